@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/validation"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const keysUsage = `usage: job-portal-api keys generate [-bits n] [-force] [flags]
+
+Writes a new RSA key pair. With auth.key_dir set the private key is added to the directory
+as <kid>.pem and becomes the active signer on the next reload. Otherwise the pair replaces
+the files at auth.private_key and auth.public_key, which -force must allow.`
+
+const userUsage = `usage: job-portal-api user <command> [flags]
+
+commands:
+  create          -name n -email e [-password p] [-role candidate|employer|admin]
+  reset-password  -email e [-password p]
+
+The password is read from standard input when -password is not given.`
+
+const companyUsage = `usage: job-portal-api company transfer-ownership -company id -to email [flags]
+
+Makes the user registered with email the owner of the company. Its previous owners stay
+on as recruiters.`
+
+// dispatch runs the action of a command group named by the first argument.
+func dispatch(group, groupUsage string, args []string, actions map[string]func(args []string) error) error {
+	if len(args) == 0 {
+		return errors.New(groupUsage)
+	}
+	action, ok := actions[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown %s command %q\n%s", group, args[0], groupUsage)
+	}
+	return action(args[1:])
+}
+
+func keys(args []string) error {
+	return dispatch("keys", keysUsage, args, map[string]func([]string) error{
+		"generate": generateKeys,
+	})
+}
+
+func user(args []string) error {
+	return dispatch("user", userUsage, args, map[string]func([]string) error{
+		"create":         createUser,
+		"reset-password": resetPassword,
+	})
+}
+
+func company(args []string) error {
+	return dispatch("company", companyUsage, args, map[string]func([]string) error{
+		"transfer-ownership": transferOwnership,
+	})
+}
+
+func generateKeys(args []string) error {
+	fs := flag.NewFlagSet("keys generate", flag.ContinueOnError)
+	bits := fs.Int("bits", auth.MinKeyBits, "size of the RSA key in bits")
+	force := fs.Bool("force", false, "overwrite the files at auth.private_key and auth.public_key")
+	cfg, err := config.Load(fs, args)
+	if err != nil {
+		return fmt.Errorf("loading config %w", err)
+	}
+
+	privatePEM, publicPEM, err := auth.GenerateKey(*bits)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Auth.KeyDir != "" {
+		kid := auth.NewKeyID(time.Now())
+		path := filepath.Join(cfg.Auth.KeyDir, kid+".pem")
+		err = writeKey(path, privatePEM, 0o600, false)
+		if err != nil {
+			return err
+		}
+		log.Info().Str("kid", kid).Str("path", path).Msg("main : Generated signing key")
+		return nil
+	}
+
+	err = writeKey(cfg.Auth.PrivateKeyPath, privatePEM, 0o600, *force)
+	if err != nil {
+		return err
+	}
+	err = writeKey(cfg.Auth.PublicKeyPath, publicPEM, 0o644, *force)
+	if err != nil {
+		return err
+	}
+	log.Info().Str("private_key", cfg.Auth.PrivateKeyPath).Str("public_key", cfg.Auth.PublicKeyPath).
+		Msg("main : Generated signing key pair")
+	return nil
+}
+
+// writeKey writes a PEM file, refusing to replace an existing one unless overwrite is set.
+func writeKey(path string, data []byte, perm os.FileMode, overwrite bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !overwrite {
+		flags |= os.O_EXCL
+	}
+	f, err := os.OpenFile(path, flags, perm)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%s already exists, pass -force to replace it", path)
+	}
+	if err != nil {
+		return fmt.Errorf("writing key %w", err)
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("writing key %w", err)
+	}
+	return nil
+}
+
+func createUser(args []string) error {
+	fs := flag.NewFlagSet("user create", flag.ContinueOnError)
+	name := fs.String("name", "", "name of the user")
+	email := fs.String("email", "", "email the user signs in with")
+	password := fs.String("password", "", "password of the user, read from standard input when empty")
+	role := fs.String("role", models.RoleCandidate, "role of the user: candidate, employer or admin")
+	cfg, err := config.Load(fs, args)
+	if err != nil {
+		return fmt.Errorf("loading config %w", err)
+	}
+
+	switch *role {
+	case models.RoleCandidate, models.RoleEmployer, models.RoleAdmin:
+	default:
+		return fmt.Errorf("role %q is not one of candidate, employer or admin", *role)
+	}
+	err = readPassword(os.Stdin, password)
+	if err != nil {
+		return err
+	}
+	// Sign ups cannot ask for the admin role, so the role is checked above and set after
+	// the rest of the user is validated.
+	nu := models.NewUser{Name: *name, Email: *email, Password: *password}
+	err = validation.Struct(nu, "Invalid user")
+	if err != nil {
+		return err
+	}
+	nu.Role = *role
+
+	s, err := openServices(cfg.DB)
+	if err != nil {
+		return err
+	}
+	u, err := s.users.CreateUser(context.Background(), nu)
+	if err != nil {
+		return err
+	}
+	log.Info().Uint("id", u.ID).Str("email", u.Email).Str("role", u.Role).Msg("main : Created user")
+	return nil
+}
+
+func resetPassword(args []string) error {
+	fs := flag.NewFlagSet("user reset-password", flag.ContinueOnError)
+	email := fs.String("email", "", "email of the user")
+	password := fs.String("password", "", "new password, read from standard input when empty")
+	cfg, err := config.Load(fs, args)
+	if err != nil {
+		return fmt.Errorf("loading config %w", err)
+	}
+	if *email == "" {
+		return errors.New("-email is required")
+	}
+	err = readPassword(os.Stdin, password)
+	if err != nil {
+		return err
+	}
+	err = validation.Struct(struct {
+		Password string `json:"password" validate:"required,password"`
+	}{*password}, "Invalid password")
+	if err != nil {
+		return err
+	}
+
+	s, err := openServices(cfg.DB)
+	if err != nil {
+		return err
+	}
+	u, err := s.users.ResetPassword(context.Background(), *email, *password)
+	if err != nil {
+		return err
+	}
+	log.Info().Uint("id", u.ID).Str("email", u.Email).Msg("main : Reset password and revoked refresh tokens")
+	return nil
+}
+
+func transferOwnership(args []string) error {
+	fs := flag.NewFlagSet("company transfer-ownership", flag.ContinueOnError)
+	companyID := fs.Uint("company", 0, "id of the company")
+	to := fs.String("to", "", "email of the new owner")
+	cfg, err := config.Load(fs, args)
+	if err != nil {
+		return fmt.Errorf("loading config %w", err)
+	}
+	if *companyID == 0 || *to == "" {
+		return errors.New(companyUsage)
+	}
+
+	s, err := openServices(cfg.DB)
+	if err != nil {
+		return err
+	}
+	c, err := s.companies.TransferCompanyOwnership(context.Background(), *companyID, *to)
+	if err != nil {
+		return err
+	}
+	log.Info().Uint("company", c.ID).Uint("owner", c.UserId).Msg("main : Transferred company ownership")
+	return nil
+}
+
+// readPassword fills an empty password with the first line of r, so that it does not have
+// to appear on the command line.
+func readPassword(r io.Reader, password *string) error {
+	if *password != "" {
+		return nil
+	}
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("reading password %w", err)
+	}
+	*password = strings.TrimRight(line, "\r\n")
+	return nil
+}
@@ -2,90 +2,146 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"errors"
+	"flag"
 	"fmt"
 	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
 	"job-portal-api/internal/database"
 	"job-portal-api/internal/handlers"
+	"job-portal-api/internal/metrics"
+	middlewares "job-portal-api/internal/middleware"
+	"job-portal-api/internal/pagination"
 	"job-portal-api/internal/repository"
+	"job-portal-api/internal/services"
+	"job-portal-api/internal/tracing"
 
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 
 	"time"
 )
 
 func main() {
-	err := startApp()
+	err := run(os.Args[1:])
 	if err != nil {
-		log.Panic().Err(err).Send()
+		log.Fatal().Err(err).Send()
 	}
 	log.Info().Msg("hello this is our app")
 }
 
-func startApp() error {
-	log.Info().Msg("main : Started : Initializing authentication support")
-	privatePEM, err := os.ReadFile("private.pem")
-	if err != nil {
-		return fmt.Errorf("reading auth private key %w", err)
+// command is a subcommand of the binary. It is run with the arguments following its name
+// and reads the same configuration as the server.
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{name: "serve", usage: "start the api, the default when no command is given", run: serve},
+	{name: "migrate", usage: "apply, roll back or list the schema migrations", run: migrate},
+	{name: "keys", usage: "generate token signing keys", run: keys},
+	{name: "user", usage: "create users and reset their passwords", run: user},
+	{name: "company", usage: "hand companies over to another owner", run: company},
+	{name: "seed", usage: "fill the database with demo data", run: seedCommand},
+}
+
+// run runs the command named by the first argument. Without one, or when the arguments
+// start with a flag, the api is served.
+func run(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return serve(args)
 	}
-	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
-	if err != nil {
-		return fmt.Errorf("parsing auth private key %w", err)
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(args[1:])
+		}
 	}
-
-	publicPEM, err := os.ReadFile("pubkey.pem")
-	if err != nil {
-		return fmt.Errorf("reading auth public key %w", err)
+	if args[0] == "help" {
+		fmt.Println(usage())
+		return nil
 	}
+	return fmt.Errorf("unknown command %q\n%s", args[0], usage())
+}
 
-	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicPEM)
-	if err != nil {
-		return fmt.Errorf("parsing auth public key %w", err)
+func usage() string {
+	var b strings.Builder
+	b.WriteString("usage: job-portal-api <command> [arguments]\n\ncommands:")
+	for _, c := range commands {
+		fmt.Fprintf(&b, "\n  %-8s %s", c.name, c.usage)
 	}
+	return b.String()
+}
 
-	a, err := auth.NewAuth(privateKey, publicKey)
+// serve starts the api and blocks until it is interrupted.
+func serve(args []string) error {
+	cfg, err := config.Load(flag.CommandLine, args)
 	if err != nil {
-		return fmt.Errorf("constructing auth %w", err)
+		return fmt.Errorf("loading config %w", err)
+	}
+	if cfg.PrintConfig {
+		return cfg.Print(os.Stdout)
 	}
 
-	log.Info().Msg("main : Started : Initializing db support")
-	db, err := database.Open()
+	log.Info().Msg("main : Started : Initializing authentication support")
+	a, err := newAuth(cfg.Auth)
 	if err != nil {
-		return fmt.Errorf("connecting to db %w", err)
+		return fmt.Errorf("constructing auth %w", err)
 	}
-	pg, err := db.DB()
-	if err != nil {
-		return fmt.Errorf("Failed to get database instance: %w ", err)
+	if cfg.Auth.KeyDir != "" {
+		watchCtx, stopWatching := context.WithCancel(context.Background())
+		defer stopWatching()
+		go a.WatchDir(watchCtx, cfg.Auth.KeyDir, cfg.Auth.KeyReloadInterval)
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
 
-	err = pg.PingContext(ctx)
+	log.Info().Msg("main : Started : Initializing tracing support")
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
 	if err != nil {
-		return fmt.Errorf("Database is not connected: %w ", err)
+		return fmt.Errorf("setting up tracing %w", err)
 	}
-	repo, err := repository.NewRepository(db)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.App.ShutdownTimeout)
+		defer cancel()
+		err := shutdownTracing(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("main : flushing spans")
+		}
+	}()
+
+	mt := metrics.New()
+
+	log.Info().Msg("main : Started : Initializing db support")
+	repos, err := openRepos(cfg.DB, mt)
 	if err != nil {
-		log.Print("err")
 		return err
 	}
 
-	err = repo.AutoMigrate()
+	pages, err := newPaginator(cfg.Page)
 	if err != nil {
-		log.Print(err)
-		return err
+		return fmt.Errorf("constructing paginator %w", err)
 	}
 
+	// Requests still running when the shutdown gives up are cancelled through their
+	// context, which aborts the queries they are waiting on.
+	requestCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+	timeouts := middlewares.QueryTimeouts{Default: cfg.DB.QueryTimeout, Routes: cfg.DB.RouteTimeouts}
 	api := http.Server{
-		Addr:         ":8081",
-		ReadTimeout:  8000 * time.Second,
-		WriteTimeout: 800 * time.Second,
-		IdleTimeout:  800 * time.Second,
-		Handler:      handlers.API(a, repo),
+		Addr:         cfg.App.Addr(),
+		ReadTimeout:  cfg.App.ReadTimeout,
+		WriteTimeout: cfg.App.WriteTimeout,
+		IdleTimeout:  cfg.App.IdleTimeout,
+		Handler:      handlers.API(a, repos, pages, timeouts, mt),
+		BaseContext:  func(net.Listener) context.Context { return requestCtx },
 	}
 
 	serverErrors := make(chan error, 1)
@@ -101,10 +157,11 @@ func startApp() error {
 		return fmt.Errorf("server error %w", err)
 	case sig := <-shutdown:
 		log.Info().Msgf("main: Start shutdown %s", sig)
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.App.ShutdownTimeout)
 		defer cancel()
 		err := api.Shutdown(ctx)
 		if err != nil {
+			cancelRequests()
 			err = api.Close()
 			return fmt.Errorf("could not stop server gracefully %w", err)
 		}
@@ -112,3 +169,130 @@ func startApp() error {
 
 	return nil
 }
+
+// openRepos opens the repositories behind the api. With a database the pending migrations are applied first when the config asks for it,
+// and the queries are timed by mt and traced.
+func openRepos(cfg config.DBConfig, mt *metrics.Metrics) (repository.Repos, error) {
+	if cfg.Driver == config.DriverMemory {
+		log.Warn().Msg("main : db.driver is memory, the data is lost when the api stops")
+		return repository.NewMemoryRepository(), nil
+	}
+
+	db, err := connect(cfg)
+	if err != nil {
+		return nil, err
+	}
+	err = mt.InstrumentDB(db)
+	if err != nil {
+		return nil, fmt.Errorf("instrumenting db %w", err)
+	}
+	err = tracing.InstrumentDB(db)
+	if err != nil {
+		return nil, fmt.Errorf("tracing db %w", err)
+	}
+	if cfg.MigrateOnStart {
+		log.Info().Msg("main : Started : Applying schema migrations")
+		m, err := database.NewMigrator(db)
+		if err != nil {
+			return nil, fmt.Errorf("loading migrations %w", err)
+		}
+		err = migrateUp(context.Background(), m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return repository.NewRepository(db)
+}
+
+// adminServices are the services the admin commands work through.
+type adminServices struct {
+	users     services.UserService
+	companies services.CompanyService
+}
+
+// openServices connects to the database and builds the services the admin commands work
+// through, wired the same way as the ones behind the api.
+func openServices(cfg config.DBConfig) (adminServices, error) {
+	db, err := connect(cfg)
+	if err != nil {
+		return adminServices{}, err
+	}
+	repos, err := repository.NewRepository(db)
+	if err != nil {
+		return adminServices{}, err
+	}
+	users, err := services.NewUserStore(repos)
+	if err != nil {
+		return adminServices{}, err
+	}
+	companies, err := services.NewCompanyStore(repos, repos, repos)
+	if err != nil {
+		return adminServices{}, err
+	}
+	return adminServices{users: users, companies: companies}, nil
+}
+
+// connect opens the database and checks that it answers.
+func connect(cfg config.DBConfig) (*gorm.DB, error) {
+	if cfg.Driver == config.DriverMemory {
+		return nil, errors.New("db.driver is memory, there is no database to work on")
+	}
+	db, err := database.Open(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to db %w", err)
+	}
+	pg, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get database instance: %w ", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	err = pg.PingContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Database is not connected: %w ", err)
+	}
+	return db, nil
+}
+
+// newPaginator signs cursors with the configured secret, or with a random one when none is set.
+func newPaginator(cfg config.PageConfig) (pagination.Paginator, error) {
+	secret := []byte(cfg.CursorSecret)
+	if len(secret) == 0 {
+		log.Warn().Msg("main : page.cursor_secret is not set, cursors will not survive a restart")
+		secret = make([]byte, 32)
+		_, err := rand.Read(secret)
+		if err != nil {
+			return pagination.Paginator{}, fmt.Errorf("generating cursor secret %w", err)
+		}
+	}
+	return pagination.New(secret, cfg.DefaultSize, cfg.MaxSize)
+}
+
+// newAuth loads the signing keys, either as a ring from the key directory or as a single pair.
+func newAuth(cfg config.AuthConfig) (*auth.Auth, error) {
+	if cfg.KeyDir != "" {
+		return auth.NewAuthFromDir(cfg.KeyDir, cfg.KeyRetention)
+	}
+
+	privatePEM, err := os.ReadFile(cfg.PrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading auth private key %w", err)
+	}
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+	if err != nil {
+		return nil, fmt.Errorf("parsing auth private key %w", err)
+	}
+
+	publicPEM, err := os.ReadFile(cfg.PublicKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading auth public key %w", err)
+	}
+
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parsing auth public key %w", err)
+	}
+
+	return auth.NewAuth(privateKey, publicKey)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/database"
+	"os"
+	"os/signal"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const migrateUsage = `usage: job-portal-api migrate <command> [flags]
+
+commands:
+  up        apply every pending migration
+  down [n]  roll back the last n applied migrations, 1 by default
+  status    list the migrations and whether they are applied
+  redo      roll back the last applied migration and apply it again`
+
+// migrate runs the migrate subcommand. The config flags of the api follow the command.
+func migrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	command, args := args[0], args[1:]
+	switch command {
+	case "up", "down", "status", "redo":
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", command, migrateUsage)
+	}
+
+	fs := flag.NewFlagSet("migrate "+command, flag.ContinueOnError)
+	cfg, err := config.Load(fs, args)
+	if err != nil {
+		return fmt.Errorf("loading config %w", err)
+	}
+	if cfg.PrintConfig {
+		return cfg.Print(os.Stdout)
+	}
+
+	steps := 1
+	if command == "down" && fs.NArg() > 0 {
+		steps, err = strconv.Atoi(fs.Arg(0))
+		if err != nil || steps < 1 {
+			return fmt.Errorf("migrate down: %q is not a positive number of steps", fs.Arg(0))
+		}
+	}
+
+	db, err := connect(cfg.DB)
+	if err != nil {
+		return err
+	}
+	m, err := database.NewMigrator(db)
+	if err != nil {
+		return fmt.Errorf("loading migrations %w", err)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	switch command {
+	case "up":
+		return migrateUp(ctx, m)
+	case "down":
+		done, err := m.Down(ctx, steps)
+		for _, mig := range done {
+			log.Info().Str("migration", mig.String()).Msg("main : Rolled back migration")
+		}
+		return err
+	case "redo":
+		mig, err := m.Redo(ctx)
+		if err != nil {
+			return err
+		}
+		log.Info().Str("migration", mig.String()).Msg("main : Redid migration")
+		return nil
+	}
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	return printStatus(os.Stdout, status)
+}
+
+// migrateUp applies the pending migrations of m.
+func migrateUp(ctx context.Context, m *database.Migrator) error {
+	done, err := m.Up(ctx)
+	for _, mig := range done {
+		log.Info().Str("migration", mig.String()).Msg("main : Applied migration")
+	}
+	if err != nil {
+		return err
+	}
+	if len(done) == 0 {
+		log.Info().Msg("main : Schema is up to date")
+	}
+	return nil
+}
+
+func printStatus(w io.Writer, status []database.MigrationStatus) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, s := range status {
+		at := ""
+		if !s.AppliedAt.IsZero() {
+			at = s.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, s.State, at)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/seed"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// seedCommand fills an empty database with synthetic data, or writes the same data as json
+// when -dump is given.
+func seedCommand(args []string) error {
+	opts := seed.DefaultOptions()
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	fs.Int64Var(&opts.Seed, "seed", opts.Seed, "seed the data is derived from, the same seed gives the same data")
+	fs.IntVar(&opts.Companies, "companies", opts.Companies, "number of companies, each with its own employer")
+	fs.IntVar(&opts.Candidates, "candidates", opts.Candidates, "number of candidates")
+	fs.IntVar(&opts.Jobs, "jobs", opts.Jobs, "number of jobs")
+	fs.IntVar(&opts.Applications, "applications", opts.Applications, "number of applications")
+	fs.IntVar(&opts.BatchSize, "batch-size", opts.BatchSize, "number of rows sent in a single insert")
+	dump := fs.String("dump", "", "write the data as json to this file, - for standard output, instead of inserting it")
+	cfg, err := config.Load(fs, args)
+	if err != nil {
+		return fmt.Errorf("loading config %w", err)
+	}
+
+	if *dump != "" {
+		return dumpFixtures(*dump, opts)
+	}
+
+	db, err := connect(cfg.DB)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	start := time.Now()
+	err = seed.Seed(ctx, db, opts)
+	if err != nil {
+		return err
+	}
+	log.Info().
+		Int("companies", opts.Companies).Int("candidates", opts.Candidates).
+		Int("jobs", opts.Jobs).Int("applications", opts.Applications).
+		Dur("took", time.Since(start)).
+		Msgf("main : Seeded the database, users sign in with the password %s", seed.Password)
+	return nil
+}
+
+func dumpFixtures(path string, opts seed.Options) error {
+	if path == "-" {
+		return seed.Dump(os.Stdout, opts)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating fixture file %w", err)
+	}
+	err = seed.Dump(file, opts)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("writing fixture file %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Package apperr holds the kinds of errors shared by every layer of the api and renders
+// them as RFC 7807 problem details.
+package apperr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The kinds of errors callers can act on. Errors are matched against them with errors.Is,
+// so a layer adds context with fmt.Errorf("job %d: %w", id, apperr.ErrNotFound).
+var (
+	// ErrNotFound is returned when a resource the request refers to does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned when a request clashes with a resource that already exists.
+	ErrConflict = errors.New("conflict")
+	// ErrForbidden is returned when the caller is not allowed to act on a resource.
+	ErrForbidden = errors.New("forbidden")
+	// ErrValidation is returned when a request breaks a rule, either of its own shape or one
+	// that needs stored data to check.
+	ErrValidation = errors.New("validation failed")
+	// ErrUnauthorized is returned when the credentials presented by the caller are not valid.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrTimeout is returned when the request ran out of the time it is given, usually
+	// while waiting on the database.
+	ErrTimeout = errors.New("timed out")
+)
+
+// Error is an error of a known kind whose cause must not be shown to clients, such as an
+// error of the database. Detail is shown instead, along with the Fields at fault if any.
+type Error struct {
+	Kind   error
+	Detail string
+	Fields []FieldError
+	Cause  error
+}
+
+// FieldError tells what is wrong with one field of a request. Field is the path of the
+// field as the client sent it, such as stages[1].name.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Wrap returns an error of kind caused by cause, described to clients by detail.
+func Wrap(kind error, cause error, detail string) error {
+	return &Error{Kind: kind, Detail: detail, Cause: cause}
+}
+
+// Invalid returns a validation error listing the fields at fault.
+func Invalid(detail string, fields ...FieldError) error {
+	return &Error{Kind: ErrValidation, Detail: detail, Fields: fields}
+}
+
+func (e *Error) Error() string {
+	msg := e.Detail
+	for _, f := range e.Fields {
+		msg += fmt.Sprintf("; %s %s", f.Field, f.Message)
+	}
+	if e.Cause == nil {
+		return msg
+	}
+	return fmt.Sprintf("%s: %v", msg, e.Cause)
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Cause == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Cause}
+}
+
+// Kind returns the kind of err, or nil when it is not of a known kind.
+func Kind(err error) error {
+	for _, kind := range []error{ErrNotFound, ErrConflict, ErrForbidden, ErrValidation, ErrUnauthorized, ErrTimeout} {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
+
+// Fields returns the fields at fault in err, if it lists any.
+func Fields(err error) []FieldError {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Fields
+	}
+	return nil
+}
+
+// Detail describes err to clients. An Error is described by its Detail, other errors of a
+// known kind are built by this api and are described by their own text.
+func Detail(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Detail
+	}
+	return err.Error()
+}
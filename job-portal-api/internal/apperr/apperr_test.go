@@ -0,0 +1,83 @@
+package apperr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrap(t *testing.T) {
+	cause := errors.New("duplicate key value violates unique constraint")
+	err := fmt.Errorf("creating user: %w", Wrap(ErrConflict, cause, "email already registered"))
+
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, ErrConflict, Kind(err))
+	assert.Equal(t, "email already registered", Detail(err))
+	assert.Equal(t, "creating user: email already registered: duplicate key value violates unique constraint", err.Error())
+}
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{fmt.Errorf("job 5: %w", ErrNotFound), http.StatusNotFound},
+		{Wrap(ErrConflict, nil, "already applied"), http.StatusConflict},
+		{ErrForbidden, http.StatusForbidden},
+		{fmt.Errorf("salary: %w", ErrValidation), http.StatusBadRequest},
+		{ErrUnauthorized, http.StatusUnauthorized},
+		{Wrap(ErrTimeout, context.DeadlineExceeded, "took too long"), http.StatusServiceUnavailable},
+		{errors.New("connection refused"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Status(tt.err), tt.err.Error())
+	}
+}
+
+func TestAbortWithError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "known kind",
+			err:      fmt.Errorf("job 5: %w", ErrNotFound),
+			wantCode: http.StatusNotFound,
+			wantBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"job 5: not found","trace_id":"123"}`,
+		},
+		{
+			name:     "detail repeating the title",
+			err:      ErrForbidden,
+			wantCode: http.StatusForbidden,
+			wantBody: `{"type":"about:blank","title":"Forbidden","status":403,"trace_id":"123"}`,
+		},
+		{
+			name:     "unknown error",
+			err:      errors.New("pq: connection refused"),
+			wantCode: http.StatusInternalServerError,
+			wantBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"Failed to fetch job","trace_id":"123"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			AbortWithError(c, "123", tt.err, "Failed to fetch job")
+
+			assert.True(t, c.IsAborted())
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, ContentType, rr.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantBody, rr.Body.String())
+		})
+	}
+}
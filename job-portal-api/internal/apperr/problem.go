@@ -0,0 +1,82 @@
+package apperr
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType is the media type of problem details.
+const ContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object. TraceID and Errors are extension members,
+// the first names the request in the logs and the second lists the fields of the request
+// that are not valid.
+type Problem struct {
+	Type    string       `json:"type"`
+	Title   string       `json:"title"`
+	Status  int          `json:"status"`
+	Detail  string       `json:"detail,omitempty"`
+	TraceID string       `json:"trace_id,omitempty"`
+	Errors  []FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem returns the problem for a response of status. Detail is left out when it only
+// repeats the title.
+func NewProblem(status int, detail, traceID string) Problem {
+	p := Problem{
+		Type:    "about:blank",
+		Title:   http.StatusText(status),
+		Status:  status,
+		Detail:  detail,
+		TraceID: traceID,
+	}
+	if strings.EqualFold(p.Detail, p.Title) {
+		p.Detail = ""
+	}
+	return p
+}
+
+// Abort ends the request with a problem response.
+func Abort(c *gin.Context, traceID string, status int, detail string) {
+	AbortWithProblem(c, NewProblem(status, detail, traceID))
+}
+
+// AbortWithProblem ends the request with p.
+func AbortWithProblem(c *gin.Context, p Problem) {
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
+
+// Status returns the http status of an error, 500 for errors of no known kind.
+func Status(err error) int {
+	switch Kind(err) {
+	case ErrNotFound:
+		return http.StatusNotFound
+	case ErrConflict:
+		return http.StatusConflict
+	case ErrForbidden:
+		return http.StatusForbidden
+	case ErrValidation:
+		return http.StatusBadRequest
+	case ErrUnauthorized:
+		return http.StatusUnauthorized
+	case ErrTimeout:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// AbortWithError ends the request with the problem matching the kind of err. Errors of no
+// known kind are described by fallback so their text never reaches clients.
+func AbortWithError(c *gin.Context, traceID string, err error, fallback string) {
+	status := Status(err)
+	if status == http.StatusInternalServerError {
+		Abort(c, traceID, status, fallback)
+		return
+	}
+	p := NewProblem(status, Detail(err), traceID)
+	p.Errors = Fields(err)
+	AbortWithProblem(c, p)
+}
@@ -4,6 +4,9 @@ import (
 	"crypto/rsa"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
+
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -11,40 +14,97 @@ type ctxKey int
 
 const Key ctxKey = 1
 
+// Claims are the claims carried by the tokens issued by this api.
+type Claims struct {
+	jwt.RegisteredClaims
+	Role string `json:"role"`
+	// CompanyRoles maps the id of each company the user belongs to onto their role in it.
+	CompanyRoles map[uint]string `json:"company_roles,omitempty"`
+}
+
+// Auth signs and validates tokens with a ring of RSA keys identified by their kid. One key
+// is the active signer, the others are only used to validate tokens they signed earlier.
 type Auth struct {
+	mu     sync.RWMutex
+	keys   map[string]*signingKey
+	active string
+
+	// retention is how long a key stays valid for validation after it stopped being the signer.
+	retention time.Duration
+	now       func() time.Time
+}
+
+type signingKey struct {
+	kid        string
 	privateKey *rsa.PrivateKey
 	publicKey  *rsa.PublicKey
+	// retiredAt is zero while the key is the active signer or was never one.
+	retiredAt time.Time
 }
 
 func NewAuth(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey) (*Auth, error) {
 	if privateKey == nil || publicKey == nil {
 		return nil, errors.New("private/public key cannot be nil")
 	}
+	kid := Thumbprint(publicKey)
 	return &Auth{
-		privateKey: privateKey,
-		publicKey:  publicKey,
+		keys: map[string]*signingKey{
+			kid: {kid: kid, privateKey: privateKey, publicKey: publicKey},
+		},
+		active: kid,
+		now:    time.Now,
 	}, nil
 }
 
-func (a *Auth) GenerateToken(claims jwt.RegisteredClaims) (string, error) {
+func (a *Auth) GenerateToken(claims Claims) (string, error) {
+	a.mu.RLock()
+	k := a.keys[a.active]
+	a.mu.RUnlock()
+	if k == nil || k.privateKey == nil {
+		return "", errors.New("no active signing key")
+	}
+
 	tkn := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	tokenStr, err := tkn.SignedString(a.privateKey)
+	tkn.Header["kid"] = k.kid
+	tokenStr, err := tkn.SignedString(k.privateKey)
 	if err != nil {
 		return "", fmt.Errorf("error signing token: %w", err)
 	}
 	return tokenStr, nil
 }
 
-func (a *Auth) ValidateToken(token string) (jwt.RegisteredClaims, error) {
-	var c jwt.RegisteredClaims
-	tkn, err := jwt.ParseWithClaims(token, &c, func(token *jwt.Token) (interface{}, error) {
-		return a.publicKey, nil
-	})
+func (a *Auth) ValidateToken(token string) (Claims, error) {
+	var c Claims
+	tkn, err := jwt.ParseWithClaims(token, &c, a.keyFunc, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}))
 	if err != nil {
-		return jwt.RegisteredClaims{}, fmt.Errorf("error parsing token: %w", err)
+		return Claims{}, fmt.Errorf("error parsing token: %w", err)
 	}
 	if !tkn.Valid {
-		return jwt.RegisteredClaims{}, errors.New("invalid token")
+		return Claims{}, errors.New("invalid token")
 	}
 	return c, nil
 }
+
+// keyFunc picks the public key named by the kid header. Tokens without a kid were issued
+// before keys had one and are checked against the active key.
+func (a *Auth) keyFunc(token *jwt.Token) (interface{}, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		kid = a.active
+	}
+	k, ok := a.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if a.expired(k) {
+		return nil, fmt.Errorf("signing key %q has expired", kid)
+	}
+	return k.publicKey, nil
+}
+
+func (a *Auth) expired(k *signingKey) bool {
+	return !k.retiredAt.IsZero() && a.now().After(k.retiredAt.Add(a.retention))
+}
@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+func writePrivateKey(t *testing.T, dir, kid string, key *rsa.PrivateKey) {
+	t.Helper()
+	b := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, kid+".pem"), b, 0o600))
+}
+
+func testClaims() Claims {
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti",
+			Subject:   "1",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Role: "candidate",
+	}
+}
+
+func TestAuth_SingleKey(t *testing.T) {
+	key := newKey(t)
+	a, err := NewAuth(key, &key.PublicKey)
+	require.NoError(t, err)
+
+	tkn, err := a.GenerateToken(testClaims())
+	require.NoError(t, err)
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(tkn, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, Thumbprint(&key.PublicKey), parsed.Header["kid"])
+
+	claims, err := a.ValidateToken(tkn)
+	require.NoError(t, err)
+	assert.Equal(t, "candidate", claims.Role)
+
+	jwks := a.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "AQAB", jwks.Keys[0].E)
+
+	// Tokens signed before keys had a kid are checked against the active key.
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodRS256, testClaims()).SignedString(key)
+	require.NoError(t, err)
+	_, err = a.ValidateToken(legacy)
+	assert.NoError(t, err)
+}
+
+func TestAuth_KeyRotation(t *testing.T) {
+	dir := t.TempDir()
+	writePrivateKey(t, dir, "2026-01", newKey(t))
+
+	a, err := NewAuthFromDir(dir, time.Hour)
+	require.NoError(t, err)
+	now := time.Now()
+	a.now = func() time.Time { return now }
+
+	oldTkn, err := a.GenerateToken(testClaims())
+	require.NoError(t, err)
+
+	writePrivateKey(t, dir, "2026-02", newKey(t))
+	require.NoError(t, a.LoadDir(dir))
+
+	newTkn, err := a.GenerateToken(testClaims())
+	require.NoError(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(newTkn, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-02", parsed.Header["kid"])
+
+	// The retired key still validates until its retention period ends.
+	_, err = a.ValidateToken(oldTkn)
+	assert.NoError(t, err)
+	assert.Len(t, a.JWKS().Keys, 2)
+
+	now = now.Add(2 * time.Hour)
+	require.NoError(t, a.LoadDir(dir))
+	_, err = a.ValidateToken(oldTkn)
+	assert.Error(t, err)
+	_, err = a.ValidateToken(newTkn)
+	assert.NoError(t, err)
+	require.Len(t, a.JWKS().Keys, 1)
+	assert.Equal(t, "2026-02", a.JWKS().Keys[0].Kid)
+}
+
+// signWith signs claims with key, naming it kid.
+func signWith(t *testing.T, key *rsa.PrivateKey, kid string) string {
+	t.Helper()
+	tkn := jwt.NewWithClaims(jwt.SigningMethodRS256, testClaims())
+	tkn.Header["kid"] = kid
+	s, err := tkn.SignedString(key)
+	require.NoError(t, err)
+	return s
+}
+
+func TestNewAuthFromDir_RetentionSurvivesRestart(t *testing.T) {
+	now := time.Now()
+	t.Run("kid dates", func(t *testing.T) {
+		dir := t.TempDir()
+		older, old, current := NewKeyID(now.Add(-5*time.Hour)), NewKeyID(now.Add(-2*time.Hour)), NewKeyID(now.Add(-30*time.Minute))
+		olderKey, oldKey := newKey(t), newKey(t)
+		writePrivateKey(t, dir, older, olderKey)
+		writePrivateKey(t, dir, old, oldKey)
+		writePrivateKey(t, dir, current, newKey(t))
+
+		a, err := NewAuthFromDir(dir, time.Hour)
+		require.NoError(t, err)
+		_, err = a.ValidateToken(signWith(t, olderKey, older))
+		assert.Error(t, err, "retired two hours ago, when the next key took over")
+		_, err = a.ValidateToken(signWith(t, oldKey, old))
+		assert.NoError(t, err, "retired half an hour ago")
+		assert.Len(t, a.JWKS().Keys, 2)
+	})
+	t.Run("file times", func(t *testing.T) {
+		dir := t.TempDir()
+		oldKey := newKey(t)
+		writePrivateKey(t, dir, "2026-01", oldKey)
+		writePrivateKey(t, dir, "2026-02", newKey(t))
+		written := now.Add(-2 * time.Hour)
+		require.NoError(t, os.Chtimes(filepath.Join(dir, "2026-02.pem"), written, written))
+
+		a, err := NewAuthFromDir(dir, time.Hour)
+		require.NoError(t, err)
+		_, err = a.ValidateToken(signWith(t, oldKey, "2026-01"))
+		assert.Error(t, err)
+	})
+}
+
+func TestAuth_UnknownKid(t *testing.T) {
+	key := newKey(t)
+	a, err := NewAuth(key, &key.PublicKey)
+	require.NoError(t, err)
+
+	other := jwt.NewWithClaims(jwt.SigningMethodRS256, testClaims())
+	other.Header["kid"] = "someone-else"
+	tkn, err := other.SignedString(newKey(t))
+	require.NoError(t, err)
+
+	_, err = a.ValidateToken(tkn)
+	assert.Error(t, err)
+}
+
+func TestNewAuthFromDir_NoPrivateKey(t *testing.T) {
+	_, err := NewAuthFromDir(t.TempDir(), time.Hour)
+	assert.Error(t, err)
+}
+
+func TestGenerateKey(t *testing.T) {
+	privatePEM, publicPEM, err := GenerateKey(MinKeyBits)
+	require.NoError(t, err)
+
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+	require.NoError(t, err)
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicPEM)
+	require.NoError(t, err)
+	assert.True(t, privateKey.PublicKey.Equal(publicKey))
+
+	_, _, err = GenerateKey(1024)
+	assert.Error(t, err)
+}
+
+func TestNewKeyID(t *testing.T) {
+	first := NewKeyID(time.Date(2026, 9, 30, 23, 0, 0, 0, time.UTC))
+	second := NewKeyID(time.Date(2026, 10, 1, 3, 0, 0, 0, time.FixedZone("CEST", 2*60*60)))
+	assert.Equal(t, "20260930T230000Z", first)
+	assert.Less(t, first, second)
+}
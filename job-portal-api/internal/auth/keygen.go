@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+)
+
+// MinKeyBits is the smallest RSA key GenerateKey agrees to create.
+const MinKeyBits = 2048
+
+// GenerateKey creates an RSA key pair and returns both halves PEM encoded, ready to be
+// written where NewAuth or LoadDir read them.
+func GenerateKey(bits int) (privatePEM, publicPEM []byte, err error) {
+	if bits < MinKeyBits {
+		return nil, nil, fmt.Errorf("rsa keys must have at least %d bits, got %d", MinKeyBits, bits)
+	}
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generating rsa key %w", err)
+	}
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding public key %w", err)
+	}
+	privatePEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	publicPEM = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub})
+	return privatePEM, publicPEM, nil
+}
+
+// keyIDLayout is the time layout of the ids NewKeyID gives.
+const keyIDLayout = "20060102T150405Z"
+
+// NewKeyID names a key created at t. Later keys get greater ids, so a key written to the key
+// directory under its id becomes the active signer, see LoadDir.
+func NewKeyID(t time.Time) string {
+	return t.UTC().Format(keyIDLayout)
+}
@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// NewAuthFromDir builds a key ring from the PEM files in dir, see LoadDir. Keys that stop
+// being the active signer are still accepted for validation for the retention period.
+func NewAuthFromDir(dir string, retention time.Duration) (*Auth, error) {
+	a := &Auth{
+		keys:      map[string]*signingKey{},
+		retention: retention,
+		now:       time.Now,
+	}
+	err := a.LoadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// LoadDir replaces the keys of the ring with the ones found in dir. Every <kid>.pem file
+// holds either an RSA private key, which can sign, or an RSA public key, which can only
+// validate. The private key with the greatest kid becomes the active signer, so naming
+// keys after their creation date rotates them in order.
+//
+// Every other private key, including the signer before the reload, keeps validating tokens
+// until its retention period ends. The period starts when the key after it took over, see
+// handoverTime, so restarting does not extend it. Public keys validate for as long as they are in dir and
+// keys removed from dir are dropped straight away.
+func (a *Auth) LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return fmt.Errorf("listing keys in %s: %w", dir, err)
+	}
+
+	loaded := make(map[string]*signingKey, len(paths))
+	modTimes := make(map[string]time.Time, len(paths))
+	for _, p := range paths {
+		kid := strings.TrimSuffix(filepath.Base(p), ".pem")
+		k, err := readKey(p)
+		if err != nil {
+			return err
+		}
+		k.kid = kid
+		loaded[kid] = k
+		if fi, err := os.Stat(p); err == nil {
+			modTimes[kid] = fi.ModTime()
+		}
+	}
+
+	var signers []string
+	for kid, k := range loaded {
+		if k.privateKey != nil {
+			signers = append(signers, kid)
+		}
+	}
+	if len(signers) == 0 {
+		return fmt.Errorf("no private key found in %s", dir)
+	}
+	sort.Strings(signers)
+	active := signers[len(signers)-1]
+	successors := make(map[string]string, len(signers))
+	for i := 1; i < len(signers); i++ {
+		successors[signers[i-1]] = signers[i]
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	for kid, k := range loaded {
+		if kid == active {
+			continue
+		}
+		// Retired keys keep their retirement time, expired ones included, so they stay in the
+		// ring and a later reload does not revive them.
+		if old, ok := a.keys[kid]; ok && !old.retiredAt.IsZero() {
+			k.retiredAt = old.retiredAt
+			continue
+		}
+		// Public keys never signed here and validate for as long as they are in dir.
+		if k.privateKey == nil {
+			continue
+		}
+		if _, ok := a.keys[kid]; ok {
+			// The previous signer, retired by this reload.
+			k.retiredAt = now
+			continue
+		}
+		// A key seen for the first time, as after a restart, retired when the key after it
+		// took over rather than now, or its retention would start over on every restart.
+		next := successors[kid]
+		k.retiredAt = handoverTime(next, modTimes[next], now)
+	}
+	if a.active != active {
+		log.Info().Str("kid", active).Str("previous", a.active).Msg("auth: active signing key changed")
+	}
+	a.keys = loaded
+	a.active = active
+	return nil
+}
+
+// handoverTime is when the key named kid became the signer: the date of its kid when it was
+// named by NewKeyID, otherwise the time its file was written. Times after now are taken as
+// now.
+func handoverTime(kid string, modTime time.Time, now time.Time) time.Time {
+	t, err := time.Parse(keyIDLayout, kid)
+	if err != nil {
+		t = modTime
+	}
+	if t.IsZero() || t.After(now) {
+		return now
+	}
+	return t
+}
+
+// WatchDir reloads the keys from dir every interval until ctx is done. A failed reload
+// keeps the current keys.
+func (a *Auth) WatchDir(ctx context.Context, dir string, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			err := a.LoadDir(dir)
+			if err != nil {
+				log.Error().Err(err).Str("dir", dir).Msg("auth: reloading signing keys")
+			}
+		}
+	}
+}
+
+func readKey(path string) (*signingKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading key %w", err)
+	}
+	if privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		return &signingKey{privateKey: privateKey, publicKey: &privateKey.PublicKey}, nil
+	}
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing key %s: %w", path, err)
+	}
+	return &signingKey{publicKey: publicKey}, nil
+}
+
+// JWK is the JSON Web Key representation of an RSA public key.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public half of every key that can still validate tokens.
+func (a *Auth) JWKS() JWKSet {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	set := JWKSet{Keys: make([]JWK, 0, len(a.keys))}
+	for _, k := range a.keys {
+		if a.expired(k) {
+			continue
+		}
+		set.Keys = append(set.Keys, JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: jwt.SigningMethodRS256.Alg(),
+			Kid: k.kid,
+			N:   base64.RawURLEncoding.EncodeToString(k.publicKey.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.publicKey.E)).Bytes()),
+		})
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
+// Thumbprint returns the RFC 7638 thumbprint of the key, used as the kid of keys that are not named.
+func Thumbprint(publicKey *rsa.PublicKey) string {
+	// The members must be in lexicographic order with no whitespace.
+	members := fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`,
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()))
+	sum := sha256.Sum256([]byte(members))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,441 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting needed to start the job portal api.
+type Config struct {
+	App     AppConfig
+	DB      DBConfig
+	Auth    AuthConfig
+	Page    PageConfig
+	Tracing TracingConfig
+
+	// PrintConfig is set by the --print-config flag. It is never read from a file or the environment.
+	PrintConfig bool
+}
+
+type AppConfig struct {
+	Host            string
+	Port            int
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	ShutdownTimeout time.Duration
+}
+
+// Database drivers.
+const (
+	DriverPostgres = "postgres"
+	// DriverSQLite keeps the data in a single file, for deployments running one instance.
+	DriverSQLite = "sqlite"
+	// DriverMemory keeps the data in memory, it is lost when the api stops. It is meant for
+	// demos and tests and only serves the api, the other commands need a database.
+	DriverMemory = "memory"
+)
+
+type DBConfig struct {
+	Driver   string
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Name     string
+	SSLMode  string
+	TimeZone string
+	// Path is the file of the sqlite database.
+	Path string
+	// MigrateOnStart applies the pending schema migrations before the api starts serving.
+	MigrateOnStart bool
+	// QueryTimeout bounds the time the queries of a request may take, zero leaves them
+	// unbounded. RouteTimeouts overrides it for the routes it names by method and path
+	// pattern, as in "GET /api/jobs/search".
+	QueryTimeout  time.Duration
+	RouteTimeouts map[string]time.Duration
+}
+
+type AuthConfig struct {
+	PrivateKeyPath string
+	PublicKeyPath  string
+	// KeyDir, when set, replaces the single key pair with a ring of keys reloaded from the directory.
+	KeyDir            string
+	KeyReloadInterval time.Duration
+	KeyRetention      time.Duration
+}
+
+type PageConfig struct {
+	DefaultSize int
+	MaxSize     int
+	// CursorSecret signs the cursors of paged lists. When empty a random secret is used, so
+	// cursors stop working when the api restarts.
+	CursorSecret string
+}
+
+type TracingConfig struct {
+	// Endpoint is the url of the OTLP/HTTP collector the spans are sent to, as
+	// "http://localhost:4318". When empty the spans are not exported, but requests still get
+	// trace ids and pass on the trace context they came with.
+	Endpoint    string
+	ServiceName string
+}
+
+// Addr returns the address the http server listens on.
+func (a AppConfig) Addr() string {
+	return fmt.Sprintf("%s:%d", a.Host, a.Port)
+}
+
+// DSN returns the postgres connection string for the configured database.
+func (d DBConfig) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s TimeZone=%s",
+		dsnValue(d.Host), dsnValue(d.User), dsnValue(d.Password), dsnValue(d.Name), d.Port,
+		dsnValue(d.SSLMode), dsnValue(d.TimeZone))
+}
+
+// dsnValueEscaper escapes the characters libpq reads specially in a quoted value.
+var dsnValueEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+// dsnValue quotes v as a value of a keyword/value connection string, so spaces, quotes and
+// backslashes in passwords and the like are read as they are.
+func dsnValue(v string) string {
+	return "'" + dsnValueEscaper.Replace(v) + "'"
+}
+
+// Default returns the configuration used for local development.
+func Default() Config {
+	return Config{
+		App: AppConfig{
+			Port:            8081,
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    10 * time.Second,
+			IdleTimeout:     120 * time.Second,
+			ShutdownTimeout: 10 * time.Second,
+		},
+		DB: DBConfig{
+			Driver:         DriverPostgres,
+			Host:           "localhost",
+			Port:           5432,
+			User:           "postgres",
+			Name:           "postgres",
+			SSLMode:        "disable",
+			TimeZone:       "Asia/Shanghai",
+			Path:           "job-portal.db",
+			MigrateOnStart: true,
+			QueryTimeout:   5 * time.Second,
+		},
+		Auth: AuthConfig{
+			PrivateKeyPath:    "private.pem",
+			PublicKeyPath:     "pubkey.pem",
+			KeyReloadInterval: time.Minute,
+			KeyRetention:      2 * time.Hour,
+		},
+		Page: PageConfig{
+			DefaultSize: 20,
+			MaxSize:     100,
+		},
+		Tracing: TracingConfig{
+			ServiceName: "job-portal-api",
+		},
+	}
+}
+
+// Load builds the configuration from, in increasing order of precedence, the defaults,
+// an optional YAML or TOML file, environment variables and command line flags.
+//
+// Every setting has a dotted key such as "db.host". The same key is used in the file,
+// as the flag name (-db.host) and, upper-cased with dots replaced by underscores, as the
+// environment variable (DB_HOST). The file is chosen with -config or CONFIG_FILE.
+//
+// The config flags are registered on fs, so callers may add flags of their own before
+// calling Load and read the remaining arguments from fs.Args afterwards.
+func Load(fs *flag.FlagSet, args []string) (Config, error) {
+	cfg := Default()
+	bs := cfg.bindings()
+
+	path := os.Getenv("CONFIG_FILE")
+	if p, ok := lookupFlag(args, "config"); ok {
+		path = p
+	}
+	if path != "" {
+		err := loadFile(path, bs)
+		if err != nil {
+			return Config{}, err
+		}
+	}
+
+	for _, b := range bs {
+		v, ok := os.LookupEnv(b.env())
+		if !ok {
+			continue
+		}
+		err := b.value.Set(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("env %s: %w", b.env(), err)
+		}
+	}
+
+	fs.String("config", path, "path to a YAML or TOML config file")
+	fs.BoolVar(&cfg.PrintConfig, "print-config", false, "print the effective configuration with secrets redacted and exit")
+	for _, b := range bs {
+		fs.Var(b.value, b.key, b.usage)
+	}
+	err := fs.Parse(args)
+	if err != nil {
+		return Config{}, err
+	}
+
+	err = cfg.Validate()
+	if err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Validate reports every invalid setting in c.
+func (c Config) Validate() error {
+	var errs []error
+	if c.App.Port < 1 || c.App.Port > 65535 {
+		errs = append(errs, fmt.Errorf("app.port %d is out of range", c.App.Port))
+	}
+	timeouts := map[string]time.Duration{
+		"app.read_timeout":     c.App.ReadTimeout,
+		"app.write_timeout":    c.App.WriteTimeout,
+		"app.idle_timeout":     c.App.IdleTimeout,
+		"app.shutdown_timeout": c.App.ShutdownTimeout,
+	}
+	for k, d := range timeouts {
+		if d <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive", k))
+		}
+	}
+	switch c.DB.Driver {
+	case DriverPostgres:
+		errs = append(errs, c.DB.validatePostgres()...)
+	case DriverSQLite:
+		if c.DB.Path == "" {
+			errs = append(errs, errors.New("db.path is required"))
+		}
+	case DriverMemory:
+	default:
+		errs = append(errs, fmt.Errorf("db.driver %q is not supported", c.DB.Driver))
+	}
+	if c.DB.QueryTimeout < 0 {
+		errs = append(errs, errors.New("db.query_timeout must not be negative"))
+	}
+	for route, d := range c.DB.RouteTimeouts {
+		method, path, ok := strings.Cut(route, " ")
+		if !ok || method == "" || !strings.HasPrefix(path, "/") {
+			errs = append(errs, fmt.Errorf("db.route_timeouts: route %q is not \"METHOD /path\"", route))
+		}
+		if d < 0 {
+			errs = append(errs, fmt.Errorf("db.route_timeouts: timeout of %s must not be negative", route))
+		}
+	}
+	if c.Auth.KeyDir == "" && c.Auth.PrivateKeyPath == "" {
+		errs = append(errs, errors.New("auth.private_key is required"))
+	}
+	if c.Auth.KeyDir == "" && c.Auth.PublicKeyPath == "" {
+		errs = append(errs, errors.New("auth.public_key is required"))
+	}
+	if c.Auth.KeyDir != "" && c.Auth.KeyReloadInterval <= 0 {
+		errs = append(errs, errors.New("auth.key_reload_interval must be positive"))
+	}
+	if c.Auth.KeyRetention < 0 {
+		errs = append(errs, errors.New("auth.key_retention must not be negative"))
+	}
+	if c.Page.DefaultSize < 1 {
+		errs = append(errs, errors.New("page.default_size must be positive"))
+	}
+	if c.Page.MaxSize < c.Page.DefaultSize {
+		errs = append(errs, fmt.Errorf("page.max_size %d is below page.default_size %d", c.Page.MaxSize, c.Page.DefaultSize))
+	}
+	if c.Tracing.Endpoint != "" {
+		u, err := url.Parse(c.Tracing.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("tracing.endpoint %q is not an http or https url", c.Tracing.Endpoint))
+		}
+	}
+	if c.Tracing.ServiceName == "" {
+		errs = append(errs, errors.New("tracing.service_name is required"))
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errors.Join(errs...)
+}
+
+func (d DBConfig) validatePostgres() []error {
+	var errs []error
+	if d.Host == "" {
+		errs = append(errs, errors.New("db.host is required"))
+	}
+	if d.Port < 1 || d.Port > 65535 {
+		errs = append(errs, fmt.Errorf("db.port %d is out of range", d.Port))
+	}
+	if d.User == "" {
+		errs = append(errs, errors.New("db.user is required"))
+	}
+	if d.Name == "" {
+		errs = append(errs, errors.New("db.name is required"))
+	}
+	switch d.SSLMode {
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		errs = append(errs, fmt.Errorf("db.sslmode %q is not supported", d.SSLMode))
+	}
+	return errs
+}
+
+// Print writes the configuration to w as YAML with secrets redacted.
+func (c Config) Print(w io.Writer) error {
+	out := map[string]map[string]any{}
+	for _, b := range c.bindings() {
+		section, name, _ := strings.Cut(b.key, ".")
+		if out[section] == nil {
+			out[section] = map[string]any{}
+		}
+		v := b.value.Get()
+		if b.secret && b.value.String() != "" {
+			v = "REDACTED"
+		}
+		out[section][name] = v
+	}
+	return yaml.NewEncoder(w).Encode(out)
+}
+
+// binding ties a config key to the field it sets.
+type binding struct {
+	key    string
+	usage  string
+	secret bool
+	value  flag.Getter
+}
+
+func (b binding) env() string {
+	return strings.ToUpper(strings.ReplaceAll(b.key, ".", "_"))
+}
+
+func (c *Config) bindings() []binding {
+	return []binding{
+		{key: "app.host", usage: "interface the api listens on", value: (*stringValue)(&c.App.Host)},
+		{key: "app.port", usage: "port the api listens on", value: (*intValue)(&c.App.Port)},
+		{key: "app.read_timeout", usage: "http server read timeout", value: (*durationValue)(&c.App.ReadTimeout)},
+		{key: "app.write_timeout", usage: "http server write timeout", value: (*durationValue)(&c.App.WriteTimeout)},
+		{key: "app.idle_timeout", usage: "http server idle timeout", value: (*durationValue)(&c.App.IdleTimeout)},
+		{key: "app.shutdown_timeout", usage: "time allowed for graceful shutdown", value: (*durationValue)(&c.App.ShutdownTimeout)},
+		{key: "db.driver", usage: "database driver, postgres, sqlite or memory", value: (*stringValue)(&c.DB.Driver)},
+		{key: "db.host", usage: "database host", value: (*stringValue)(&c.DB.Host)},
+		{key: "db.port", usage: "database port", value: (*intValue)(&c.DB.Port)},
+		{key: "db.user", usage: "database user", value: (*stringValue)(&c.DB.User)},
+		{key: "db.password", usage: "database password", secret: true, value: (*stringValue)(&c.DB.Password)},
+		{key: "db.name", usage: "database name", value: (*stringValue)(&c.DB.Name)},
+		{key: "db.sslmode", usage: "database ssl mode", value: (*stringValue)(&c.DB.SSLMode)},
+		{key: "db.timezone", usage: "database session time zone", value: (*stringValue)(&c.DB.TimeZone)},
+		{key: "db.path", usage: "file of the sqlite database", value: (*stringValue)(&c.DB.Path)},
+		{key: "db.migrate_on_start", usage: "apply pending schema migrations when the api starts", value: (*boolValue)(&c.DB.MigrateOnStart)},
+		{key: "db.query_timeout", usage: "time the queries of a request may take, 0 for no limit", value: (*durationValue)(&c.DB.QueryTimeout)},
+		{key: "db.route_timeouts", usage: "query timeouts of single routes, as \"GET /api/jobs/search=10s,POST /api/login=2s\"", value: (*durationMapValue)(&c.DB.RouteTimeouts)},
+		{key: "auth.private_key", usage: "path to the RSA private key used to sign tokens", value: (*stringValue)(&c.Auth.PrivateKeyPath)},
+		{key: "auth.public_key", usage: "path to the RSA public key used to validate tokens", value: (*stringValue)(&c.Auth.PublicKeyPath)},
+		{key: "auth.key_dir", usage: "directory of <kid>.pem signing keys, replaces auth.private_key and auth.public_key", value: (*stringValue)(&c.Auth.KeyDir)},
+		{key: "auth.key_reload_interval", usage: "how often the keys in auth.key_dir are reloaded", value: (*durationValue)(&c.Auth.KeyReloadInterval)},
+		{key: "auth.key_retention", usage: "how long a retired signing key still validates tokens", value: (*durationValue)(&c.Auth.KeyRetention)},
+		{key: "page.default_size", usage: "number of items in a page of a list when none is asked for", value: (*intValue)(&c.Page.DefaultSize)},
+		{key: "page.max_size", usage: "largest number of items a page of a list can hold", value: (*intValue)(&c.Page.MaxSize)},
+		{key: "page.cursor_secret", usage: "secret signing the cursors of paged lists, random when empty", secret: true, value: (*stringValue)(&c.Page.CursorSecret)},
+		{key: "tracing.endpoint", usage: "url of the OTLP/HTTP collector spans are sent to, as http://localhost:4318, none when empty", value: (*stringValue)(&c.Tracing.Endpoint)},
+		{key: "tracing.service_name", usage: "service name the spans are reported under", value: (*stringValue)(&c.Tracing.ServiceName)},
+	}
+}
+
+// loadFile applies the settings found in a YAML or TOML file, chosen by extension.
+func loadFile(path string, bs []binding) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file %w", err)
+	}
+
+	var raw map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return fmt.Errorf("config file %s: unsupported format, use .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+
+	byKey := make(map[string]binding, len(bs))
+	for _, b := range bs {
+		byKey[b.key] = b
+	}
+	values := map[string]string{}
+	flatten("", raw, values, byKey)
+
+	for k, v := range values {
+		b, ok := byKey[k]
+		if !ok {
+			return fmt.Errorf("config file %s: unknown key %q", path, k)
+		}
+		err := b.value.Set(v)
+		if err != nil {
+			return fmt.Errorf("config file %s: %s: %w", path, k, err)
+		}
+	}
+	return nil
+}
+
+// flatten turns nested sections into dotted keys. A map set to a key of a binding is the
+// value of the binding, written as the k=v pairs its flag takes.
+func flatten(prefix string, in map[string]any, out map[string]string, byKey map[string]binding) {
+	for k, v := range in {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		m, ok := v.(map[string]any)
+		if !ok {
+			out[key] = fmt.Sprint(v)
+			continue
+		}
+		if _, ok := byKey[key]; !ok {
+			flatten(key, m, out, byKey)
+			continue
+		}
+		pairs := make([]string, 0, len(m))
+		for mk, mv := range m {
+			pairs = append(pairs, fmt.Sprintf("%s=%v", mk, mv))
+		}
+		out[key] = strings.Join(pairs, ",")
+	}
+}
+
+// lookupFlag finds the value of a flag in args without parsing the rest of them.
+func lookupFlag(args []string, name string) (string, bool) {
+	for i, a := range args {
+		if a == "--" {
+			break
+		}
+		a = strings.TrimLeft(a, "-")
+		if a == name && i+1 < len(args) {
+			return args[i+1], true
+		}
+		if v, ok := strings.CutPrefix(a, name+"="); ok {
+			return v, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,251 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	yamlFile := `
+app:
+  port: 9000
+  read_timeout: 3s
+db:
+  host: filehost
+  user: fileuser
+`
+	tomlFile := `
+[db]
+host = "tomlhost"
+port = 6543
+`
+	tests := []struct {
+		name    string
+		env     map[string]string
+		args    func(t *testing.T) []string
+		check   func(t *testing.T, cfg Config)
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			args: func(t *testing.T) []string { return nil },
+			check: func(t *testing.T, cfg Config) {
+				assert.Equal(t, Default(), cfg)
+			},
+		},
+		{
+			name: "yaml file overrides defaults",
+			args: func(t *testing.T) []string {
+				return []string{"-config", writeFile(t, "cfg.yaml", yamlFile)}
+			},
+			check: func(t *testing.T, cfg Config) {
+				assert.Equal(t, 9000, cfg.App.Port)
+				assert.Equal(t, 3*time.Second, cfg.App.ReadTimeout)
+				assert.Equal(t, "filehost", cfg.DB.Host)
+				assert.Equal(t, "fileuser", cfg.DB.User)
+				assert.Equal(t, "postgres", cfg.DB.Name)
+			},
+		},
+		{
+			name: "toml file from environment",
+			args: func(t *testing.T) []string {
+				t.Setenv("CONFIG_FILE", writeFile(t, "cfg.toml", tomlFile))
+				return nil
+			},
+			check: func(t *testing.T, cfg Config) {
+				assert.Equal(t, "tomlhost", cfg.DB.Host)
+				assert.Equal(t, 6543, cfg.DB.Port)
+			},
+		},
+		{
+			name: "env overrides file and flags override env",
+			env:  map[string]string{"DB_HOST": "envhost", "DB_USER": "envuser"},
+			args: func(t *testing.T) []string {
+				return []string{"--config=" + writeFile(t, "cfg.yml", yamlFile), "-db.user", "flaguser"}
+			},
+			check: func(t *testing.T, cfg Config) {
+				assert.Equal(t, "envhost", cfg.DB.Host)
+				assert.Equal(t, "flaguser", cfg.DB.User)
+				assert.Equal(t, 9000, cfg.App.Port)
+			},
+		},
+		{
+			name: "bool from env",
+			env:  map[string]string{"DB_MIGRATE_ON_START": "false"},
+			args: func(t *testing.T) []string { return nil },
+			check: func(t *testing.T, cfg Config) {
+				assert.False(t, cfg.DB.MigrateOnStart)
+			},
+		},
+		{
+			name: "bool flag without a value",
+			env:  map[string]string{"DB_MIGRATE_ON_START": "false"},
+			args: func(t *testing.T) []string { return []string{"-db.migrate_on_start"} },
+			check: func(t *testing.T, cfg Config) {
+				assert.True(t, cfg.DB.MigrateOnStart)
+			},
+		},
+		{
+			name: "unknown key in file",
+			args: func(t *testing.T) []string {
+				return []string{"-config", writeFile(t, "cfg.yaml", "db:\n  hots: x\n")}
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported file format",
+			args: func(t *testing.T) []string {
+				return []string{"-config", writeFile(t, "cfg.json", "{}")}
+			},
+			wantErr: true,
+		},
+		{
+			name:    "invalid env value",
+			env:     map[string]string{"APP_READ_TIMEOUT": "soon"},
+			args:    func(t *testing.T) []string { return nil },
+			wantErr: true,
+		},
+		{
+			name: "memory driver needs no connection",
+			env:  map[string]string{"DB_DRIVER": "memory"},
+			args: func(t *testing.T) []string { return []string{"-db.host", "", "-db.sslmode", "sometimes"} },
+			check: func(t *testing.T, cfg Config) {
+				assert.Equal(t, DriverMemory, cfg.DB.Driver)
+			},
+		},
+		{
+			name: "sqlite driver reads a file",
+			args: func(t *testing.T) []string {
+				return []string{"-db.driver", "sqlite", "-db.path", "jobs.db", "-db.host", ""}
+			},
+			check: func(t *testing.T, cfg Config) {
+				assert.Equal(t, DriverSQLite, cfg.DB.Driver)
+				assert.Equal(t, "jobs.db", cfg.DB.Path)
+			},
+		},
+		{
+			name:    "sqlite driver needs a path",
+			args:    func(t *testing.T) []string { return []string{"-db.driver", "sqlite", "-db.path", ""} },
+			wantErr: true,
+		},
+		{
+			name: "route timeouts from a yaml map",
+			args: func(t *testing.T) []string {
+				return []string{"-config", writeFile(t, "cfg.yaml", `
+db:
+  query_timeout: 3s
+  route_timeouts:
+    GET /api/jobs/search: 10s
+    POST /api/login: 500ms
+`)}
+			},
+			check: func(t *testing.T, cfg Config) {
+				assert.Equal(t, 3*time.Second, cfg.DB.QueryTimeout)
+				assert.Equal(t, map[string]time.Duration{
+					"GET /api/jobs/search": 10 * time.Second,
+					"POST /api/login":      500 * time.Millisecond,
+				}, cfg.DB.RouteTimeouts)
+			},
+		},
+		{
+			name: "route timeouts from env",
+			env:  map[string]string{"DB_ROUTE_TIMEOUTS": "GET /api/jobs=2s, DELETE /api/jobs/:jobID=1s"},
+			args: func(t *testing.T) []string { return nil },
+			check: func(t *testing.T, cfg Config) {
+				assert.Equal(t, map[string]time.Duration{
+					"GET /api/jobs":           2 * time.Second,
+					"DELETE /api/jobs/:jobID": time.Second,
+				}, cfg.DB.RouteTimeouts)
+			},
+		},
+		{
+			name:    "route timeout without a method",
+			args:    func(t *testing.T) []string { return []string{"-db.route_timeouts", "/api/jobs=2s"} },
+			wantErr: true,
+		},
+		{
+			name:    "route timeout that is not a duration",
+			args:    func(t *testing.T) []string { return []string{"-db.route_timeouts", "GET /api/jobs=soon"} },
+			wantErr: true,
+		},
+		{
+			name: "tracing endpoint from env",
+			env:  map[string]string{"TRACING_ENDPOINT": "http://collector:4318"},
+			args: func(t *testing.T) []string { return nil },
+			check: func(t *testing.T, cfg Config) {
+				assert.Equal(t, "http://collector:4318", cfg.Tracing.Endpoint)
+				assert.Equal(t, "job-portal-api", cfg.Tracing.ServiceName)
+			},
+		},
+		{
+			name:    "tracing endpoint that is not a url",
+			args:    func(t *testing.T) []string { return []string{"-tracing.endpoint", "collector:4318"} },
+			wantErr: true,
+		},
+		{
+			name:    "unknown driver",
+			args:    func(t *testing.T) []string { return []string{"-db.driver", "oracle"} },
+			wantErr: true,
+		},
+		{
+			name:    "validation failure",
+			args:    func(t *testing.T) []string { return []string{"-app.port", "0", "-db.sslmode", "sometimes"} },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&bytes.Buffer{})
+			cfg, err := Load(fs, tt.args(t))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestDBConfig_DSN(t *testing.T) {
+	d := Default().DB
+	d.Password = `it's a \secret`
+	d.User = ""
+	assert.Equal(t, `host='localhost' user='' password='it\'s a \\secret' dbname='postgres' port=5432 sslmode='disable' TimeZone='Asia/Shanghai'`, d.DSN())
+
+	pc, err := pgconn.ParseConfig(d.DSN())
+	require.NoError(t, err)
+	assert.Equal(t, `it's a \secret`, pc.Password)
+	assert.Equal(t, "postgres", pc.Database)
+	assert.Equal(t, uint16(5432), pc.Port)
+}
+
+func TestConfig_Print(t *testing.T) {
+	cfg := Default()
+	cfg.DB.Password = "hunter2"
+
+	var buf bytes.Buffer
+	require.NoError(t, cfg.Print(&buf))
+	assert.NotContains(t, buf.String(), "hunter2")
+	assert.Contains(t, buf.String(), "password: REDACTED")
+	assert.Contains(t, buf.String(), "port: 8081")
+}
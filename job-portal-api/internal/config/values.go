@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// stringValue, intValue, boolValue, durationValue and durationMapValue implement flag.Getter over a config field so the
+// same setter is used for files, environment variables and flags.
+
+type stringValue string
+
+func (s *stringValue) Set(v string) error {
+	*s = stringValue(v)
+	return nil
+}
+
+func (s *stringValue) String() string { return string(*s) }
+
+func (s *stringValue) Get() any { return string(*s) }
+
+type intValue int
+
+func (i *intValue) Set(v string) error {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return err
+	}
+	*i = intValue(n)
+	return nil
+}
+
+func (i *intValue) String() string { return strconv.Itoa(int(*i)) }
+
+func (i *intValue) Get() any { return int(*i) }
+
+type boolValue bool
+
+func (b *boolValue) Set(v string) error {
+	t, err := strconv.ParseBool(v)
+	if err != nil {
+		return err
+	}
+	*b = boolValue(t)
+	return nil
+}
+
+func (b *boolValue) String() string { return strconv.FormatBool(bool(*b)) }
+
+func (b *boolValue) Get() any { return bool(*b) }
+
+// IsBoolFlag lets the flag be given without a value, as -db.migrate_on_start.
+func (b *boolValue) IsBoolFlag() bool { return true }
+
+type durationValue time.Duration
+
+func (d *durationValue) Set(v string) error {
+	t, err := time.ParseDuration(v)
+	if err != nil {
+		return err
+	}
+	*d = durationValue(t)
+	return nil
+}
+
+func (d *durationValue) String() string { return time.Duration(*d).String() }
+
+func (d *durationValue) Get() any { return time.Duration(*d).String() }
+
+// durationMapValue is a list of key=duration pairs separated by commas, such as
+// "GET /api/jobs=2s,POST /api/login=500ms".
+type durationMapValue map[string]time.Duration
+
+func (m *durationMapValue) Set(v string) error {
+	out := durationMapValue{}
+	for _, pair := range strings.Split(v, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		k, d, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("%q is not key=duration", pair)
+		}
+		t, err := time.ParseDuration(strings.TrimSpace(d))
+		if err != nil {
+			return err
+		}
+		out[strings.TrimSpace(k)] = t
+	}
+	*m = out
+	return nil
+}
+
+func (m *durationMapValue) String() string {
+	keys := make([]string, 0, len(*m))
+	for k := range *m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+(*m)[k].String())
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (m *durationMapValue) Get() any { return m.String() }
@@ -1,15 +1,50 @@
-package database
-
-import (
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
-)
-
-func Open() (*gorm.DB, error) {
-	dsn := "host=localhost user=postgres password=admin dbname=postgres port=5432 sslmode=disable TimeZone=Asia/Shanghai"
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
-	if err != nil {
-		return nil, err
-	}
-	return db, nil
-}
+package database
+
+import (
+	"fmt"
+	"job-portal-api/internal/config"
+	"net/url"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Names of the dialects the repositories and the migrations support, as returned by
+// gorm.Dialector.Name.
+const (
+	DialectPostgres = "postgres"
+	DialectSQLite   = "sqlite"
+)
+
+// Open connects to the database cfg.Driver names.
+func Open(cfg config.DBConfig) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch cfg.Driver {
+	case config.DriverPostgres:
+		dialector = postgres.Open(cfg.DSN())
+	case config.DriverSQLite:
+		dialector = &sqlite.Dialector{DriverName: sqliteDriverName, DSN: sqliteDSN(cfg.Path)}
+	default:
+		return nil, fmt.Errorf("db.driver %q has no database to open", cfg.Driver)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		// Lets the repositories tell unique and foreign key violations apart from other errors.
+		TranslateError: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// sqliteDSN turns the path of a sqlite database into the name the driver opens. Foreign keys
+// are off in sqlite unless asked for, and writers wait for each other instead of failing.
+func sqliteDSN(path string) string {
+	q := url.Values{}
+	q.Add("_pragma", "foreign_keys(1)")
+	q.Add("_pragma", "busy_timeout(5000)")
+	q.Add("_pragma", "journal_mode(WAL)")
+	return path + "?" + q.Encode()
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/config"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := Open(config.DBConfig{Driver: config.DriverSQLite, Path: filepath.Join(t.TempDir(), "test.db")})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, err := db.DB()
+		if err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func TestOpen(t *testing.T) {
+	db := openSQLite(t)
+	assert.Equal(t, DialectSQLite, db.Dialector.Name())
+
+	_, err := Open(config.DBConfig{Driver: config.DriverMemory})
+	assert.Error(t, err)
+}
+
+func TestOpen_sqliteTimesCompareInUTC(t *testing.T) {
+	db := openSQLite(t)
+	type event struct {
+		ID int
+		At time.Time
+	}
+	require.NoError(t, db.Exec("CREATE TABLE events (id integer PRIMARY KEY, at datetime)").Error)
+
+	utc := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	// An hour later, but it reads as earlier text in its own offset.
+	later := utc.Add(time.Hour).In(time.FixedZone("", -9*3600))
+	require.NoError(t, db.Create(&[]event{{ID: 1, At: utc}, {ID: 2, At: later}}).Error)
+
+	var ids []int
+	require.NoError(t, db.Model(&event{}).Where("at > ?", utc.In(time.FixedZone("", 2*3600))).Pluck("id", &ids).Error)
+	assert.Equal(t, []int{2}, ids)
+
+	var got event
+	require.NoError(t, db.First(&got, 2).Error)
+	assert.True(t, later.Equal(got.At), "read back %v, wrote %v", got.At, later)
+}
+
+func TestOpen_sqliteForeignKeys(t *testing.T) {
+	db := openSQLite(t)
+	require.NoError(t, db.Exec("CREATE TABLE parents (id integer PRIMARY KEY)").Error)
+	require.NoError(t, db.Exec("CREATE TABLE children (id integer PRIMARY KEY, parent_id integer REFERENCES parents (id))").Error)
+
+	err := db.Exec("INSERT INTO children (id, parent_id) VALUES (1, 42)").Error
+	assert.True(t, errors.Is(err, gorm.ErrForeignKeyViolated), "got %v", err)
+}
+
+func TestOpen_sqliteDeadlineInterruptsStatement(t *testing.T) {
+	db := openSQLite(t)
+	db.Logger = logger.Discard
+	require.NoError(t, db.Exec("CREATE TABLE numbers (n integer)").Error)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	// Counts forever unless interrupted.
+	err := db.WithContext(ctx).Exec(`INSERT INTO numbers
+		WITH RECURSIVE c(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM c) SELECT n FROM c`).Error
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,404 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations/postgres/*.sql migrations/sqlite/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID is the key of the postgres advisory lock held while migrating, so that
+// instances starting together apply the pending migrations once.
+const migrationLockID int64 = 7_204_190_116
+
+var (
+	// ErrChecksumMismatch is returned when an applied migration was edited afterwards.
+	ErrChecksumMismatch = errors.New("migration changed after it was applied")
+	// ErrUnknownMigration is returned when the database has a migration the binary lacks,
+	// usually because it was migrated by a newer release.
+	ErrUnknownMigration = errors.New("applied migration is unknown")
+	// ErrOutOfOrder is returned for a pending migration older than the last applied one.
+	ErrOutOfOrder = errors.New("migration is older than the last applied migration")
+)
+
+// Migration is a versioned change to the schema, read from a pair of files named
+// <version>_<name>.up.sql and <version>_<name>.down.sql.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+	// Checksum is the sha256 of Up. Down is left out so that a broken rollback can be
+	// fixed after the migration shipped.
+	Checksum string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// MigrationState tells where a migration stands in a database.
+type MigrationState string
+
+const (
+	StateApplied MigrationState = "applied"
+	StatePending MigrationState = "pending"
+	// StateChanged marks an applied migration whose up script is not the one applied.
+	StateChanged MigrationState = "changed"
+	// StateUnknown marks an applied migration that is not in the binary.
+	StateUnknown MigrationState = "unknown"
+)
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	State     MigrationState
+	AppliedAt time.Time
+}
+
+// appliedMigration is a row of schema_migrations.
+type appliedMigration struct {
+	Version   int64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+func (appliedMigration) TableName() string { return "schema_migrations" }
+
+// createMigrationsTable creates schema_migrations with the column types of each dialect.
+var createMigrationsTable = map[string]string{
+	DialectPostgres: `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version bigint PRIMARY KEY,
+	name text NOT NULL,
+	checksum text NOT NULL,
+	applied_at timestamptz NOT NULL DEFAULT now()
+)`,
+	DialectSQLite: `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version integer PRIMARY KEY,
+	name text NOT NULL,
+	checksum text NOT NULL,
+	applied_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`,
+}
+
+var migrationFile = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migrations returns the migrations embedded in the binary for a dialect, postgres or sqlite.
+func Migrations(dialect string) ([]Migration, error) {
+	sub, err := fs.Sub(migrationFiles, path.Join("migrations", dialect))
+	if err != nil {
+		return nil, err
+	}
+	ms, err := LoadMigrations(sub)
+	if err != nil {
+		return nil, err
+	}
+	if len(ms) == 0 {
+		return nil, fmt.Errorf("no migrations for the %s dialect", dialect)
+	}
+	return ms, nil
+}
+
+// LoadMigrations reads the .sql files at the root of fsys and returns the migrations they
+// hold sorted by version. Every migration needs both an up and a down file.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	paths, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, p := range paths {
+		match := migrationFile.FindStringSubmatch(path.Base(p))
+		if match == nil {
+			return nil, fmt.Errorf("migration file %s: name is not <version>_<name>.up.sql or <version>_<name>.down.sql", p)
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version < 1 {
+			return nil, fmt.Errorf("migration file %s: version must be a positive number", p)
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration file %s: version %d is also used by %s", p, version, m)
+		}
+		if match[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	ms := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %s needs both an up and a down file", m)
+		}
+		sum := sha256.Sum256([]byte(m.Up))
+		m.Checksum = hex.EncodeToString(sum[:])
+		ms = append(ms, *m)
+	}
+	sort.Slice(ms, func(i, j int) bool { return ms[i].Version < ms[j].Version })
+	return ms, nil
+}
+
+// Migrator applies and rolls back migrations, keeping track of them in schema_migrations.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator returns a Migrator over the migrations embedded in the binary for the
+// dialect of db.
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	ms, err := Migrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: ms}, nil
+}
+
+// Up applies the pending migrations in order and returns them.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *gorm.DB, applied []appliedMigration) error {
+		todo, err := pending(m.migrations, applied)
+		if err != nil {
+			return err
+		}
+		for _, mig := range todo {
+			err = apply(conn, mig)
+			if err != nil {
+				return err
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down rolls back the last steps applied migrations, newest first, and returns them.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *gorm.DB, applied []appliedMigration) error {
+		todo, err := rollbacks(m.migrations, applied, steps)
+		if err != nil {
+			return err
+		}
+		for _, mig := range todo {
+			err = rollback(conn, mig)
+			if err != nil {
+				return err
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Redo rolls back the last applied migration and applies it again.
+func (m *Migrator) Redo(ctx context.Context) (Migration, error) {
+	var mig Migration
+	err := m.locked(ctx, func(conn *gorm.DB, applied []appliedMigration) error {
+		todo, err := rollbacks(m.migrations, applied, 1)
+		if err != nil {
+			return err
+		}
+		if len(todo) == 0 {
+			return errors.New("no migration has been applied")
+		}
+		mig = todo[0]
+		err = rollback(conn, mig)
+		if err != nil {
+			return err
+		}
+		return apply(conn, mig)
+	})
+	return mig, err
+}
+
+// Status lists the known and the applied migrations by version.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var out []MigrationStatus
+	err := m.locked(ctx, func(_ *gorm.DB, applied []appliedMigration) error {
+		out = status(m.migrations, applied)
+		return nil
+	})
+	return out, err
+}
+
+// locked runs fn holding the migration lock, on a single connection since advisory locks
+// belong to the session that took them. Sqlite has no such lock, it serves a single
+// instance and locks the whole file while a migration writes to it.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *gorm.DB, applied []appliedMigration) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if conn.Dialector.Name() == DialectPostgres {
+			err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockID).Error
+			if err != nil {
+				return fmt.Errorf("taking the migration lock %w", err)
+			}
+			// The connection goes back to the pool, so the lock must be released even when ctx is done.
+			defer conn.WithContext(context.Background()).Exec("SELECT pg_advisory_unlock(?)", migrationLockID)
+		}
+
+		err := conn.Exec(createMigrationsTable[conn.Dialector.Name()]).Error
+		if err != nil {
+			return fmt.Errorf("creating schema_migrations %w", err)
+		}
+		var applied []appliedMigration
+		err = conn.Order("version").Find(&applied).Error
+		if err != nil {
+			return fmt.Errorf("reading schema_migrations %w", err)
+		}
+		return fn(conn, applied)
+	})
+}
+
+func apply(conn *gorm.DB, mig Migration) error {
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		// Without arguments the script is sent as is, so it may hold several statements.
+		err := tx.Exec(mig.Up).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&appliedMigration{
+			Version:   mig.Version,
+			Name:      mig.Name,
+			Checksum:  mig.Checksum,
+			AppliedAt: time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("applying migration %s: %w", mig, err)
+	}
+	return nil
+}
+
+func rollback(conn *gorm.DB, mig Migration) error {
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(mig.Down).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&appliedMigration{}, mig.Version).Error
+	})
+	if err != nil {
+		return fmt.Errorf("rolling back migration %s: %w", mig, err)
+	}
+	return nil
+}
+
+// verify checks that every applied migration is known and unchanged.
+func verify(ms []Migration, applied []appliedMigration) (map[int64]Migration, error) {
+	known := make(map[int64]Migration, len(ms))
+	for _, mig := range ms {
+		known[mig.Version] = mig
+	}
+	for _, a := range applied {
+		mig, ok := known[a.Version]
+		if !ok {
+			return nil, fmt.Errorf("%w: %04d_%s", ErrUnknownMigration, a.Version, a.Name)
+		}
+		if mig.Checksum != a.Checksum {
+			return nil, fmt.Errorf("%w: %s", ErrChecksumMismatch, mig)
+		}
+	}
+	return known, nil
+}
+
+// pending returns the migrations of ms that are not applied yet.
+func pending(ms []Migration, applied []appliedMigration) ([]Migration, error) {
+	_, err := verify(ms, applied)
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[int64]bool, len(applied))
+	var last int64
+	for _, a := range applied {
+		done[a.Version] = true
+		last = max(last, a.Version)
+	}
+
+	var todo []Migration
+	for _, mig := range ms {
+		if done[mig.Version] {
+			continue
+		}
+		if mig.Version < last {
+			return nil, fmt.Errorf("%w: %s was added after %04d was applied", ErrOutOfOrder, mig, last)
+		}
+		todo = append(todo, mig)
+	}
+	return todo, nil
+}
+
+// rollbacks returns the last steps applied migrations, newest first.
+func rollbacks(ms []Migration, applied []appliedMigration, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("steps must be positive, got %d", steps)
+	}
+	known, err := verify(ms, applied)
+	if err != nil {
+		return nil, err
+	}
+	sorted := append([]appliedMigration{}, applied...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version > sorted[j].Version })
+
+	var todo []Migration
+	for _, a := range sorted {
+		if len(todo) == steps {
+			break
+		}
+		todo = append(todo, known[a.Version])
+	}
+	return todo, nil
+}
+
+func status(ms []Migration, applied []appliedMigration) []MigrationStatus {
+	byVersion := make(map[int64]appliedMigration, len(applied))
+	for _, a := range applied {
+		byVersion[a.Version] = a
+	}
+
+	out := make([]MigrationStatus, 0, len(ms)+len(applied))
+	for _, mig := range ms {
+		s := MigrationStatus{Version: mig.Version, Name: mig.Name, State: StatePending}
+		if a, ok := byVersion[mig.Version]; ok {
+			s.State, s.AppliedAt = StateApplied, a.AppliedAt
+			if a.Checksum != mig.Checksum {
+				s.State = StateChanged
+			}
+			delete(byVersion, mig.Version)
+		}
+		out = append(out, s)
+	}
+	for _, a := range byVersion {
+		out = append(out, MigrationStatus{Version: a.Version, Name: a.Name, State: StateUnknown, AppliedAt: a.AppliedAt})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out
+}
@@ -0,0 +1,296 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func file(s string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(s)} }
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		want    []int64
+		wantErr bool
+	}{
+		{
+			name: "sorted by version",
+			fsys: fstest.MapFS{
+				"0010_b.up.sql":   file("B"),
+				"0010_b.down.sql": file("-B"),
+				"0002_a.up.sql":   file("A"),
+				"0002_a.down.sql": file("-A"),
+				"README.md":       file("not a migration"),
+			},
+			want: []int64{2, 10},
+		},
+		{
+			name:    "missing down file",
+			fsys:    fstest.MapFS{"0001_a.up.sql": file("A")},
+			wantErr: true,
+		},
+		{
+			name: "version used twice",
+			fsys: fstest.MapFS{
+				"0001_a.up.sql":   file("A"),
+				"0001_a.down.sql": file("-A"),
+				"0001_b.up.sql":   file("B"),
+				"0001_b.down.sql": file("-B"),
+			},
+			wantErr: true,
+		},
+		{
+			name:    "badly named file",
+			fsys:    fstest.MapFS{"init.sql": file("A")},
+			wantErr: true,
+		},
+		{
+			name:    "version zero",
+			fsys:    fstest.MapFS{"0_a.up.sql": file("A"), "0_a.down.sql": file("-A")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms, err := LoadMigrations(tt.fsys)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			var got []int64
+			for _, m := range ms {
+				got = append(got, m.Version)
+				assert.Len(t, m.Checksum, 64)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoadMigrations_checksum(t *testing.T) {
+	load := func(up, down string) Migration {
+		ms, err := LoadMigrations(fstest.MapFS{"0001_a.up.sql": file(up), "0001_a.down.sql": file(down)})
+		require.NoError(t, err)
+		return ms[0]
+	}
+	m := load("CREATE TABLE a ()", "DROP TABLE a")
+	assert.Equal(t, m.Checksum, load("CREATE TABLE a ()", "DROP TABLE IF EXISTS a").Checksum, "down script changes the checksum")
+	assert.NotEqual(t, m.Checksum, load("CREATE TABLE a (id int)", "DROP TABLE a").Checksum, "up script does not change the checksum")
+}
+
+func TestMigrations(t *testing.T) {
+	for _, dialect := range []string{DialectPostgres, DialectSQLite} {
+		t.Run(dialect, func(t *testing.T) {
+			ms, err := Migrations(dialect)
+			require.NoError(t, err)
+			require.NotEmpty(t, ms)
+			for i, m := range ms {
+				assert.Equal(t, int64(i+1), m.Version, "versions must have no gaps")
+			}
+			assert.Equal(t, "initial_schema", ms[0].Name)
+		})
+	}
+
+	_, err := Migrations("oracle")
+	assert.Error(t, err)
+}
+
+// TestMigrator_adoptsAutoMigrateSchema brings a database created by AutoMigrate, before
+// the api had migrations, up to date. It runs against the postgres database
+// JOB_PORTAL_TEST_DSN points to, in a schema of its own that is dropped afterwards.
+func TestMigrator_adoptsAutoMigrateSchema(t *testing.T) {
+	dsn := os.Getenv("JOB_PORTAL_TEST_DSN")
+	if dsn == "" {
+		t.Skip("JOB_PORTAL_TEST_DSN is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true, Logger: logger.Discard})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	// The search path is set on the session, so every statement must use the same one.
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.Exec("DROP SCHEMA IF EXISTS automigrate_baseline CASCADE").Error)
+	require.NoError(t, db.Exec("CREATE SCHEMA automigrate_baseline").Error)
+	t.Cleanup(func() { db.Exec("DROP SCHEMA IF EXISTS automigrate_baseline CASCADE") })
+	require.NoError(t, db.Exec("SET search_path TO automigrate_baseline").Error)
+
+	// The models as they were when the api ran AutoMigrate.
+	type Job struct {
+		gorm.Model
+		Title       string
+		Description string
+		CompanyID   uint
+	}
+	type Companies struct {
+		gorm.Model
+		CompanyName string
+		FoundedYear int
+		Location    string
+		UserId      uint
+		Address     string
+		Jobs        []Job `gorm:"foreignKey:CompanyID"`
+	}
+	type User struct {
+		gorm.Model
+		Name         string
+		Email        string `gorm:"unique;not null"`
+		PasswordHash string
+	}
+	require.NoError(t, db.AutoMigrate(&User{}, &Companies{}, &Job{}))
+	owner := User{Name: "owner", Email: "owner@example.com"}
+	require.NoError(t, db.Create(&owner).Error)
+	require.NoError(t, db.Create(&User{Name: "candidate", Email: "candidate@example.com"}).Error)
+	company := Companies{CompanyName: "Acme", UserId: owner.ID, Jobs: []Job{{Title: "Backend Engineer", Description: "Go"}}}
+	require.NoError(t, db.Create(&company).Error)
+
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+	done, err := migrator.Up(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, done, len(migrator.migrations))
+
+	var users []struct {
+		Email string
+		Role  string
+	}
+	require.NoError(t, db.Raw("SELECT email, role FROM users ORDER BY id").Scan(&users).Error)
+	require.Len(t, users, 2)
+	assert.Equal(t, "employer", users[0].Role, "the company creator")
+	assert.Equal(t, "candidate", users[1].Role)
+
+	var job struct {
+		Status         string
+		RemotePolicy   string
+		EmploymentType string
+		Searchable     bool
+	}
+	require.NoError(t, db.Raw(`SELECT status, remote_policy, employment_type,
+		search_vector @@ to_tsquery('english', 'backend') AS searchable FROM jobs`).Scan(&job).Error)
+	assert.Equal(t, "open", job.Status)
+	assert.Equal(t, "onsite", job.RemotePolicy)
+	assert.Equal(t, "full_time", job.EmploymentType)
+	assert.True(t, job.Searchable, "jobs from before the search are searchable")
+
+	err = db.Exec("INSERT INTO job_skills (job_id, name, required) VALUES (?, 'go', true)", company.Jobs[0].ID).Error
+	assert.NoError(t, err)
+
+	_, err = migrator.Down(context.Background(), len(done))
+	require.NoError(t, err)
+	var tables []string
+	require.NoError(t, db.Raw("SELECT tablename FROM pg_tables WHERE schemaname = 'automigrate_baseline'").Scan(&tables).Error)
+	assert.Equal(t, []string{"schema_migrations"}, tables)
+}
+
+func migrations(versions ...int64) []Migration {
+	ms := make([]Migration, 0, len(versions))
+	for _, v := range versions {
+		ms = append(ms, Migration{Version: v, Name: "m", Checksum: "sum"})
+	}
+	return ms
+}
+
+func applied(versions ...int64) []appliedMigration {
+	as := make([]appliedMigration, 0, len(versions))
+	for _, v := range versions {
+		as = append(as, appliedMigration{Version: v, Name: "m", Checksum: "sum"})
+	}
+	return as
+}
+
+func versions(ms []Migration) []int64 {
+	vs := []int64{}
+	for _, m := range ms {
+		vs = append(vs, m.Version)
+	}
+	return vs
+}
+
+func Test_pending(t *testing.T) {
+	tests := []struct {
+		name    string
+		ms      []Migration
+		applied []appliedMigration
+		want    []int64
+		wantErr error
+	}{
+		{name: "fresh database", ms: migrations(1, 2, 3), want: []int64{1, 2, 3}},
+		{name: "some applied", ms: migrations(1, 2, 3), applied: applied(1), want: []int64{2, 3}},
+		{name: "up to date", ms: migrations(1, 2), applied: applied(1, 2), want: []int64{}},
+		{name: "applied by a newer release", ms: migrations(1), applied: applied(1, 2), wantErr: ErrUnknownMigration},
+		{name: "added out of order", ms: migrations(1, 2, 3), applied: applied(1, 3), wantErr: ErrOutOfOrder},
+		{
+			name:    "edited after it was applied",
+			ms:      migrations(1),
+			applied: []appliedMigration{{Version: 1, Name: "m", Checksum: "other"}},
+			wantErr: ErrChecksumMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pending(tt.ms, tt.applied)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "pending() error = %v, want %v", err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, versions(got))
+		})
+	}
+}
+
+func Test_rollbacks(t *testing.T) {
+	tests := []struct {
+		name    string
+		applied []appliedMigration
+		steps   int
+		want    []int64
+		wantErr bool
+	}{
+		{name: "last one", applied: applied(1, 2, 3), steps: 1, want: []int64{3}},
+		{name: "newest first", applied: applied(2, 1, 3), steps: 2, want: []int64{3, 2}},
+		{name: "more steps than applied", applied: applied(1), steps: 5, want: []int64{1}},
+		{name: "nothing applied", steps: 1, want: []int64{}},
+		{name: "no steps", applied: applied(1), steps: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rollbacks(migrations(1, 2, 3), tt.applied, tt.steps)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, versions(got))
+		})
+	}
+}
+
+func Test_status(t *testing.T) {
+	at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	as := []appliedMigration{
+		{Version: 1, Name: "m", Checksum: "sum", AppliedAt: at},
+		{Version: 2, Name: "m", Checksum: "edited", AppliedAt: at},
+		{Version: 9, Name: "future", Checksum: "sum", AppliedAt: at},
+	}
+	got := status(migrations(1, 2, 3), as)
+	want := []MigrationStatus{
+		{Version: 1, Name: "m", State: StateApplied, AppliedAt: at},
+		{Version: 2, Name: "m", State: StateChanged, AppliedAt: at},
+		{Version: 3, Name: "m", State: StatePending},
+		{Version: 9, Name: "future", State: StateUnknown, AppliedAt: at},
+	}
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,39 @@
+-- The schema GORM AutoMigrate created before the api had migrations. Every statement is
+-- guarded with IF NOT EXISTS so databases created by AutoMigrate are adopted as they are,
+-- the later migrations bring them up to date.
+
+CREATE TABLE IF NOT EXISTS users (
+	id bigserial PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	name text,
+	email text NOT NULL UNIQUE,
+	password_hash text
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS companies (
+	id bigserial PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	company_name text,
+	founded_year bigint,
+	location text,
+	user_id bigint,
+	address text
+);
+CREATE INDEX IF NOT EXISTS idx_companies_deleted_at ON companies (deleted_at);
+
+CREATE TABLE IF NOT EXISTS jobs (
+	id bigserial PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	title text,
+	description text,
+	company_id bigint,
+	CONSTRAINT fk_companies_jobs FOREIGN KEY (company_id) REFERENCES companies (id)
+);
+CREATE INDEX IF NOT EXISTS idx_jobs_deleted_at ON jobs (deleted_at);
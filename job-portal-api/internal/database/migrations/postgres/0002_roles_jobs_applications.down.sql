@@ -0,0 +1,25 @@
+DROP TABLE IF EXISTS revoked_tokens;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS application_stage_changes;
+DROP TABLE IF EXISTS pipeline_stages;
+DROP TABLE IF EXISTS applications;
+DROP TABLE IF EXISTS job_skills;
+DROP INDEX IF EXISTS idx_jobs_seniority;
+DROP INDEX IF EXISTS idx_jobs_remote_policy;
+DROP INDEX IF EXISTS idx_jobs_expires_at;
+DROP INDEX IF EXISTS idx_jobs_employment_type;
+DROP INDEX IF EXISTS idx_jobs_country;
+ALTER TABLE jobs
+	DROP COLUMN IF EXISTS expires_at,
+	DROP COLUMN IF EXISTS seniority,
+	DROP COLUMN IF EXISTS employment_type,
+	DROP COLUMN IF EXISTS remote_policy,
+	DROP COLUMN IF EXISTS country,
+	DROP COLUMN IF EXISTS city,
+	DROP COLUMN IF EXISTS salary_period,
+	DROP COLUMN IF EXISTS salary_currency,
+	DROP COLUMN IF EXISTS salary_max,
+	DROP COLUMN IF EXISTS salary_min,
+	DROP COLUMN IF EXISTS status;
+DROP TABLE IF EXISTS company_members;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
@@ -0,0 +1,116 @@
+-- Everything the api gained on top of the AutoMigrate schema: roles, company members, the
+-- details of jobs, applications and their pipelines, and sessions. Columns are added with
+-- IF NOT EXISTS and defaults, so the rows of adopted databases get valid values.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role text NOT NULL DEFAULT 'candidate';
+-- Before roles, whoever created a company was acting as its employer.
+UPDATE users SET role = 'employer'
+	WHERE role = 'candidate' AND id IN (SELECT user_id FROM companies WHERE user_id IS NOT NULL);
+
+CREATE TABLE IF NOT EXISTS company_members (
+	id bigserial PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	company_id bigint,
+	user_id bigint,
+	role text NOT NULL,
+	CONSTRAINT fk_companies_members FOREIGN KEY (company_id) REFERENCES companies (id),
+	CONSTRAINT fk_company_members_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_company_member ON company_members (company_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_company_members_deleted_at ON company_members (deleted_at);
+
+ALTER TABLE jobs
+	ADD COLUMN IF NOT EXISTS status text NOT NULL DEFAULT 'open',
+	ADD COLUMN IF NOT EXISTS salary_min bigint,
+	ADD COLUMN IF NOT EXISTS salary_max bigint,
+	ADD COLUMN IF NOT EXISTS salary_currency varchar(3),
+	ADD COLUMN IF NOT EXISTS salary_period text,
+	ADD COLUMN IF NOT EXISTS city text,
+	ADD COLUMN IF NOT EXISTS country varchar(2),
+	ADD COLUMN IF NOT EXISTS remote_policy text NOT NULL DEFAULT 'onsite',
+	ADD COLUMN IF NOT EXISTS employment_type text NOT NULL DEFAULT 'full_time',
+	ADD COLUMN IF NOT EXISTS seniority text,
+	ADD COLUMN IF NOT EXISTS expires_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_jobs_country ON jobs (country);
+CREATE INDEX IF NOT EXISTS idx_jobs_employment_type ON jobs (employment_type);
+CREATE INDEX IF NOT EXISTS idx_jobs_expires_at ON jobs (expires_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_remote_policy ON jobs (remote_policy);
+CREATE INDEX IF NOT EXISTS idx_jobs_seniority ON jobs (seniority);
+
+CREATE TABLE IF NOT EXISTS job_skills (
+	id bigserial PRIMARY KEY,
+	job_id bigint,
+	name text NOT NULL,
+	required boolean,
+	CONSTRAINT fk_jobs_skills FOREIGN KEY (job_id) REFERENCES jobs (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_job_skill ON job_skills (job_id, name);
+
+CREATE TABLE IF NOT EXISTS applications (
+	id bigserial PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	job_id bigint NOT NULL,
+	user_id bigint NOT NULL,
+	cover_letter text,
+	resume_url text,
+	status text NOT NULL DEFAULT 'submitted',
+	stage text,
+	CONSTRAINT fk_applications_job FOREIGN KEY (job_id) REFERENCES jobs (id)
+);
+CREATE INDEX IF NOT EXISTS idx_applications_deleted_at ON applications (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_applications_stage ON applications (stage);
+CREATE INDEX IF NOT EXISTS idx_applications_status ON applications (status);
+CREATE INDEX IF NOT EXISTS idx_applications_user_id ON applications (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_job_applicant ON applications (job_id, user_id);
+
+CREATE TABLE IF NOT EXISTS pipeline_stages (
+	id bigserial PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	company_id bigint NOT NULL,
+	name text NOT NULL,
+	position bigint,
+	outcome text,
+	next text
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_company_stage ON pipeline_stages (company_id, name);
+CREATE INDEX IF NOT EXISTS idx_pipeline_stages_deleted_at ON pipeline_stages (deleted_at);
+
+CREATE TABLE IF NOT EXISTS application_stage_changes (
+	id bigserial PRIMARY KEY,
+	application_id bigint NOT NULL,
+	from_stage text,
+	to_stage text,
+	status text,
+	changed_by bigint,
+	reason text,
+	created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_application_stage_changes_application_id ON application_stage_changes (application_id);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+	id bigserial PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	user_id bigint NOT NULL,
+	token_hash text NOT NULL,
+	family_id text NOT NULL,
+	expires_at timestamptz,
+	revoked_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_deleted_at ON refresh_tokens (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens (token_hash);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+	jti text PRIMARY KEY,
+	expires_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS companies_search_touch ON companies;
+DROP FUNCTION IF EXISTS companies_search_touch();
+DROP TRIGGER IF EXISTS job_skills_search_touch ON job_skills;
+DROP FUNCTION IF EXISTS job_skills_search_touch();
+DROP TRIGGER IF EXISTS jobs_search_vector ON jobs;
+DROP FUNCTION IF EXISTS jobs_search_vector_update();
+DROP INDEX IF EXISTS idx_jobs_search_vector;
+ALTER TABLE jobs DROP COLUMN IF EXISTS search_vector;
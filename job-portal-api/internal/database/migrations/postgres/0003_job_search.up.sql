@@ -0,0 +1,52 @@
+-- jobs.search_vector feeds the full text search. Titles weigh the most, then the company
+-- name and the skills, then the description. The company name and the skills live in other
+-- tables, so changes to them touch the jobs they belong to to refresh the vector.
+
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+CREATE OR REPLACE FUNCTION jobs_search_vector_update() RETURNS trigger AS $$
+BEGIN
+	NEW.search_vector :=
+		setweight(to_tsvector('english', coalesce(NEW.title, '')), 'A') ||
+		setweight(to_tsvector('english', coalesce((SELECT company_name FROM companies WHERE id = NEW.company_id), '')), 'B') ||
+		setweight(to_tsvector('english', coalesce((SELECT string_agg(name, ' ') FROM job_skills WHERE job_id = NEW.id), '')), 'B') ||
+		setweight(to_tsvector('english', coalesce(NEW.description, '')), 'C');
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS jobs_search_vector ON jobs;
+CREATE TRIGGER jobs_search_vector BEFORE INSERT OR UPDATE OF title, description, company_id ON jobs
+	FOR EACH ROW EXECUTE FUNCTION jobs_search_vector_update();
+
+CREATE OR REPLACE FUNCTION job_skills_search_touch() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP <> 'DELETE' THEN
+		UPDATE jobs SET title = title WHERE id = NEW.job_id;
+	END IF;
+	IF TG_OP <> 'INSERT' THEN
+		UPDATE jobs SET title = title WHERE id = OLD.job_id;
+	END IF;
+	RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS job_skills_search_touch ON job_skills;
+CREATE TRIGGER job_skills_search_touch AFTER INSERT OR UPDATE OR DELETE ON job_skills
+	FOR EACH ROW EXECUTE FUNCTION job_skills_search_touch();
+
+CREATE OR REPLACE FUNCTION companies_search_touch() RETURNS trigger AS $$
+BEGIN
+	UPDATE jobs SET title = title WHERE company_id = NEW.id;
+	RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS companies_search_touch ON companies;
+CREATE TRIGGER companies_search_touch AFTER UPDATE OF company_name ON companies
+	FOR EACH ROW EXECUTE FUNCTION companies_search_touch();
+
+-- Backfill the jobs that existed before the trigger.
+UPDATE jobs SET title = title WHERE search_vector IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_search_vector ON jobs USING GIN (search_vector);
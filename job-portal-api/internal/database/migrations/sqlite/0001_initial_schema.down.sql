@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS revoked_tokens;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS application_stage_changes;
+DROP TABLE IF EXISTS pipeline_stages;
+DROP TABLE IF EXISTS applications;
+DROP TABLE IF EXISTS job_skills;
+DROP TABLE IF EXISTS jobs;
+DROP TABLE IF EXISTS company_members;
+DROP TABLE IF EXISTS companies;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,146 @@
+-- The schema the postgres migrations build, for sqlite. Sqlite databases never ran
+-- AutoMigrate, so there is nothing to adopt and the schema is created in one step. Ids
+-- never get reused, like the ones of a sequence, and times are stored as text in UTC.
+
+CREATE TABLE IF NOT EXISTS users (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	created_at datetime,
+	updated_at datetime,
+	deleted_at datetime,
+	name text,
+	email text NOT NULL UNIQUE,
+	password_hash text,
+	role text NOT NULL DEFAULT 'candidate'
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS companies (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	created_at datetime,
+	updated_at datetime,
+	deleted_at datetime,
+	company_name text,
+	founded_year integer,
+	location text,
+	user_id integer,
+	address text
+);
+CREATE INDEX IF NOT EXISTS idx_companies_deleted_at ON companies (deleted_at);
+
+CREATE TABLE IF NOT EXISTS company_members (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	created_at datetime,
+	updated_at datetime,
+	deleted_at datetime,
+	company_id integer,
+	user_id integer,
+	role text NOT NULL,
+	CONSTRAINT fk_companies_members FOREIGN KEY (company_id) REFERENCES companies (id),
+	CONSTRAINT fk_company_members_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_company_member ON company_members (company_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_company_members_deleted_at ON company_members (deleted_at);
+
+CREATE TABLE IF NOT EXISTS jobs (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	created_at datetime,
+	updated_at datetime,
+	deleted_at datetime,
+	title text,
+	description text,
+	company_id integer,
+	status text NOT NULL DEFAULT 'open',
+	salary_min integer,
+	salary_max integer,
+	salary_currency varchar(3),
+	salary_period text,
+	city text,
+	country varchar(2),
+	remote_policy text NOT NULL DEFAULT 'onsite',
+	employment_type text NOT NULL DEFAULT 'full_time',
+	seniority text,
+	expires_at datetime,
+	CONSTRAINT fk_companies_jobs FOREIGN KEY (company_id) REFERENCES companies (id)
+);
+CREATE INDEX IF NOT EXISTS idx_jobs_country ON jobs (country);
+CREATE INDEX IF NOT EXISTS idx_jobs_deleted_at ON jobs (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_employment_type ON jobs (employment_type);
+CREATE INDEX IF NOT EXISTS idx_jobs_expires_at ON jobs (expires_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_remote_policy ON jobs (remote_policy);
+CREATE INDEX IF NOT EXISTS idx_jobs_seniority ON jobs (seniority);
+
+CREATE TABLE IF NOT EXISTS job_skills (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	job_id integer,
+	name text NOT NULL,
+	required boolean,
+	CONSTRAINT fk_jobs_skills FOREIGN KEY (job_id) REFERENCES jobs (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_job_skill ON job_skills (job_id, name);
+
+CREATE TABLE IF NOT EXISTS applications (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	created_at datetime,
+	updated_at datetime,
+	deleted_at datetime,
+	job_id integer NOT NULL,
+	user_id integer NOT NULL,
+	cover_letter text,
+	resume_url text,
+	status text NOT NULL DEFAULT 'submitted',
+	stage text,
+	CONSTRAINT fk_applications_job FOREIGN KEY (job_id) REFERENCES jobs (id)
+);
+CREATE INDEX IF NOT EXISTS idx_applications_deleted_at ON applications (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_applications_stage ON applications (stage);
+CREATE INDEX IF NOT EXISTS idx_applications_status ON applications (status);
+CREATE INDEX IF NOT EXISTS idx_applications_user_id ON applications (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_job_applicant ON applications (job_id, user_id);
+
+CREATE TABLE IF NOT EXISTS pipeline_stages (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	created_at datetime,
+	updated_at datetime,
+	deleted_at datetime,
+	company_id integer NOT NULL,
+	name text NOT NULL,
+	position integer,
+	outcome text,
+	next text
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_company_stage ON pipeline_stages (company_id, name);
+CREATE INDEX IF NOT EXISTS idx_pipeline_stages_deleted_at ON pipeline_stages (deleted_at);
+
+CREATE TABLE IF NOT EXISTS application_stage_changes (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	application_id integer NOT NULL,
+	from_stage text,
+	to_stage text,
+	status text,
+	changed_by integer,
+	reason text,
+	created_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_application_stage_changes_application_id ON application_stage_changes (application_id);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	created_at datetime,
+	updated_at datetime,
+	deleted_at datetime,
+	user_id integer NOT NULL,
+	token_hash text NOT NULL,
+	family_id text NOT NULL,
+	expires_at datetime,
+	revoked_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_deleted_at ON refresh_tokens (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens (token_hash);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+	jti text PRIMARY KEY,
+	expires_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
@@ -0,0 +1,27 @@
+package database
+
+import (
+	"errors"
+
+	gosqlite "github.com/glebarez/go-sqlite"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// sqliteBusy is the primary result code of sqlite for a database locked by another
+// connection, the extended codes keep it in their low byte.
+const sqliteBusy = 5
+
+// SerializationFailure reports whether err comes from a transaction that could not be
+// serialized with the ones running next to it. Running the transaction again may succeed.
+func SerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// serialization_failure and deadlock_detected.
+		return pgErr.Code == "40001" || pgErr.Code == "40P01"
+	}
+	var sqliteErr *gosqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code()&0xff == sqliteBusy
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	gosqlite "github.com/glebarez/go-sqlite"
+)
+
+// sqliteDriverName is the sqlite driver adapted to what the repositories expect of a
+// database, see sqliteConn.
+const sqliteDriverName = "sqlite-portal"
+
+func init() {
+	sql.Register(sqliteDriverName, sqliteDriver{&gosqlite.Driver{}})
+}
+
+type sqliteDriver struct {
+	driver.Driver
+}
+
+func (d sqliteDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	dc, ok := c.(driverConn)
+	if !ok {
+		c.Close()
+		return nil, fmt.Errorf("sqlite connection %T lacks the context methods", c)
+	}
+	return sqliteConn{dc}, nil
+}
+
+// driverConn is what a connection of the sqlite driver implements.
+type driverConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+}
+
+// sqliteConn writes every time in UTC. Sqlite has no time type, times are text compared
+// as text, which only orders them right when they all have the same offset. It also
+// reports statements interrupted because their context is done with the error of the
+// context, like the postgres driver does.
+type sqliteConn struct {
+	driverConn
+}
+
+var _ driver.NamedValueChecker = sqliteConn{}
+
+// CheckNamedValue converts arguments the way database/sql does by default, then moves
+// times to UTC.
+func (sqliteConn) CheckNamedValue(nv *driver.NamedValue) error {
+	v, err := driver.DefaultParameterConverter.ConvertValue(nv.Value)
+	if err != nil {
+		return err
+	}
+	if t, ok := v.(time.Time); ok {
+		v = t.UTC()
+	}
+	nv.Value = v
+	return nil
+}
+
+func (c sqliteConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	res, err := c.driverConn.ExecContext(ctx, query, args)
+	return res, contextError(ctx, err)
+}
+
+func (c sqliteConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := c.driverConn.QueryContext(ctx, query, args)
+	return rows, contextError(ctx, err)
+}
+
+// contextError returns the error of ctx instead of err when ctx is done.
+func contextError(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("%w: %w", ctx.Err(), err)
+	}
+	return err
+}
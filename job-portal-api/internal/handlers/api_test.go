@@ -0,0 +1,359 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/database"
+	"job-portal-api/internal/metrics"
+	middlewares "job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/pagination"
+	"job-portal-api/internal/repository"
+	"job-portal-api/internal/tracing"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// apiClient sends requests to the whole api, routes and middlewares included, backed by
+// the in-memory repository.
+type apiClient struct {
+	t       *testing.T
+	srv     *httptest.Server
+	metrics *metrics.Metrics
+}
+
+func newAPIClient(t *testing.T, repos repository.Repos, timeouts middlewares.QueryTimeouts) apiClient {
+	gin.SetMode(gin.TestMode)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	a, err := auth.NewAuth(key, &key.PublicKey)
+	require.NoError(t, err)
+	pages, err := pagination.New([]byte("test-secret"), 0, 0)
+	require.NoError(t, err)
+
+	mt := metrics.New()
+	srv := httptest.NewServer(API(a, repos, pages, timeouts, mt))
+	t.Cleanup(srv.Close)
+	return apiClient{t: t, srv: srv, metrics: mt}
+}
+
+// do sends body as json and decodes the response into out, failing the test unless the
+// response has the status wanted.
+func (ac apiClient) do(method, path, token string, body, out interface{}, want int) {
+	ac.t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		require.NoError(ac.t, json.NewEncoder(&buf).Encode(body))
+	}
+	req, err := http.NewRequest(method, ac.srv.URL+path, &buf)
+	require.NoError(ac.t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(ac.t, err)
+	defer resp.Body.Close()
+	var raw json.RawMessage
+	require.NoError(ac.t, json.NewDecoder(resp.Body).Decode(&raw))
+	require.Equal(ac.t, want, resp.StatusCode, "%s %s: %s", method, path, raw)
+	if out != nil {
+		require.NoError(ac.t, json.Unmarshal(raw, out))
+	}
+}
+
+// scrape returns the metrics of the api, as Prometheus would read them.
+func (ac apiClient) scrape() string {
+	ac.t.Helper()
+	resp, err := http.Get(ac.srv.URL + "/metrics")
+	require.NoError(ac.t, err)
+	defer resp.Body.Close()
+	require.Equal(ac.t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(ac.t, err)
+	return string(body)
+}
+
+// signUp registers a user and logs in, returning an access token.
+func (ac apiClient) signUp(email, role string) string {
+	ac.t.Helper()
+	ac.do(http.MethodPost, "/api/register", "", models.NewUser{
+		Name: email, Email: email, Password: "Passw0rd123", Role: role,
+	}, nil, http.StatusOK)
+	var tkn models.TokenResponse
+	ac.do(http.MethodPost, "/api/login", "", map[string]string{"email": email, "password": "Passw0rd123"}, &tkn, http.StatusOK)
+	return tkn.AccessToken
+}
+
+func TestAPI_inMemory(t *testing.T) {
+	ac := newAPIClient(t, repository.NewMemoryRepository(), middlewares.QueryTimeouts{})
+
+	employer := ac.signUp("employer@example.com", models.RoleEmployer)
+	var company models.Companies
+	ac.do(http.MethodPost, "/api/listcompanies", employer, models.NewComapanies{
+		CompanyName: "Acme", FoundedYear: 2001, Location: "Berlin", Address: "1 Main Street",
+	}, &company, http.StatusOK)
+	require.NotZero(t, company.ID)
+
+	var job models.Job
+	ac.do(http.MethodPost, fmt.Sprintf("/companies/%d/jobs", company.ID), employer, models.NewJob{
+		Title: "Backend Engineer", Description: "Build services in golang.", Country: "DE",
+		Skills: []models.NewJobSkill{{Name: "go", Required: true}},
+	}, &job, http.StatusCreated)
+	require.NotZero(t, job.ID)
+
+	candidate := ac.signUp("candidate@example.com", models.RoleCandidate)
+	var jobs []models.Job
+	ac.do(http.MethodGet, "/api/jobs?country=DE", candidate, nil, &jobs, http.StatusOK)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, job.ID, jobs[0].ID)
+	var results []models.JobSearchResult
+	ac.do(http.MethodGet, "/api/jobs/search?q=golang", candidate, nil, &results, http.StatusOK)
+	require.Len(t, results, 1)
+	assert.Contains(t, results[0].Snippet, "<mark>golang</mark>")
+
+	var app models.Application
+	ac.do(http.MethodPost, fmt.Sprintf("/api/jobs/%d/applications", job.ID), candidate, models.NewApplication{
+		ResumeURL: "https://example.com/cv.pdf",
+	}, &app, http.StatusCreated)
+	ac.do(http.MethodPost, fmt.Sprintf("/api/jobs/%d/applications", job.ID), candidate, models.NewApplication{
+		ResumeURL: "https://example.com/cv.pdf",
+	}, nil, http.StatusConflict)
+	var apps []models.Application
+	ac.do(http.MethodGet, "/api/applications", candidate, nil, &apps, http.StatusOK)
+	require.Len(t, apps, 1)
+	assert.Equal(t, app.ID, apps[0].ID)
+
+	ac.do(http.MethodGet, fmt.Sprintf("/api/jobs/%d/applications", job.ID), employer, nil, &apps, http.StatusOK)
+	require.Len(t, apps, 1)
+	ac.do(http.MethodGet, fmt.Sprintf("/api/jobs/%d/applications", job.ID), candidate, nil, nil, http.StatusForbidden)
+}
+
+// stalledQueries makes the queries on table of a sqlite backed repository wait until their
+// context is done, like queries on an overloaded database, before they are sent. It tells
+// when such a query starts and the error its context ended with.
+type stalledQueries struct {
+	started chan struct{}
+	ended   chan error
+}
+
+func TestAPI_recruiterManagesJobs(t *testing.T) {
+	repos := repository.NewMemoryRepository()
+	ac := newAPIClient(t, repos, middlewares.QueryTimeouts{})
+
+	employer := ac.signUp("employer@example.com", models.RoleEmployer)
+	var company models.Companies
+	ac.do(http.MethodPost, "/api/listcompanies", employer, models.NewComapanies{
+		CompanyName: "Acme", FoundedYear: 2001, Location: "Berlin", Address: "1 Main Street",
+	}, &company, http.StatusOK)
+	recruiter := ac.signUp("recruiter@example.com", models.RoleCandidate)
+	u, err := repos.ViewUserByEmail(context.Background(), "recruiter@example.com")
+	require.NoError(t, err)
+	ac.do(http.MethodPost, fmt.Sprintf("/api/companies/%d/members", company.ID), employer, models.NewCompanyMember{
+		UserID: u.ID, Role: models.CompanyRoleRecruiter,
+	}, nil, http.StatusCreated)
+
+	// A recruiter of the company manages its jobs with a candidate account, from creating
+	// them to deleting them.
+	var job models.Job
+	ac.do(http.MethodPost, fmt.Sprintf("/companies/%d/jobs", company.ID), recruiter, models.NewJob{
+		Title: "Backend Engineer", Description: "Build services in golang.",
+	}, &job, http.StatusCreated)
+	title := "Senior Backend Engineer"
+	ac.do(http.MethodPatch, fmt.Sprintf("/api/jobs/%d", job.ID), recruiter, models.JobPatch{Title: &title}, &job, http.StatusOK)
+	assert.Equal(t, title, job.Title)
+	ac.do(http.MethodPost, fmt.Sprintf("/api/jobs/%d/close", job.ID), recruiter, nil, &job, http.StatusOK)
+	assert.Equal(t, models.JobStatusClosed, job.Status)
+
+	// Other users cannot, employers included.
+	outsider := ac.signUp("outsider@example.com", models.RoleEmployer)
+	ac.do(http.MethodPost, fmt.Sprintf("/companies/%d/jobs", company.ID), outsider, models.NewJob{
+		Title: "Backend Engineer", Description: "Build services in golang.",
+	}, nil, http.StatusForbidden)
+	ac.do(http.MethodDelete, fmt.Sprintf("/api/jobs/%d", job.ID), outsider, nil, nil, http.StatusForbidden)
+	candidate := ac.signUp("candidate@example.com", models.RoleCandidate)
+	ac.do(http.MethodPost, fmt.Sprintf("/companies/%d/jobs", company.ID), candidate, models.NewJob{
+		Title: "Backend Engineer", Description: "Build services in golang.",
+	}, nil, http.StatusForbidden)
+}
+
+// openSQLite opens a sqlite database with the schema of the api.
+func openSQLite(t *testing.T) *gorm.DB {
+	db, err := database.Open(config.DBConfig{Driver: config.DriverSQLite, Path: filepath.Join(t.TempDir(), "api.db")})
+	require.NoError(t, err)
+	db.Logger = logger.Discard
+	migrator, err := database.NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+	return db
+}
+
+func newStalledRepo(t *testing.T, table string) (*repository.Repo, stalledQueries) {
+	db := openSQLite(t)
+	sq := stalledQueries{started: make(chan struct{}, 1), ended: make(chan error, 1)}
+	err := db.Callback().Query().Before("gorm:query").Register("test:stall", func(tx *gorm.DB) {
+		if tx.Statement.Table != table {
+			return
+		}
+		sq.started <- struct{}{}
+		select {
+		case <-tx.Statement.Context.Done():
+			sq.ended <- tx.Statement.Context.Err()
+		case <-time.After(10 * time.Second):
+			sq.ended <- nil
+		}
+	})
+	require.NoError(t, err)
+	return &repository.Repo{DB: db}, sq
+}
+
+func TestAPI_routeTimeoutAbortsQuery(t *testing.T) {
+	repo, queries := newStalledRepo(t, "jobs")
+	ac := newAPIClient(t, repo, middlewares.QueryTimeouts{
+		Default: time.Minute,
+		Routes:  map[string]time.Duration{"GET /api/jobs/search": 100 * time.Millisecond},
+	})
+	candidate := ac.signUp("candidate@example.com", models.RoleCandidate)
+
+	start := time.Now()
+	ac.do(http.MethodGet, "/api/jobs/search?q=golang", candidate, nil, nil, http.StatusServiceUnavailable)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.ErrorIs(t, <-queries.ended, context.DeadlineExceeded)
+}
+
+func TestAPI_clientGoneAbortsQuery(t *testing.T) {
+	repo, queries := newStalledRepo(t, "jobs")
+	ac := newAPIClient(t, repo, middlewares.QueryTimeouts{})
+	candidate := ac.signUp("candidate@example.com", models.RoleCandidate)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ac.srv.URL+"/api/jobs/search?q=golang", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+candidate)
+	go func() {
+		<-queries.started
+		cancel()
+	}()
+	_, err = http.DefaultClient.Do(req)
+	require.ErrorIs(t, err, context.Canceled)
+
+	select {
+	case err := <-queries.ended:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the query was not cancelled when the client went away")
+	}
+}
+
+func TestAPI_metrics(t *testing.T) {
+	db := openSQLite(t)
+	ac := newAPIClient(t, &repository.Repo{DB: db}, middlewares.QueryTimeouts{})
+	require.NoError(t, ac.metrics.InstrumentDB(db))
+
+	employer := ac.signUp("employer@example.com", models.RoleEmployer)
+	ac.do(http.MethodPost, "/api/login", "", map[string]string{
+		"email": "employer@example.com", "password": "wrong-password",
+	}, nil, http.StatusUnauthorized)
+	var company models.Companies
+	ac.do(http.MethodPost, "/api/listcompanies", employer, models.NewComapanies{
+		CompanyName: "Acme", FoundedYear: 2001, Location: "Berlin", Address: "1 Main Street",
+	}, &company, http.StatusOK)
+	var job models.Job
+	ac.do(http.MethodPost, fmt.Sprintf("/companies/%d/jobs", company.ID), employer, models.NewJob{
+		Title: "Backend Engineer", Description: "Build services in golang.", Country: "DE",
+	}, &job, http.StatusCreated)
+	ac.do(http.MethodGet, fmt.Sprintf("/api/jobs/%d", job.ID), employer, nil, nil, http.StatusOK)
+	ac.do(http.MethodGet, "/api/nowhere", employer, nil, nil, http.StatusNotFound)
+
+	scraped := strings.Split(ac.scrape(), "\n")
+	for _, line := range []string{
+		`job_portal_registrations_total 1`,
+		`job_portal_logins_failed_total 1`,
+		`job_portal_jobs_posted_total 1`,
+		`job_portal_http_requests_total{method="POST",route="/api/login",status="200"} 1`,
+		`job_portal_http_requests_total{method="POST",route="/api/login",status="401"} 1`,
+		`job_portal_http_requests_total{method="GET",route="/api/jobs/:jobID",status="200"} 1`,
+		`job_portal_http_requests_total{method="",route="unmatched",status="404"} 1`,
+		`job_portal_http_request_duration_seconds_count{method="POST",route="/companies/:companyID/jobs",status="201"} 1`,
+		// The scrape itself.
+		`job_portal_http_requests_in_flight{method="GET",route="/metrics"} 1`,
+		`job_portal_http_requests_in_flight{method="POST",route="/api/register"} 0`,
+		`job_portal_db_query_duration_seconds_count{operation="create",table="jobs"} 1`,
+		`go_sql_max_open_connections{db_name="sqlite"} 0`,
+	} {
+		assert.Contains(t, scraped, line)
+	}
+}
+
+func TestAPI_tracing(t *testing.T) {
+	tp, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(tp)
+		otel.SetTextMapPropagator(propagator)
+	})
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	db := openSQLite(t)
+	require.NoError(t, tracing.InstrumentDB(db))
+	ac := newAPIClient(t, &repository.Repo{DB: db}, middlewares.QueryTimeouts{})
+	employer := ac.signUp("employer@example.com", models.RoleEmployer)
+	var company models.Companies
+	ac.do(http.MethodPost, "/api/listcompanies", employer, models.NewComapanies{
+		CompanyName: "Acme", FoundedYear: 2001, Location: "Berlin", Address: "1 Main Street",
+	}, &company, http.StatusOK)
+	var job models.Job
+	ac.do(http.MethodPost, fmt.Sprintf("/companies/%d/jobs", company.ID), employer, models.NewJob{
+		Title: "Backend Engineer", Description: "Build services in golang.", Country: "DE",
+	}, &job, http.StatusCreated)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/jobs/%d", ac.srv.URL, job.ID), nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+employer)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, traceID, resp.Header.Get("X-Trace-Id"))
+	assert.True(t, strings.HasPrefix(resp.Header.Get("traceparent"), "00-"+traceID+"-"))
+
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range sr.Ended() {
+		if span.SpanContext().TraceID().String() == traceID {
+			byName[span.Name()] = span
+		}
+	}
+	server, service, query := byName["GET /api/jobs/:jobID"], byName["JobService.JobsByID"], byName["db.query jobs"]
+	require.NotNil(t, server, "no span for the request in %v", byName)
+	require.NotNil(t, service, "no span for the service in %v", byName)
+	require.NotNil(t, query, "no span for the query in %v", byName)
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+	assert.Equal(t, server.SpanContext().SpanID(), service.Parent().SpanID())
+	assert.Equal(t, service.SpanContext().SpanID(), query.Parent().SpanID())
+}
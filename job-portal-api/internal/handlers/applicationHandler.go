@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/auth"
+	middlewares "job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+func (h *handler) Apply(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceID).Msg("login first")
+		apperr.Abort(c, traceID, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	jobID, err := strconv.ParseUint(c.Param("jobID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.Abort(c, traceID, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	var na models.NewApplication
+	if !bindJSON(c, traceID, &na) {
+		return
+	}
+
+	app, err := h.as.Apply(ctx, jobID, na, claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Failed to apply")
+		return
+	}
+
+	c.JSON(http.StatusCreated, app)
+}
+
+func (h *handler) MyApplications(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceID).Msg("login first")
+		apperr.Abort(c, traceID, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	p, ok := h.pageRequest(c, traceID, models.SortNewest, models.SortOldest)
+	if !ok {
+		return
+	}
+
+	apps, info, err := h.as.MyApplications(ctx, p, claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Failed to fetch applications")
+		return
+	}
+	h.setPageLinks(c, traceID, info)
+
+	c.JSON(http.StatusOK, apps)
+}
+
+func (h *handler) JobApplications(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceID).Msg("login first")
+		apperr.Abort(c, traceID, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	jobID, err := strconv.ParseUint(c.Param("jobID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.Abort(c, traceID, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	p, ok := h.pageRequest(c, traceID, models.SortOldest, models.SortNewest)
+	if !ok {
+		return
+	}
+
+	apps, info, err := h.as.JobApplications(ctx, jobID, p, claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Failed to fetch applications")
+		return
+	}
+	h.setPageLinks(c, traceID, info)
+
+	c.JSON(http.StatusOK, apps)
+}
+
+func (h *handler) WithdrawApplication(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceID).Msg("login first")
+		apperr.Abort(c, traceID, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	applicationID, err := strconv.ParseUint(c.Param("applicationID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.Abort(c, traceID, http.StatusBadRequest, "Invalid application ID")
+		return
+	}
+
+	app, err := h.as.WithdrawApplication(ctx, uint(applicationID), claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Failed to withdraw application")
+		return
+	}
+
+	c.JSON(http.StatusOK, app)
+}
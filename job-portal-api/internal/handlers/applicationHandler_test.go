@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/services"
+	"net/http"
+	"testing"
+)
+
+func Test_handler_Apply(t *testing.T) {
+	na := models.NewApplication{CoverLetter: "hire me", ResumeURL: "https://cv.example.com/me.pdf"}
+	tests := []struct {
+		name               string
+		jobID              string
+		body               string
+		setup              func(ms *services.MockApplicationService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "invalid job id",
+			jobID:              "abc",
+			body:               `{"resume_url":"https://cv.example.com/me.pdf"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Invalid job ID","trace_id":"123"}`,
+		},
+		{
+			name:               "resume link is not a url",
+			jobID:              "5",
+			body:               `{"resume_url":"my resume"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"resume_url","message":"must be an http or https url"}]}`,
+		},
+		{
+			name:  "already applied",
+			jobID: "5",
+			body:  `{"cover_letter":"hire me","resume_url":"https://cv.example.com/me.pdf"}`,
+			setup: func(ms *services.MockApplicationService) {
+				ms.EXPECT().Apply(gomock.Any(), uint64(5), na, "1").
+					Return(models.Application{}, fmt.Errorf("already applied to job 5: %w", apperr.ErrConflict))
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   `{"type":"about:blank","title":"Conflict","status":409,"detail":"already applied to job 5: conflict","trace_id":"123"}`,
+		},
+		{
+			name:  "success",
+			jobID: "5",
+			body:  `{"cover_letter":"hire me","resume_url":"https://cv.example.com/me.pdf"}`,
+			setup: func(ms *services.MockApplicationService) {
+				ms.EXPECT().Apply(gomock.Any(), uint64(5), na, "1").Return(models.Application{
+					JobID: 5, UserID: 1, CoverLetter: "hire me", ResumeURL: "https://cv.example.com/me.pdf", Status: models.ApplicationSubmitted, Stage: "applied",
+				}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponse:   `{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"job_id":5,"user_id":1,"cover_letter":"hire me","resume_url":"https://cv.example.com/me.pdf","status":"submitted","stage":"applied"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, rr := newJobRequest(http.MethodPost, tt.jobID, tt.body)
+			ms := services.NewMockApplicationService(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+
+			h := &handler{
+				as: ms,
+			}
+			h.Apply(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_JobApplications(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, rr := newJobRequest(http.MethodGet, "5", "")
+	ms := services.NewMockApplicationService(gomock.NewController(t))
+	ms.EXPECT().JobApplications(gomock.Any(), uint64(5), gomock.Any(), "1").Return(nil, models.PageInfo{}, fmt.Errorf("company 1: %w", apperr.ErrForbidden))
+
+	h := &handler{
+		as: ms,
+	}
+	h.JobApplications(c)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Equal(t, `{"type":"about:blank","title":"Forbidden","status":403,"detail":"company 1: forbidden","trace_id":"123"}`, rr.Body.String())
+}
+
+func Test_handler_MyApplications(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, rr := newJobRequest(http.MethodGet, "", "")
+	ms := services.NewMockApplicationService(gomock.NewController(t))
+	ms.EXPECT().MyApplications(gomock.Any(), models.PageRequest{Limit: 20, Sort: models.SortNewest}, "1").Return([]models.Application{
+		{JobID: 5, UserID: 1, ResumeURL: "https://cv.example.com/me.pdf", Status: models.ApplicationInReview, Stage: "interview", Job: &models.Job{Title: "SDE", CompanyID: 2, Status: models.JobStatusOpen}},
+	}, models.PageInfo{}, nil)
+
+	h := &handler{
+		as: ms,
+	}
+	h.MyApplications(c)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `[{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"job_id":5,"user_id":1,"resume_url":"https://cv.example.com/me.pdf","status":"in_review","stage":"interview","job":{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"title":"SDE","description":"","CompanyID":2,"status":"open"}}]`, rr.Body.String())
+}
+
+func Test_handler_WithdrawApplication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, rr := newJobRequest(http.MethodPost, "", "")
+	c.Params = gin.Params{{Key: "applicationID", Value: "7"}}
+	ms := services.NewMockApplicationService(gomock.NewController(t))
+	ms.EXPECT().WithdrawApplication(gomock.Any(), uint(7), "1").
+		Return(models.Application{}, fmt.Errorf("application 7 is rejected: %w", apperr.ErrValidation))
+
+	h := &handler{
+		as: ms,
+	}
+	h.WithdrawApplication(c)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, `{"type":"about:blank","title":"Bad Request","status":400,"detail":"application 7 is rejected: validation failed","trace_id":"123"}`, rr.Body.String())
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/validation"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// maxBodySize is the largest request body the api reads.
+const maxBodySize = 1 << 20
+
+// invalidFields is the detail of the problem describing a request body that decoded but
+// broke some of its rules.
+const invalidFields = "The request has invalid fields"
+
+// errBodyTooLarge is returned by decodeJSON for bodies above maxBodySize.
+var errBodyTooLarge = fmt.Errorf("request body is larger than %d bytes", maxBodySize)
+
+// bindJSON decodes the body of the request into dst and validates it. It aborts the request
+// with a problem naming the fields at fault when the body is not valid.
+func bindJSON(c *gin.Context, traceID string, dst interface{}) bool {
+	err := decodeJSON(c, dst)
+	if errors.Is(err, io.EOF) {
+		err = apperr.Wrap(apperr.ErrValidation, err, "Request body is empty")
+	}
+	if err == nil {
+		err = validation.Struct(dst, invalidFields)
+	}
+	if err == nil {
+		return true
+	}
+	abortWithBodyError(c, traceID, err)
+	return false
+}
+
+// abortWithBodyError ends a request whose body could not be read into a value.
+func abortWithBodyError(c *gin.Context, traceID string, err error) {
+	log.Error().Err(err).Str("Trace Id", traceID).Send()
+	if errors.Is(err, errBodyTooLarge) {
+		apperr.Abort(c, traceID, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+	apperr.AbortWithError(c, traceID, err, "Invalid request body")
+}
+
+// decodeJSON decodes a single json value from the body of the request into dst. Fields dst
+// does not know and bodies above maxBodySize are rejected rather than ignored. An empty
+// body is reported as io.EOF.
+func decodeJSON(c *gin.Context, dst interface{}) error {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBodySize)
+	dec := json.NewDecoder(c.Request.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(dst)
+	if err == nil && dec.More() {
+		err = errors.New("request body must hold a single json value")
+	}
+	if errors.Is(err, io.EOF) {
+		return err
+	}
+	if err != nil {
+		return decodeError(err)
+	}
+	return nil
+}
+
+// decodeError turns an error of the json decoder into one that can be shown to clients.
+func decodeError(err error) error {
+	var (
+		tooLarge  *http.MaxBytesError
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &tooLarge):
+		return errBodyTooLarge
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return apperr.Wrap(apperr.ErrValidation, err, "Request body is not valid json")
+	case errors.As(err, &typeErr) && typeErr.Field == "":
+		return apperr.Wrap(apperr.ErrValidation, err, "Request body must be "+jsonType(typeErr.Type))
+	case errors.As(err, &typeErr):
+		return &apperr.Error{
+			Kind:   apperr.ErrValidation,
+			Detail: invalidFields,
+			Fields: []apperr.FieldError{{Field: typeErr.Field, Message: "must be " + jsonType(typeErr.Type)}},
+			Cause:  err,
+		}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return apperr.Invalid(invalidFields, apperr.FieldError{Field: field, Message: "is not a known field"})
+	}
+	return apperr.Wrap(apperr.ErrValidation, err, "Invalid request body")
+}
+
+// jsonType names the json type that decodes into a value of type t.
+func jsonType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	}
+	return "an object"
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"job-portal-api/internal/models"
+)
+
+func Test_bindJSON(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		wantOK             bool
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:   "valid body",
+			body:   `{"name":"Asha","email":"asha@example.com","password":"Secret123"}`,
+			wantOK: true,
+		},
+		{
+			name:               "empty body",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Request body is empty","trace_id":"123"}`,
+		},
+		{
+			name:               "unknown field",
+			body:               `{"name":"Asha","email":"asha@example.com","password":"Secret123","admin":true}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"admin","message":"is not a known field"}]}`,
+		},
+		{
+			name:               "wrong type",
+			body:               `{"name":42,"email":"asha@example.com","password":"Secret123"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"name","message":"must be a string"}]}`,
+		},
+		{
+			name:               "more than one value",
+			body:               `{"name":"Asha","email":"asha@example.com","password":"Secret123"} {}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Invalid request body","trace_id":"123"}`,
+		},
+		{
+			name:               "rules broken",
+			body:               `{"name":"Asha","email":"asha","password":"secret"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"email","message":"must be a valid email address"},{"field":"password","message":"must be 8 to 72 characters long and contain an upper case letter, a lower case letter and a digit"}]}`,
+		},
+		{
+			name:               "body too large",
+			body:               `{"name":"` + strings.Repeat("a", maxBodySize) + `"}`,
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+			expectedResponse:   `{"type":"about:blank","title":"Request Entity Too Large","status":413,"detail":"request body is larger than 1048576 bytes","trace_id":"123"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			c.Request = httptest.NewRequest(http.MethodPost, "http://test.com", bytes.NewBufferString(tt.body))
+
+			var nu models.NewUser
+			ok := bindJSON(c, "123", &nu)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, models.NewUser{Name: "Asha", Email: "asha@example.com", Password: "Secret123"}, nu)
+				return
+			}
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/auth"
+	middlewares "job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+func (h *handler) UpdateCompany(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceID).Msg("login first")
+		apperr.Abort(c, traceID, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	companyID, err := strconv.ParseUint(c.Param("companyID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.Abort(c, traceID, http.StatusBadRequest, "Invalid company ID")
+		return
+	}
+
+	var uc models.UpdateCompany
+	if !bindJSON(c, traceID, &uc) {
+		return
+	}
+
+	company, err := h.companies.UpdateCompany(ctx, uint(companyID), uc, claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Failed to update company")
+		return
+	}
+
+	c.JSON(http.StatusOK, company)
+}
+
+func (h *handler) PatchCompany(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceID).Msg("login first")
+		apperr.Abort(c, traceID, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	companyID, err := strconv.ParseUint(c.Param("companyID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.Abort(c, traceID, http.StatusBadRequest, "Invalid company ID")
+		return
+	}
+
+	var cp models.CompanyPatch
+	if !bindJSON(c, traceID, &cp) {
+		return
+	}
+
+	company, err := h.companies.PatchCompany(ctx, uint(companyID), cp, claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Failed to update company")
+		return
+	}
+
+	c.JSON(http.StatusOK, company)
+}
+
+func (h *handler) DeleteCompany(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceID).Msg("login first")
+		apperr.Abort(c, traceID, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	companyID, err := strconv.ParseUint(c.Param("companyID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.Abort(c, traceID, http.StatusBadRequest, "Invalid company ID")
+		return
+	}
+
+	err = h.companies.DeleteCompany(ctx, uint(companyID), claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Failed to delete company")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RestoreCompany undeletes a company, its jobs are restored too when ?jobs=true is given.
+func (h *handler) RestoreCompany(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	companyID, err := strconv.ParseUint(c.Param("companyID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.Abort(c, traceID, http.StatusBadRequest, "Invalid company ID")
+		return
+	}
+	restoreJobs := false
+	if v := c.Query("jobs"); v != "" {
+		restoreJobs, err = strconv.ParseBool(v)
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceID).Send()
+			apperr.Abort(c, traceID, http.StatusBadRequest, "Invalid jobs parameter")
+			return
+		}
+	}
+
+	company, err := h.companies.RestoreCompany(ctx, uint(companyID), restoreJobs)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Failed to restore company")
+		return
+	}
+
+	c.JSON(http.StatusOK, company)
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/auth"
+	middlewares "job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newCompanyRequest builds a context for a request on /api/companies/:companyID made by user 1.
+func newCompanyRequest(method, target, companyID, body string) (*gin.Context, *httptest.ResponseRecorder) {
+	rr := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rr)
+	httpRequest, _ := http.NewRequest(method, "http://test.com:8080"+target, bytes.NewBufferString(body))
+	ctx := httpRequest.Context()
+	ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+	ctx = context.WithValue(ctx, auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}})
+	c.Request = httpRequest.WithContext(ctx)
+	c.Params = append(c.Params, gin.Param{Key: "companyID", Value: companyID})
+	return c, rr
+}
+
+func Test_handler_UpdateCompany(t *testing.T) {
+	uc := models.UpdateCompany{CompanyName: "tek", FoundedYear: 2001, Location: "blr", Address: "mg road"}
+	tests := []struct {
+		name               string
+		companyID          string
+		body               string
+		setup              func(ms *services.MockCompanyService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "invalid company id",
+			companyID:          "abc",
+			body:               `{}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Invalid company ID","trace_id":"123"}`,
+		},
+		{
+			name:               "missing fields",
+			companyID:          "1",
+			body:               `{"company_name":"tek"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"founded_year","message":"is required"},{"field":"location","message":"is required"},{"field":"address","message":"is required"}]}`,
+		},
+		{
+			name:      "not an owner",
+			companyID: "1",
+			body:      `{"company_name":"tek","founded_year":2001,"location":"blr","address":"mg road"}`,
+			setup: func(ms *services.MockCompanyService) {
+				ms.EXPECT().UpdateCompany(gomock.Any(), uint(1), uc, "1").Return(models.Companies{}, apperr.ErrForbidden)
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   `{"type":"about:blank","title":"Forbidden","status":403,"trace_id":"123"}`,
+		},
+		{
+			name:      "success",
+			companyID: "1",
+			body:      `{"company_name":"tek","founded_year":2001,"location":"blr","address":"mg road"}`,
+			setup: func(ms *services.MockCompanyService) {
+				ms.EXPECT().UpdateCompany(gomock.Any(), uint(1), uc, "1").
+					Return(models.Companies{CompanyName: "tek", FoundedYear: 2001, Location: "blr", Address: "mg road", UserId: 1}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"company_name":"tek","founded_year":2001,"location":"blr","user_id":1,"address":"mg road"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, rr := newCompanyRequest(http.MethodPut, "", tt.companyID, tt.body)
+			ms := services.NewMockCompanyService(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+
+			h := &handler{
+				companies: ms,
+			}
+			h.UpdateCompany(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_PatchCompany(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, rr := newCompanyRequest(http.MethodPatch, "", "1", `{"location":""}`)
+	ms := services.NewMockCompanyService(gomock.NewController(t))
+
+	h := &handler{
+		companies: ms,
+	}
+	h.PatchCompany(c)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"location","message":"must not be empty"}]}`, rr.Body.String())
+}
+
+func Test_handler_DeleteCompany(t *testing.T) {
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "success",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:               "company not found",
+			err:                apperr.ErrNotFound,
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"type":"about:blank","title":"Not Found","status":404,"trace_id":"123"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, rr := newCompanyRequest(http.MethodDelete, "", "1", "")
+			ms := services.NewMockCompanyService(gomock.NewController(t))
+			ms.EXPECT().DeleteCompany(gomock.Any(), uint(1), "1").Return(tt.err)
+
+			h := &handler{
+				companies: ms,
+			}
+			h.DeleteCompany(c)
+			c.Writer.WriteHeaderNow()
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_RestoreCompany(t *testing.T) {
+	tests := []struct {
+		name               string
+		target             string
+		setup              func(ms *services.MockCompanyService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "invalid jobs parameter",
+			target:             "/?jobs=maybe",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Invalid jobs parameter","trace_id":"123"}`,
+		},
+		{
+			name:   "restores jobs too",
+			target: "/?jobs=true",
+			setup: func(ms *services.MockCompanyService) {
+				ms.EXPECT().RestoreCompany(gomock.Any(), uint(1), true).Return(models.Companies{CompanyName: "tek"}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"company_name":"tek","founded_year":0,"location":"","user_id":0,"address":""}`,
+		},
+		{
+			name:   "not deleted",
+			target: "/",
+			setup: func(ms *services.MockCompanyService) {
+				ms.EXPECT().RestoreCompany(gomock.Any(), uint(1), false).Return(models.Companies{}, apperr.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"type":"about:blank","title":"Not Found","status":404,"trace_id":"123"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, rr := newCompanyRequest(http.MethodPost, tt.target, "1", "")
+			ms := services.NewMockCompanyService(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+
+			h := &handler{
+				companies: ms,
+			}
+			h.RestoreCompany(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
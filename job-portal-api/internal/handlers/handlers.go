@@ -3,49 +3,135 @@ package handlers
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
+	"job-portal-api/internal/apperr"
 	"job-portal-api/internal/auth"
+	"job-portal-api/internal/metrics"
 	"job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/pagination"
 	"job-portal-api/internal/repository"
 	"job-portal-api/internal/services"
 	"net/http"
 	"time"
 )
 
-func API(a *auth.Auth, c repository.UserRepo) *gin.Engine {
+// API routes the requests of the job portal. mt counts them and is served at /metrics.
+func API(a *auth.Auth, repos repository.Repos, pages pagination.Paginator, timeouts middlewares.QueryTimeouts,
+	mt *metrics.Metrics) *gin.Engine {
 	r := gin.New()
 
-	m, err := middlewares.NewMid(a)
-	if err != nil {
-		log.Error().Err(err).Msg("Error setting up middlewares")
+	if mt == nil {
+		log.Error().Msg("Error setting up metrics: metrics can't be nil")
 		return nil
 	}
 
-	ms, err := services.NewStore(c)
+	users, err := services.NewUserStore(repos)
 	if err != nil {
 		log.Error().Err(err).Msg("Error setting up services")
 		return nil
 	}
+	companies, err := services.NewCompanyStore(repos, repos, repos)
+	if err != nil {
+		log.Error().Err(err).Msg("Error setting up services")
+		return nil
+	}
+	jobs, err := services.NewJobStore(repos, repos)
+	if err != nil {
+		log.Error().Err(err).Msg("Error setting up services")
+		return nil
+	}
+	as, err := services.NewApplicationStore(repos, repos, repos)
+	if err != nil {
+		log.Error().Err(err).Msg("Error setting up services")
+		return nil
+	}
+
+	// Every service method runs in a span, between the span of the request and the ones
+	// of its queries.
+	users = services.TraceUserService(users)
+	companies = services.TraceCompanyService(companies)
+	jobs = services.TraceJobService(jobs)
+	as = services.TraceApplicationService(as)
+
+	m, err := middlewares.NewMid(a, users)
+	if err != nil {
+		log.Error().Err(err).Msg("Error setting up middlewares")
+		return nil
+	}
 
 	h := handler{
-		s: ms,
-		a: a,
+		users:     users,
+		companies: companies,
+		jobs:      jobs,
+		as:        as,
+		a:         a,
+		pages:     pages,
+		metrics:   mt,
 	}
 
-	r.Use(m.Log(), gin.Recovery())
+	r.Use(m.Log(), middlewares.Metrics(mt), gin.Recovery(), middlewares.Timeout(timeouts))
+	r.GET("/metrics", gin.WrapH(mt.Handler()))
+	r.GET("/.well-known/jwks.json", h.JWKS)
 	r.GET("/api/check", m.Authenticate(check))
 	r.POST("/api/register", h.Register)
 	r.POST("/api/login", h.Login)
-	r.POST("/api/listcompanies", m.Authenticate(h.AddCompanies))
+	r.POST("/api/token/refresh", h.RefreshToken)
+	r.POST("/api/logout", m.Authenticate(h.Logout))
+	candidate := m.Require(models.RoleCandidate)
+	employer := m.Require(models.RoleEmployer)
+	admin := m.Require(models.RoleAdmin)
+
+	r.POST("/api/listcompanies", m.Authenticate(employer(h.AddCompanies)))
 	r.GET("/api/viewcompanies", m.Authenticate(h.ViewCompanies))
 	r.GET("/api/companies/:companyID", m.Authenticate(h.ViewCompaniesById))
+	r.PUT("/api/companies/:companyID", m.Authenticate(h.UpdateCompany))
+	r.PATCH("/api/companies/:companyID", m.Authenticate(h.PatchCompany))
+	r.DELETE("/api/companies/:companyID", m.Authenticate(h.DeleteCompany))
+	r.POST("/api/companies/:companyID/restore", m.Authenticate(admin(h.RestoreCompany)))
+	r.POST("/api/companies/:companyID/members", m.Authenticate(h.AddCompanyMember))
+	r.GET("/api/companies/:companyID/pipeline", m.Authenticate(h.ViewPipeline))
+	r.PUT("/api/companies/:companyID/pipeline", m.Authenticate(h.SetPipeline))
+	// Jobs are managed by the owner and recruiters of their company, whatever their global
+	// role, so the job routes leave the check to the services.
 	r.POST("/companies/:companyID/jobs", m.Authenticate(h.CreateJob))
 	r.GET("api/companies/:companyID/list-jobs", m.Authenticate(h.ListJobs))
 	r.GET("api/jobs", m.Authenticate(h.AllJobs))
+	r.GET("/api/jobs/search", m.Authenticate(h.SearchJobs))
+	r.GET("/api/jobs/facets", m.Authenticate(h.JobFacets))
 	r.GET("/api/jobs/:jobID", m.Authenticate(h.JobsByID))
+	r.PUT("/api/jobs/:jobID", m.Authenticate(h.UpdateJob))
+	r.PATCH("/api/jobs/:jobID", m.Authenticate(h.PatchJob))
+	r.DELETE("/api/jobs/:jobID", m.Authenticate(h.DeleteJob))
+	r.POST("/api/jobs/:jobID/close", m.Authenticate(h.CloseJob))
+	r.POST("/api/jobs/:jobID/restore", m.Authenticate(admin(h.RestoreJob)))
+	r.POST("/api/jobs/:jobID/applications", m.Authenticate(candidate(h.Apply)))
+	r.GET("/api/jobs/:jobID/applications", m.Authenticate(h.JobApplications))
+	r.GET("/api/applications", m.Authenticate(h.MyApplications))
+	r.POST("/api/applications/:applicationID/withdraw", m.Authenticate(h.WithdrawApplication))
+	r.GET("/api/applications/:applicationID/history", m.Authenticate(h.ApplicationHistory))
+	r.POST("/api/applications/move", m.Authenticate(h.MoveApplications))
+	r.POST("/api/applications/reject", m.Authenticate(h.RejectApplications))
+	r.PUT("/api/admin/users/:userID/role", m.Authenticate(admin(h.UpdateUserRole)))
+	r.NoRoute(noRoute)
 
+	routes := make(map[string]bool)
+	for _, ri := range r.Routes() {
+		routes[ri.Method+" "+ri.Path] = true
+	}
+	for route := range timeouts.Routes {
+		if !routes[route] {
+			log.Warn().Str("route", route).Msg("a query timeout is set for a route the api does not serve")
+		}
+	}
 	return r
 }
 
+// noRoute answers requests for unknown paths with a problem like every other error.
+func noRoute(c *gin.Context) {
+	traceID, _ := c.Request.Context().Value(middlewares.TraceIdKey).(string)
+	apperr.Abort(c, traceID, http.StatusNotFound, "no route for "+c.Request.Method+" "+c.Request.URL.Path)
+}
+
 func check(c *gin.Context) {
 	time.Sleep(time.Second * 3)
 	select {
@@ -1,17 +1,16 @@
 package handlers
 
 import (
-	"encoding/json"
+	"job-portal-api/internal/apperr"
 	"job-portal-api/internal/auth"
 	middlewares "job-portal-api/internal/middleware"
 	"job-portal-api/internal/models"
+	"job-portal-api/internal/validation"
 	"net/http"
 
 	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/rs/zerolog/log"
 )
 
@@ -20,44 +19,31 @@ func (h *handler) AddCompanies(c *gin.Context) {
 	traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
 	if !ok {
 		log.Error().Msg("traceId missing from context")
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
-	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
 	if !ok {
 		log.Error().Str("Trace Id", traceId).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		apperr.Abort(c, traceId, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
 		return
 	}
 
 	var newCom models.NewComapanies
-	err := json.NewDecoder(c.Request.Body).Decode(&newCom)
-	if err != nil {
-		log.Error().Str("Trace Id", traceId).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
-		return
-	}
-	validate := validator.New()
-	err = validate.Struct(newCom)
-
-	if err != nil {
-		log.Error().Err(err).Str("Trace Id", traceId).Send()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"msg": "please provide all deatails"})
+	if !bindJSON(c, traceId, &newCom) {
 		return
 	}
 	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
 	if err != nil {
 		log.Error().Err(err).Str("Trace Id", traceId)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.
-			StatusInternalServerError)})
+		apperr.Abort(c, traceId, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
-	comp, err := h.s.CreatCompanies(ctx, newCom, uint(uid))
+	comp, err := h.companies.CreatCompanies(ctx, newCom, uint(uid))
 	if err != nil {
 		log.Error().Err(err).Str("Trace Id", traceId)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": "Company creation failed"})
+		apperr.AbortWithError(c, traceId, err, "Company creation failed")
 		return
 	}
 
@@ -70,23 +56,28 @@ func (h *handler) ViewCompanies(c *gin.Context) {
 	traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
 	if !ok {
 		log.Error().Msg("traceId missing from context")
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
-	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
 	if !ok {
 		log.Error().Str("Trace Id", traceId).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		apperr.Abort(c, traceId, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+	p, ok := h.pageRequest(c, traceId, models.SortNewest, models.SortOldest)
+	if !ok {
 		return
 	}
-	companyList, err := h.s.ViewCompanies(ctx, claims.Subject)
+	companyList, info, err := h.companies.ViewCompanies(ctx, p, claims.Subject)
 
 	if err != nil {
 		log.Error().Err(err).Str("Trace Id", traceId)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "problem in viewing company"})
+		apperr.AbortWithError(c, traceId, err, "problem in viewing company")
 		return
 	}
+	h.setPageLinks(c, traceId, info)
 	m := gin.H{"companies list": companyList}
 	c.JSON(http.StatusOK, m)
 }
@@ -95,14 +86,14 @@ func (h *handler) ViewCompaniesById(c *gin.Context) {
 	traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
 	if !ok {
 		log.Error().Msg("traceId missing from context")
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
-	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
 	if !ok {
 		log.Error().Str("Trace Id", traceId).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		apperr.Abort(c, traceId, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
 		return
 	}
 
@@ -111,41 +102,76 @@ func (h *handler) ViewCompaniesById(c *gin.Context) {
 	companyID, err := strconv.ParseUint(companyIDs, 10, 64)
 	if err != nil {
 		log.Error().Err(err).Str("Trace Id", traceId)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+		apperr.Abort(c, traceId, http.StatusBadRequest, "Invalid company ID")
 		return
 	}
 
-	company, err := h.s.ViewCompaniesById(ctx, uint(companyID), claims.Subject)
+	company, err := h.companies.ViewCompaniesById(ctx, uint(companyID), claims.Subject)
 	if err != nil {
 		log.Error().Err(err).Str("Trace Id", traceId)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "problem in fetching company details"})
+		apperr.AbortWithError(c, traceId, err, "problem in fetching company details")
 		return
 	}
 
 	c.JSON(http.StatusOK, company)
 }
+func (h *handler) AddCompanyMember(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		apperr.Abort(c, traceId, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	companyID, err := strconv.ParseUint(c.Param("companyID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		apperr.Abort(c, traceId, http.StatusBadRequest, "Invalid company ID")
+		return
+	}
+
+	var nm models.NewCompanyMember
+	if !bindJSON(c, traceId, &nm) {
+		return
+	}
+
+	member, err := h.companies.AddCompanyMember(ctx, uint(companyID), nm, claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		apperr.AbortWithError(c, traceId, err, "Failed to add company member")
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
 func (h *handler) CreateJob(c *gin.Context) {
 	ctx := c.Request.Context()
 	traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
 	if !ok {
 		log.Error().Msg("traceId missing from context")
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
-	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
 	if !ok {
 		log.Error().Str("Trace Id", traceId).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		apperr.Abort(c, traceId, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
 		return
 	}
 
 	// Parse the request body to get the job details
-	var newJob models.Job
-	err := c.BindJSON(&newJob)
-	if err != nil {
-		log.Error().Err(err).Str("Trace Id", traceId).Msg("failed to parse request body")
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	var nj models.NewJob
+	if !bindJSON(c, traceId, &nj) {
 		return
 	}
 
@@ -154,18 +180,19 @@ func (h *handler) CreateJob(c *gin.Context) {
 	companyID, err := strconv.ParseUint(companyIDStr, 10, 64)
 	if err != nil {
 		log.Error().Err(err).Str("Trace Id", traceId)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+		apperr.Abort(c, traceId, http.StatusBadRequest, "Invalid company ID")
 		return
 	}
-	newJob.CompanyID = uint(companyID)
+	newJob := nj.Job(uint(companyID))
 
 	// Create the job
-	createdJob, err := h.s.CreateJob(ctx, newJob, claims.Subject)
+	createdJob, err := h.jobs.CreateJob(ctx, newJob, claims.Subject)
 	if err != nil {
-		log.Error().Err(err).Str("Trace Id", traceId)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job"})
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		apperr.AbortWithError(c, traceId, err, "Failed to create job")
 		return
 	}
+	h.metrics.JobPosted()
 
 	c.JSON(http.StatusCreated, createdJob)
 }
@@ -174,13 +201,13 @@ func (h *handler) ListJobs(c *gin.Context) {
 	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
 	if !ok {
 		log.Error().Msg("traceId missing from context")
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
-	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
 	if !ok {
 		log.Error().Str("Trace Id", traceID).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		apperr.Abort(c, traceID, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
 		return
 	}
 
@@ -188,16 +215,22 @@ func (h *handler) ListJobs(c *gin.Context) {
 	companyID, err := strconv.ParseUint(companyIDStr, 10, 64)
 	if err != nil {
 		log.Error().Err(err).Str("Trace Id", traceID)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "problem in viewing job"})
+		apperr.Abort(c, traceID, http.StatusBadRequest, "problem in viewing job")
 		return
 	}
 
-	jobs, err := h.s.ListJobs(ctx, uint(companyID), claims.Subject)
+	p, ok := h.pageRequest(c, traceID, models.SortNewest, models.SortOldest, models.SortSalary)
+	if !ok {
+		return
+	}
+
+	jobs, info, err := h.jobs.ListJobs(ctx, uint(companyID), p, claims.Subject)
 	if err != nil {
 		log.Error().Err(err).Str("Trace Id", traceID)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch jobs"})
+		apperr.AbortWithError(c, traceID, err, "Failed to fetch jobs")
 		return
 	}
+	h.setPageLinks(c, traceID, info)
 
 	c.JSON(http.StatusOK, jobs)
 }
@@ -206,22 +239,32 @@ func (h *handler) AllJobs(c *gin.Context) {
 	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
 	if !ok {
 		log.Error().Msg("traceId missing from context")
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
-	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
 	if !ok {
 		log.Error().Str("Trace Id", traceID).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		apperr.Abort(c, traceID, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
 		return
 	}
 
-	jobs, err := h.s.AllJob(ctx, claims.Subject)
+	q, ok := jobQuery(c, traceID)
+	if !ok {
+		return
+	}
+	p, ok := h.pageRequest(c, traceID, models.SortNewest, models.SortOldest, models.SortSalary)
+	if !ok {
+		return
+	}
+
+	jobs, info, err := h.jobs.AllJob(ctx, q, p, claims.Subject)
 	if err != nil {
 		log.Error().Err(err).Str("Trace Id", traceID)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch jobs"})
+		apperr.AbortWithError(c, traceID, err, "Failed to fetch jobs")
 		return
 	}
+	h.setPageLinks(c, traceID, info)
 
 	c.JSON(http.StatusOK, jobs)
 }
@@ -231,13 +274,13 @@ func (h *handler) JobsByID(c *gin.Context) {
 	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
 	if !ok {
 		log.Error().Msg("traceId missing from context")
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
-	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
 	if !ok {
 		log.Error().Str("Trace Id", traceID).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		apperr.Abort(c, traceID, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
 		return
 	}
 
@@ -245,16 +288,90 @@ func (h *handler) JobsByID(c *gin.Context) {
 	jobID, err := strconv.ParseUint(jobIDStr, 10, 64)
 	if err != nil {
 		log.Error().Err(err).Str("Trace Id", traceID)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		apperr.Abort(c, traceID, http.StatusBadRequest, "Invalid job ID")
 		return
 	}
 
-	job, err := h.s.JobsByID(ctx, jobID, claims.Subject)
+	job, err := h.jobs.JobsByID(ctx, jobID, claims.Subject)
 	if err != nil {
 		log.Error().Err(err).Str("Trace Id", traceID)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch job"})
+		apperr.AbortWithError(c, traceID, err, "Failed to fetch job")
 		return
 	}
 
 	c.JSON(http.StatusOK, job)
 }
+
+// JobFacets handles GET /api/jobs/facets, it takes the same filters as GET /api/jobs.
+func (h *handler) JobFacets(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	q, ok := jobQuery(c, traceID)
+	if !ok {
+		return
+	}
+
+	facets, err := h.jobs.JobFacets(ctx, q)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Failed to count jobs")
+		return
+	}
+
+	c.JSON(http.StatusOK, facets)
+}
+
+// jobQuery parses and validates the filters of a job listing, it aborts the request when
+// they are not valid.
+func jobQuery(c *gin.Context, traceID string) (models.JobQuery, bool) {
+	q, err := models.ParseJobQuery(c.Request.URL.Query())
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.Abort(c, traceID, http.StatusBadRequest, "Invalid filters")
+		return models.JobQuery{}, false
+	}
+	err = validation.Struct(q, "Invalid filters")
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Invalid filters")
+		return models.JobQuery{}, false
+	}
+	return q, true
+}
+
+// SearchJobs handles GET /api/jobs/search?q=...&limit=...&sort=...&cursor=...
+func (h *handler) SearchJobs(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	q := c.Query("q")
+	if q == "" {
+		apperr.Abort(c, traceID, http.StatusBadRequest, "Missing search query")
+		return
+	}
+	p, ok := h.pageRequest(c, traceID, models.SortRelevance, models.SortNewest, models.SortOldest, models.SortSalary)
+	if !ok {
+		return
+	}
+
+	results, info, err := h.jobs.SearchJobs(ctx, q, p)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Failed to search jobs")
+		return
+	}
+	h.setPageLinks(c, traceID, info)
+
+	c.JSON(http.StatusOK, results)
+}
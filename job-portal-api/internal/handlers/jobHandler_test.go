@@ -4,14 +4,17 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"gorm.io/gorm"
+	"job-portal-api/internal/apperr"
 	"job-portal-api/internal/auth"
 	middlewares "job-portal-api/internal/middleware"
 	"job-portal-api/internal/models"
+	"job-portal-api/internal/pagination"
 	"job-portal-api/internal/services"
 	"net/http"
 	"net/http/httptest"
@@ -23,13 +26,13 @@ func Test_handler_AddCompanies(t *testing.T) {
 
 	tests := []struct {
 		name               string
-		setup              func() (*gin.Context, *httptest.ResponseRecorder, services.Service)
+		setup              func() (*gin.Context, *httptest.ResponseRecorder, services.CompanyService)
 		expectedStatusCode int
 		expectedResponse   string
 	}{
 		{
 			name: "missing trace id",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.CompanyService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", nil)
@@ -38,11 +41,11 @@ func Test_handler_AddCompanies(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponse:   `{"error":"Internal Server Error"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Internal Server Error","status":500}`,
 		},
 		{
 			name: "missing jwt claims",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.CompanyService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", nil)
@@ -54,62 +57,96 @@ func Test_handler_AddCompanies(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse:   `{"error":"Unauthorized"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Unauthorized","status":401,"trace_id":"123"}`,
 		},
 		{
 			name: "invalid request body",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.CompanyService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(`{"invalid`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 
 				return c, rr, nil
 			},
-			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse:   `{"error":"Unauthorized"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Request body is not valid json","trace_id":"123"}`,
 		},
 		{
-			name: "error while creating",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			name: "unknown field",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.CompanyService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(`{"name":"Software Engineer","salary":"$100,000","location":"San Francisco"}`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
-				ms := services.NewMockService(mc)
+				ms := services.NewMockCompanyService(mc)
 
 				ms.EXPECT().CreatCompanies(c.Request.Context(), gomock.Any(), gomock.Any()).Return(models.Companies{}, errors.New("test service error")).AnyTimes()
 
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse:   `{"msg":"please provide all deatails"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"name","message":"is not a known field"}]}`,
+		},
+		{
+			name: "invalid nested job",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.CompanyService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpReq, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(`{"company_name":"Tek","founded_year":2019,"location":"bnglr","address":"blndr","jobs":[{"title":"Go developer","description":"Go","salary_min":90000,"salary_max":50000,"salary_currency":"EUR","salary_period":"year"}]}`))
+				ctx := httpReq.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}})
+				httpReq = httpReq.WithContext(ctx)
+				c.Request = httpReq
+
+				return c, rr, services.NewMockCompanyService(gomock.NewController(t))
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"jobs[0].salary_max","message":"must not be less than salary_min"}]}`,
+		},
+		{
+			name: "nested job setting its status",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.CompanyService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpReq, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(`{"company_name":"Tek","founded_year":2019,"location":"bnglr","address":"blndr","jobs":[{"title":"Go developer","description":"Go","status":"closed"}]}`))
+				ctx := httpReq.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}})
+				httpReq = httpReq.WithContext(ctx)
+				c.Request = httpReq
+
+				return c, rr, services.NewMockCompanyService(gomock.NewController(t))
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"status","message":"is not a known field"}]}`,
 		},
 		{
 			name: "success",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.CompanyService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
-				httpReq, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(`"CompanyName":"Tek","FoundedYear":2019,"Location":"bnglr","UserId":1,"Address":"blndr","Jobs":null}`))
+				httpReq, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(`{"company_name":"Tek","founded_year":2019,"location":"bnglr","address":"blndr"}`))
 				ctx := httpReq.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{Subject: "1"})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}})
 				httpReq = httpReq.WithContext(ctx)
 				c.Request = httpReq
 
 				mc := gomock.NewController(t)
-				ms := services.NewMockService(mc)
+				ms := services.NewMockCompanyService(mc)
 
-				ms.EXPECT().CreatCompanies(c.Request.Context(), gomock.Any(), "1").Return(models.Companies{
+				ms.EXPECT().CreatCompanies(c.Request.Context(), gomock.Any(), uint(1)).Return(models.Companies{
 					Model:       gorm.Model{ID: 1, CreatedAt: time.Date(2022, time.January, 1, 12, 34, 56, 0, time.UTC), UpdatedAt: time.Date(2022, time.January, 1, 12, 34, 56, 0, time.UTC)},
 					CompanyName: "Tek",
 					FoundedYear: 2019,
@@ -121,8 +158,8 @@ func Test_handler_AddCompanies(t *testing.T) {
 
 				return c, rr, ms
 			},
-			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse:   `{"error":"Unauthorized"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"ID":1,"CreatedAt":"2022-01-01T12:34:56Z","UpdatedAt":"2022-01-01T12:34:56Z","DeletedAt":null,"company_name":"Tek","founded_year":2019,"location":"bnglr","user_id":1,"address":"blndr"}`,
 		},
 	}
 	for _, tt := range tests {
@@ -131,7 +168,7 @@ func Test_handler_AddCompanies(t *testing.T) {
 			c, rr, ms := tt.setup()
 
 			h := &handler{
-				s: ms,
+				companies: ms,
 			}
 			h.AddCompanies(c)
 			assert.Equal(t, tt.expectedStatusCode, rr.Code)
@@ -143,13 +180,13 @@ func Test_handler_AddCompanies(t *testing.T) {
 func Test_handler_ViewCompanies(t *testing.T) {
 	tests := []struct {
 		name               string
-		setup              func() (*gin.Context, *httptest.ResponseRecorder, services.Service)
+		setup              func() (*gin.Context, *httptest.ResponseRecorder, services.CompanyService)
 		expectedStatusCode int
 		expectedResponse   string
 	}{
 		{
 			name: "missing trace id",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.CompanyService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com", nil)
@@ -158,11 +195,11 @@ func Test_handler_ViewCompanies(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponse:   `{"msg":"Internal Server Error"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Internal Server Error","status":500}`,
 		},
 		{
 			name: "missing jwt claims",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.CompanyService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com", nil)
@@ -174,44 +211,44 @@ func Test_handler_ViewCompanies(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse:   `{"error":"Unauthorized"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Unauthorized","status":401,"trace_id":"123"}`,
 		},
 		{
 			name: "error while fetching company from service",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.CompanyService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
-				ms := services.NewMockService(mc)
+				ms := services.NewMockCompanyService(mc)
 
-				ms.EXPECT().ViewCompanies(c.Request.Context(), "").Return([]models.Companies{}, errors.New("test service error")).AnyTimes()
+				ms.EXPECT().ViewCompanies(c.Request.Context(), gomock.Any(), "").Return([]models.Companies{}, models.PageInfo{}, errors.New("test service error")).AnyTimes()
 
 				return c, rr, ms
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse:   `{"msg":"problem in viewing company"}`,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"problem in viewing company","trace_id":"123"}`,
 		},
 		{
 			name: "success",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.CompanyService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
-				ms := services.NewMockService(mc)
+				ms := services.NewMockCompanyService(mc)
 
-				ms.EXPECT().ViewCompanies(c.Request.Context(), "").Return([]models.Companies{}, nil).AnyTimes()
+				ms.EXPECT().ViewCompanies(c.Request.Context(), gomock.Any(), "").Return([]models.Companies{}, models.PageInfo{}, nil).AnyTimes()
 
 				return c, rr, ms
 			},
@@ -226,7 +263,7 @@ func Test_handler_ViewCompanies(t *testing.T) {
 			c, rr, ms := tt.setup()
 
 			h := &handler{
-				s: ms,
+				companies: ms,
 			}
 			h.ViewCompanies(c)
 			assert.Equal(t, tt.expectedStatusCode, rr.Code)
@@ -239,13 +276,13 @@ func Test_handler_ViewCompaniesById(t *testing.T) {
 
 	tests := []struct {
 		name               string
-		setup              func() (*gin.Context, *httptest.ResponseRecorder, services.Service)
+		setup              func() (*gin.Context, *httptest.ResponseRecorder, services.CompanyService)
 		expectedStatusCode int
 		expectedResponse   string
 	}{
 		{
 			name: "missing trace id",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.CompanyService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com", nil)
@@ -254,11 +291,11 @@ func Test_handler_ViewCompaniesById(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponse:   `{"msg":"Internal Server Error"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Internal Server Error","status":500}`,
 		},
 		{
 			name: "missing jwt claims",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.CompanyService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com", nil)
@@ -270,43 +307,65 @@ func Test_handler_ViewCompaniesById(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse:   `{"error":"Unauthorized"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Unauthorized","status":401,"trace_id":"123"}`,
 		},
 		{
 			name: "error while fetching jobs from service",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.CompanyService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
-				ms := services.NewMockService(mc)
+				ms := services.NewMockCompanyService(mc)
 
 				ms.EXPECT().ViewCompaniesById(c.Request.Context(), gomock.Any(), gomock.Any()).Return([]models.Companies{}, errors.New("test service error")).AnyTimes()
 
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse:   `{"error":"Invalid company ID"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Invalid company ID","trace_id":"123"}`,
+		},
+		{
+			name: "company not found",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.CompanyService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				c.Params = append(c.Params, gin.Param{Key: "companyID", Value: "123"})
+				mc := gomock.NewController(t)
+				ms := services.NewMockCompanyService(mc)
+
+				ms.EXPECT().ViewCompaniesById(c.Request.Context(), gomock.Any(), gomock.Any()).Return([]models.Companies{}, fmt.Errorf("company 123: %w", apperr.ErrNotFound)).AnyTimes()
+
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"type":"about:blank","title":"Not Found","status":404,"detail":"company 123: not found","trace_id":"123"}`,
 		},
 		{
 			name: "success",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.CompanyService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				c.Params = append(c.Params, gin.Param{Key: "companyID", Value: "123"})
 				mc := gomock.NewController(t)
-				ms := services.NewMockService(mc)
+				ms := services.NewMockCompanyService(mc)
 
 				ms.EXPECT().ViewCompaniesById(c.Request.Context(), gomock.Any(), gomock.Any()).Return([]models.Companies{}, nil).AnyTimes()
 
@@ -323,7 +382,7 @@ func Test_handler_ViewCompaniesById(t *testing.T) {
 			c, rr, ms := tt.setup()
 
 			h := &handler{
-				s: ms,
+				companies: ms,
 			}
 			h.ViewCompaniesById(c)
 			assert.Equal(t, tt.expectedStatusCode, rr.Code)
@@ -335,13 +394,13 @@ func Test_handler_ViewCompaniesById(t *testing.T) {
 func Test_handler_CreateJob(t *testing.T) {
 	tests := []struct {
 		name               string
-		setup              func() (*gin.Context, *httptest.ResponseRecorder, services.Service)
+		setup              func() (*gin.Context, *httptest.ResponseRecorder, services.JobService)
 		expectedStatusCode int
 		expectedResponse   string
 	}{
 		{
 			name: "missing trace id",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", nil)
@@ -350,11 +409,11 @@ func Test_handler_CreateJob(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponse:   `{"msg":"Internal Server Error"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Internal Server Error","status":500}`,
 		},
 		{
 			name: "missing jwt claims",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", nil)
@@ -366,17 +425,35 @@ func Test_handler_CreateJob(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse:   `{"error":"Unauthorized"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Unauthorized","status":401,"trace_id":"123"}`,
 		},
 		{
 			name: "invalid request body",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(`{"invalid`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				c.Params = append(c.Params, gin.Param{Key: "companyID", Value: "123"})
+
+				return c, rr, nil
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Request body is not valid json","trace_id":"123"}`,
+		},
+		{
+			name: "invalid salary range",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(`{"title":"SDE","description":"backend","salary_min":90000,"salary_max":50000,"salary_currency":"INR","salary_period":"year"}`))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				c.Params = append(c.Params, gin.Param{Key: "companyID", Value: "123"})
@@ -384,44 +461,88 @@ func Test_handler_CreateJob(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse:   `{"error":"Invalid request body"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"salary_max","message":"must not be less than salary_min"}]}`,
 		},
 		{
 			name: "error while creating job posting",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
-				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(`{}`))
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(`{"title":"SDE","description":"backend"}`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				c.Params = append(c.Params, gin.Param{Key: "companyID", Value: "123"})
 				mc := gomock.NewController(t)
-				ms := services.NewMockService(mc)
+				ms := services.NewMockJobService(mc)
 
 				ms.EXPECT().CreateJob(c.Request.Context(), gomock.Any(), gomock.Any()).Return(models.Job{}, errors.New("test service error")).AnyTimes()
 
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponse:   `{"error":"Failed to create job"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"Failed to create job","trace_id":"123"}`,
+		},
+		{
+			name: "company not found",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(`{"title":"SDE","description":"backend"}`))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				c.Params = append(c.Params, gin.Param{Key: "companyID", Value: "123"})
+				mc := gomock.NewController(t)
+				ms := services.NewMockJobService(mc)
+
+				ms.EXPECT().CreateJob(c.Request.Context(), gomock.Any(), gomock.Any()).Return(models.Job{}, fmt.Errorf("company 123: %w", apperr.ErrNotFound)).AnyTimes()
+
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"type":"about:blank","title":"Not Found","status":404,"detail":"company 123: not found","trace_id":"123"}`,
+		},
+		{
+			name: "caller does not belong to the company",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(`{"title":"SDE","description":"backend"}`))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				c.Params = append(c.Params, gin.Param{Key: "companyID", Value: "123"})
+				mc := gomock.NewController(t)
+				ms := services.NewMockJobService(mc)
+
+				ms.EXPECT().CreateJob(c.Request.Context(), gomock.Any(), gomock.Any()).Return(models.Job{}, fmt.Errorf("company 123: %w", apperr.ErrForbidden)).AnyTimes()
+
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   `{"type":"about:blank","title":"Forbidden","status":403,"detail":"company 123: forbidden","trace_id":"123"}`,
 		},
 		{
 			name: "success",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
-				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(`{}`))
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(`{"title":"SDE","description":"backend"}`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				c.Params = append(c.Params, gin.Param{Key: "companyID", Value: "123"})
 				mc := gomock.NewController(t)
-				ms := services.NewMockService(mc)
+				ms := services.NewMockJobService(mc)
 
 				ms.EXPECT().CreateJob(c.Request.Context(), gomock.Any(), gomock.Any()).Return(models.Job{
 					Model: gorm.Model{ID: 1, CreatedAt: time.Date(2022, time.January, 1, 12, 34, 56, 0, time.UTC), UpdatedAt: time.Date(2022, time.January, 1, 12, 34, 56, 0, time.UTC)},
@@ -430,7 +551,7 @@ func Test_handler_CreateJob(t *testing.T) {
 				return c, rr, ms
 			},
 			expectedStatusCode: 201,
-			expectedResponse:   `{"ID":1,"CreatedAt":"2022-01-01T12:34:56Z","UpdatedAt":"2022-01-01T12:34:56Z","DeletedAt":null,"title":"","description":"","CompanyID":0}`,
+			expectedResponse:   `{"ID":1,"CreatedAt":"2022-01-01T12:34:56Z","UpdatedAt":"2022-01-01T12:34:56Z","DeletedAt":null,"title":"","description":"","CompanyID":0,"status":""}`,
 		},
 	}
 	for _, tt := range tests {
@@ -439,7 +560,7 @@ func Test_handler_CreateJob(t *testing.T) {
 			c, rr, ms := tt.setup()
 
 			h := &handler{
-				s: ms,
+				jobs: ms,
 			}
 			h.CreateJob(c)
 			assert.Equal(t, tt.expectedStatusCode, rr.Code)
@@ -451,13 +572,13 @@ func Test_handler_CreateJob(t *testing.T) {
 func Test_handler_AllJobs(t *testing.T) {
 	tests := []struct {
 		name               string
-		setup              func() (*gin.Context, *httptest.ResponseRecorder, services.Service)
+		setup              func() (*gin.Context, *httptest.ResponseRecorder, services.JobService)
 		expectedStatusCode int
 		expectedResponse   string
 	}{
 		{
 			name: "missing trace id",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com", nil)
@@ -466,11 +587,11 @@ func Test_handler_AllJobs(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponse:   `{"msg":"Internal Server Error"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Internal Server Error","status":500}`,
 		},
 		{
 			name: "missing jwt claims",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com", nil)
@@ -482,44 +603,104 @@ func Test_handler_AllJobs(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse:   `{"error":"Unauthorized"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Unauthorized","status":401,"trace_id":"123"}`,
 		},
 		{
 			name: "error while fetching jobs from service",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
-				ms := services.NewMockService(mc)
+				ms := services.NewMockJobService(mc)
 
-				ms.EXPECT().AllJob(c.Request.Context(), gomock.Any()).Return([]models.Job{}, errors.New("test service error")).AnyTimes()
+				ms.EXPECT().AllJob(c.Request.Context(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]models.Job{}, models.PageInfo{}, errors.New("test service error")).AnyTimes()
 
 				return c, rr, ms
 			},
 			expectedStatusCode: 500,
-			expectedResponse:   `{"error":"Failed to fetch jobs"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"Failed to fetch jobs","trace_id":"123"}`,
+		},
+		{
+			name: "invalid filters",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080?remote_policy=moon", nil)
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+
+				return c, rr, nil
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Invalid filters","trace_id":"123","errors":[{"field":"remote_policy[0]","message":"must be one of onsite, hybrid, remote"}]}`,
+		},
+		{
+			name: "salary filter without currency",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080?salary_min=50000", nil)
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+
+				return c, rr, nil
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Invalid filters","trace_id":"123","errors":[{"field":"currency","message":"is required"}]}`,
+		},
+		{
+			name: "filters are passed to the service",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080?remote_policy=remote,hybrid&skills=go&skills=kafka&country=IN", nil)
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				mc := gomock.NewController(t)
+				ms := services.NewMockJobService(mc)
+
+				q := models.JobQuery{
+					Country:        "IN",
+					RemotePolicies: []string{"remote", "hybrid"},
+					Skills:         []string{"go", "kafka"},
+				}
+				ms.EXPECT().AllJob(c.Request.Context(), q, models.PageRequest{Limit: 20, Sort: models.SortNewest}, "1").Return([]models.Job{}, models.PageInfo{}, nil).Times(1)
+
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `[]`,
 		},
 		{
 			name: "success",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
-				ms := services.NewMockService(mc)
+				ms := services.NewMockJobService(mc)
 
-				ms.EXPECT().AllJob(c.Request.Context(), gomock.Any()).Return([]models.Job{}, nil).AnyTimes()
+				ms.EXPECT().AllJob(c.Request.Context(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]models.Job{}, models.PageInfo{}, nil).AnyTimes()
 
 				return c, rr, ms
 			},
@@ -534,7 +715,7 @@ func Test_handler_AllJobs(t *testing.T) {
 			c, rr, ms := tt.setup()
 
 			h := &handler{
-				s: ms,
+				jobs: ms,
 			}
 			h.AllJobs(c)
 			assert.Equal(t, tt.expectedStatusCode, rr.Code)
@@ -543,16 +724,127 @@ func Test_handler_AllJobs(t *testing.T) {
 	}
 }
 
+func Test_handler_AllJobs_pages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	pages, err := pagination.New([]byte("test-secret"), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := models.Cursor{Sort: models.SortSalary, ID: 4, Value: 90000}
+	token, err := pages.Encode(next)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newRequest := func(target string) (*gin.Context, *httptest.ResponseRecorder) {
+		rr := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rr)
+		httpRequest, _ := http.NewRequest(http.MethodGet, target, nil)
+		ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "123")
+		ctx = context.WithValue(ctx, auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}})
+		c.Request = httpRequest.WithContext(ctx)
+		return c, rr
+	}
+
+	c, rr := newRequest("http://test.com/api/jobs?country=IN&sort=salary&limit=1")
+	ms := services.NewMockJobService(gomock.NewController(t))
+	ms.EXPECT().AllJob(gomock.Any(), models.JobQuery{Country: "IN"}, models.PageRequest{Limit: 1, Sort: models.SortSalary}, "1").
+		Return([]models.Job{}, models.PageInfo{Next: &next}, nil)
+	h := &handler{jobs: ms, pages: pages}
+	h.AllJobs(c)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `</api/jobs?country=IN&cursor=`+token+`&limit=1&sort=salary>; rel="next"`, rr.Header().Get("Link"))
+
+	c, rr = newRequest("http://test.com/api/jobs?cursor=" + token + "&limit=1")
+	ms = services.NewMockJobService(gomock.NewController(t))
+	ms.EXPECT().AllJob(gomock.Any(), models.JobQuery{}, models.PageRequest{Limit: 1, Sort: models.SortSalary, Cursor: &next}, "1").
+		Return([]models.Job{}, models.PageInfo{}, nil)
+	h = &handler{jobs: ms, pages: pages}
+	h.AllJobs(c)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("Link"))
+
+	for target, want := range map[string]string{
+		"http://test.com/api/jobs?cursor=" + token + "x": `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Invalid cursor","trace_id":"123"}`,
+		"http://test.com/api/jobs?sort=relevance":        `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Invalid sort","trace_id":"123"}`,
+		"http://test.com/api/jobs?limit=ten":             `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Invalid limit","trace_id":"123"}`,
+	} {
+		c, rr = newRequest(target)
+		h = &handler{pages: pages}
+		h.AllJobs(c)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, target)
+		assert.Equal(t, want, rr.Body.String(), target)
+	}
+}
+
+func Test_handler_JobFacets(t *testing.T) {
+	tests := []struct {
+		name               string
+		target             string
+		setup              func(ms *services.MockJobService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "invalid filters",
+			target:             "http://test.com/api/jobs/facets?company_id=abc",
+			setup:              func(ms *services.MockJobService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Invalid filters","trace_id":"123"}`,
+		},
+		{
+			name:   "service error",
+			target: "http://test.com/api/jobs/facets",
+			setup: func(ms *services.MockJobService) {
+				ms.EXPECT().JobFacets(gomock.Any(), models.JobQuery{}).Return(models.JobFacets{}, errors.New("test service error")).Times(1)
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"Failed to count jobs","trace_id":"123"}`,
+		},
+		{
+			name:   "success",
+			target: "http://test.com/api/jobs/facets?city=Pune",
+			setup: func(ms *services.MockJobService) {
+				ms.EXPECT().JobFacets(gomock.Any(), models.JobQuery{City: "Pune"}).Return(models.JobFacets{
+					Companies:      []models.FacetCount{{Value: "1", Label: "Acme", Count: 2}},
+					RemotePolicies: []models.FacetCount{{Value: "remote", Count: 2}},
+				}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"companies":[{"value":"1","label":"Acme","count":2}],"countries":null,"remote_policies":[{"value":"remote","count":2}],"employment_types":null,"seniorities":null,"skills":null}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodGet, tt.target, nil)
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "123")
+			c.Request = httpRequest.WithContext(ctx)
+
+			mc := gomock.NewController(t)
+			ms := services.NewMockJobService(mc)
+			tt.setup(ms)
+
+			h := &handler{jobs: ms}
+			h.JobFacets(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
 func Test_handler_ListJobs(t *testing.T) {
 	tests := []struct {
 		name               string
-		setup              func() (*gin.Context, *httptest.ResponseRecorder, services.Service)
+		setup              func() (*gin.Context, *httptest.ResponseRecorder, services.JobService)
 		expectedStatusCode int
 		expectedResponse   string
 	}{
 		{
 			name: "missing trace id",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", nil)
@@ -561,11 +853,11 @@ func Test_handler_ListJobs(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponse:   `{"msg":"Internal Server Error"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Internal Server Error","status":500}`,
 		},
 		{
 			name: "missing jwt claims",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", nil)
@@ -577,58 +869,58 @@ func Test_handler_ListJobs(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse:   `{"error":"Unauthorized"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Unauthorized","status":401,"trace_id":"123"}`,
 		},
 		{
 			name: "error while fetching jobs from service",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
-				ms := services.NewMockService(mc)
+				ms := services.NewMockJobService(mc)
 
-				ms.EXPECT().AllJob(c.Request.Context(), gomock.Any()).Return([]models.Job{}, errors.New("test service error")).AnyTimes()
+				ms.EXPECT().AllJob(c.Request.Context(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]models.Job{}, models.PageInfo{}, errors.New("test service error")).AnyTimes()
 
 				return c, rr, ms
 			},
 			expectedStatusCode: 400,
-			expectedResponse:   `{"msg":"problem in viewing job"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"problem in viewing job","trace_id":"123"}`,
 		},
 
 		{
 			name: "success",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(`{"name":"Software Engineer","salary":"$100,000","location":"San Francisco"}`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				c.Params = append(c.Params, gin.Param{Key: "companyID", Value: "123"})
 				mc := gomock.NewController(t)
-				ms := services.NewMockService(mc)
+				ms := services.NewMockJobService(mc)
 
-				ms.EXPECT().ListJobs(c.Request.Context(), gomock.Any(), gomock.Any()).Return([]models.Job{
+				ms.EXPECT().ListJobs(c.Request.Context(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]models.Job{
 					{
 						Model:       gorm.Model{ID: 1, CreatedAt: time.Date(2022, time.January, 1, 12, 34, 56, 0, time.UTC), UpdatedAt: time.Date(2022, time.January, 1, 12, 34, 56, 0, time.UTC)},
 						Title:       "sde",
 						Description: "hr",
 						CompanyID:   1,
 					},
-				}, nil).AnyTimes()
+				}, models.PageInfo{}, nil).AnyTimes()
 
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse:   `[{"ID":1,"CreatedAt":"2022-01-01T12:34:56Z","UpdatedAt":"2022-01-01T12:34:56Z","DeletedAt":null,"title":"sde","description":"hr","CompanyID":1}]`,
+			expectedResponse:   `[{"ID":1,"CreatedAt":"2022-01-01T12:34:56Z","UpdatedAt":"2022-01-01T12:34:56Z","DeletedAt":null,"title":"sde","description":"hr","CompanyID":1,"status":""}]`,
 		},
 	}
 	for _, tt := range tests {
@@ -637,7 +929,7 @@ func Test_handler_ListJobs(t *testing.T) {
 			c, rr, ms := tt.setup()
 
 			h := &handler{
-				s: ms,
+				jobs: ms,
 			}
 			h.ListJobs(c)
 			assert.Equal(t, tt.expectedStatusCode, rr.Code)
@@ -649,13 +941,13 @@ func Test_handler_ListJobs(t *testing.T) {
 func Test_handler_JobsByID(t *testing.T) {
 	tests := []struct {
 		name               string
-		setup              func() (*gin.Context, *httptest.ResponseRecorder, services.Service)
+		setup              func() (*gin.Context, *httptest.ResponseRecorder, services.JobService)
 		expectedStatusCode int
 		expectedResponse   string
 	}{
 		{
 			name: "missing trace id",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com", nil)
@@ -664,11 +956,11 @@ func Test_handler_JobsByID(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponse:   `{"msg":"Internal Server Error"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Internal Server Error","status":500}`,
 		},
 		{
 			name: "missing jwt claims",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com", nil)
@@ -680,50 +972,94 @@ func Test_handler_JobsByID(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse:   `{"error":"Unauthorized"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Unauthorized","status":401,"trace_id":"123"}`,
 		},
 		{
 			name: "error while fetching jobs from service",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
-				ms := services.NewMockService(mc)
+				ms := services.NewMockJobService(mc)
 
 				ms.EXPECT().JobsByID(c.Request.Context(), gomock.Any(), gomock.Any()).Return(models.Job{}, errors.New("test service error")).AnyTimes()
 
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse:   `{"error":"Invalid job ID"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Invalid job ID","trace_id":"123"}`,
+		},
+		{
+			name: "job not found",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				c.Params = append(c.Params, gin.Param{Key: "jobID", Value: "123"})
+				mc := gomock.NewController(t)
+				ms := services.NewMockJobService(mc)
+
+				ms.EXPECT().JobsByID(c.Request.Context(), gomock.Any(), gomock.Any()).Return(models.Job{}, fmt.Errorf("job 123: %w", apperr.ErrNotFound)).AnyTimes()
+
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"type":"about:blank","title":"Not Found","status":404,"detail":"job 123: not found","trace_id":"123"}`,
+		},
+		{
+			name: "service error",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				c.Params = append(c.Params, gin.Param{Key: "jobID", Value: "123"})
+				mc := gomock.NewController(t)
+				ms := services.NewMockJobService(mc)
+
+				ms.EXPECT().JobsByID(c.Request.Context(), gomock.Any(), gomock.Any()).Return(models.Job{}, errors.New("test service error")).AnyTimes()
+
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"Failed to fetch job","trace_id":"123"}`,
 		},
 		{
 			name: "success",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.JobService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				c.Params = append(c.Params, gin.Param{Key: "jobID", Value: "123"})
 				mc := gomock.NewController(t)
-				ms := services.NewMockService(mc)
+				ms := services.NewMockJobService(mc)
 
 				ms.EXPECT().JobsByID(c.Request.Context(), gomock.Any(), gomock.Any()).Return(models.Job{}, nil).AnyTimes()
 
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse:   `{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"title":"","description":"","CompanyID":0}`,
+			expectedResponse:   `{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"title":"","description":"","CompanyID":0,"status":""}`,
 		},
 	}
 
@@ -733,11 +1069,14 @@ func Test_handler_JobsByID(t *testing.T) {
 			c, rr, ms := tt.setup()
 
 			h := &handler{
-				s: ms,
+				jobs: ms,
 			}
 			h.JobsByID(c)
 			assert.Equal(t, tt.expectedStatusCode, rr.Code)
 			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+			if rr.Code != http.StatusOK {
+				assert.Equal(t, apperr.ContentType, rr.Header().Get("Content-Type"))
+			}
 		})
 	}
 }
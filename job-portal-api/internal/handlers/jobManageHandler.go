@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/auth"
+	middlewares "job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+func (h *handler) UpdateJob(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceID).Msg("login first")
+		apperr.Abort(c, traceID, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	jobID, err := strconv.ParseUint(c.Param("jobID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.Abort(c, traceID, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	var uj models.UpdateJob
+	if !bindJSON(c, traceID, &uj) {
+		return
+	}
+
+	job, err := h.jobs.UpdateJob(ctx, jobID, uj, claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Failed to update job")
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+func (h *handler) PatchJob(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceID).Msg("login first")
+		apperr.Abort(c, traceID, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	jobID, err := strconv.ParseUint(c.Param("jobID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.Abort(c, traceID, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	var jp models.JobPatch
+	if !bindJSON(c, traceID, &jp) {
+		return
+	}
+
+	job, err := h.jobs.PatchJob(ctx, jobID, jp, claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Failed to update job")
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+func (h *handler) CloseJob(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceID).Msg("login first")
+		apperr.Abort(c, traceID, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	jobID, err := strconv.ParseUint(c.Param("jobID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.Abort(c, traceID, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	job, err := h.jobs.CloseJob(ctx, jobID, claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Failed to close job")
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+func (h *handler) DeleteJob(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceID).Msg("login first")
+		apperr.Abort(c, traceID, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	jobID, err := strconv.ParseUint(c.Param("jobID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.Abort(c, traceID, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	err = h.jobs.DeleteJob(ctx, jobID, claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Failed to delete job")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *handler) RestoreJob(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	jobID, err := strconv.ParseUint(c.Param("jobID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.Abort(c, traceID, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	job, err := h.jobs.RestoreJob(ctx, jobID)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Failed to restore job")
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/auth"
+	middlewares "job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newJobRequest builds a context for a request on /api/jobs/:jobID made by user 1.
+func newJobRequest(method, jobID, body string) (*gin.Context, *httptest.ResponseRecorder) {
+	rr := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rr)
+	httpRequest, _ := http.NewRequest(method, "http://test.com:8080", bytes.NewBufferString(body))
+	ctx := httpRequest.Context()
+	ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+	ctx = context.WithValue(ctx, auth.Key, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}})
+	c.Request = httpRequest.WithContext(ctx)
+	c.Params = append(c.Params, gin.Param{Key: "jobID", Value: jobID})
+	return c, rr
+}
+
+func Test_handler_UpdateJob(t *testing.T) {
+	tests := []struct {
+		name               string
+		jobID              string
+		body               string
+		setup              func(ms *services.MockJobService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "invalid job id",
+			jobID:              "abc",
+			body:               `{"title":"SDE","description":"backend"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Invalid job ID","trace_id":"123"}`,
+		},
+		{
+			name:               "missing fields",
+			jobID:              "5",
+			body:               `{"title":"SDE"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"description","message":"is required"}]}`,
+		},
+		{
+			name:  "job not found",
+			jobID: "5",
+			body:  `{"title":"SDE","description":"backend"}`,
+			setup: func(ms *services.MockJobService) {
+				ms.EXPECT().UpdateJob(gomock.Any(), uint64(5), models.UpdateJob{Title: "SDE", Description: "backend"}, "1").
+					Return(models.Job{}, fmt.Errorf("job 5: %w", apperr.ErrNotFound))
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"type":"about:blank","title":"Not Found","status":404,"detail":"job 5: not found","trace_id":"123"}`,
+		},
+		{
+			name:  "success",
+			jobID: "5",
+			body:  `{"title":"SDE","description":"backend"}`,
+			setup: func(ms *services.MockJobService) {
+				ms.EXPECT().UpdateJob(gomock.Any(), uint64(5), models.UpdateJob{Title: "SDE", Description: "backend"}, "1").
+					Return(models.Job{Title: "SDE", Description: "backend", CompanyID: 1, Status: models.JobStatusOpen}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"title":"SDE","description":"backend","CompanyID":1,"status":"open"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, rr := newJobRequest(http.MethodPut, tt.jobID, tt.body)
+			ms := services.NewMockJobService(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+
+			h := &handler{
+				jobs: ms,
+			}
+			h.UpdateJob(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_PatchJob(t *testing.T) {
+	title := "Backend engineer"
+	tests := []struct {
+		name               string
+		body               string
+		setup              func(ms *services.MockJobService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "empty title",
+			body:               `{"title":""}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"title","message":"must not be empty"}]}`,
+		},
+		{
+			name: "forbidden",
+			body: `{"title":"Backend engineer"}`,
+			setup: func(ms *services.MockJobService) {
+				ms.EXPECT().PatchJob(gomock.Any(), uint64(5), models.JobPatch{Title: &title}, "1").
+					Return(models.Job{}, apperr.ErrForbidden)
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   `{"type":"about:blank","title":"Forbidden","status":403,"trace_id":"123"}`,
+		},
+		{
+			name:               "unknown country",
+			body:               `{"country":"India"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"country","message":"must be an ISO 3166-1 alpha-2 country code"}]}`,
+		},
+		{
+			name:               "expiry in the past",
+			body:               `{"expires_at":"2001-01-01T00:00:00Z"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"expires_at","message":"must be in the future"}]}`,
+		},
+		{
+			name:               "duplicate skills",
+			body:               `{"skills":[{"name":"Go"},{"name":"Go","required":true}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"skills","message":"must not contain duplicates"}]}`,
+		},
+		{
+			name: "salary range rejected by the service",
+			body: `{"salary_max":1}`,
+			setup: func(ms *services.MockJobService) {
+				ms.EXPECT().PatchJob(gomock.Any(), uint64(5), gomock.Any(), "1").
+					Return(models.Job{}, fmt.Errorf("salary_max 1 is below salary_min 10: %w", apperr.ErrValidation))
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"salary_max 1 is below salary_min 10: validation failed","trace_id":"123"}`,
+		},
+		{
+			name: "skills",
+			body: `{"skills":[{"name":"Go","required":true},{"name":"Kafka"}]}`,
+			setup: func(ms *services.MockJobService) {
+				ms.EXPECT().PatchJob(gomock.Any(), uint64(5), models.JobPatch{Skills: &[]models.NewJobSkill{{Name: "Go", Required: true}, {Name: "Kafka"}}}, "1").
+					Return(models.Job{Title: "SDE", CompanyID: 1, Status: models.JobStatusOpen, RemotePolicy: models.RemotePolicyHybrid, Skills: []models.JobSkill{{Name: "Go", Required: true}, {Name: "Kafka"}}}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"title":"SDE","description":"","CompanyID":1,"status":"open","remote_policy":"hybrid","skills":[{"name":"Go","required":true},{"name":"Kafka","required":false}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, rr := newJobRequest(http.MethodPatch, "5", tt.body)
+			ms := services.NewMockJobService(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+
+			h := &handler{
+				jobs: ms,
+			}
+			h.PatchJob(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_DeleteJob(t *testing.T) {
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "success",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:               "forbidden",
+			err:                apperr.ErrForbidden,
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   `{"type":"about:blank","title":"Forbidden","status":403,"trace_id":"123"}`,
+		},
+		{
+			name:               "job not found",
+			err:                apperr.ErrNotFound,
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"type":"about:blank","title":"Not Found","status":404,"trace_id":"123"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, rr := newJobRequest(http.MethodDelete, "5", "")
+			ms := services.NewMockJobService(gomock.NewController(t))
+			ms.EXPECT().DeleteJob(gomock.Any(), uint64(5), "1").Return(tt.err)
+
+			h := &handler{
+				jobs: ms,
+			}
+			h.DeleteJob(c)
+			c.Writer.WriteHeaderNow()
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_RestoreJob(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, rr := newJobRequest(http.MethodPost, "5", "")
+	ms := services.NewMockJobService(gomock.NewController(t))
+	ms.EXPECT().RestoreJob(gomock.Any(), uint64(5)).Return(models.Job{}, apperr.ErrNotFound)
+
+	h := &handler{
+		jobs: ms,
+	}
+	h.RestoreJob(c)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, `{"type":"about:blank","title":"Not Found","status":404,"trace_id":"123"}`, rr.Body.String())
+}
+
+func Test_handler_SearchJobs(t *testing.T) {
+	tests := []struct {
+		name               string
+		target             string
+		setup              func(ms *services.MockJobService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "missing query",
+			target:             "/api/jobs/search",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Missing search query","trace_id":"123"}`,
+		},
+		{
+			name:               "invalid limit",
+			target:             "/api/jobs/search?q=go&limit=-1",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Invalid limit","trace_id":"123"}`,
+		},
+		{
+			name:   "ranked results",
+			target: `/api/jobs/search?q=%22backend+engineer%22+go*&limit=5`,
+			setup: func(ms *services.MockJobService) {
+				ms.EXPECT().SearchJobs(gomock.Any(), `"backend engineer" go*`, models.PageRequest{Limit: 5, Sort: models.SortRelevance}).Return([]models.JobSearchResult{{
+					Job:            models.Job{Title: "Backend Engineer", CompanyID: 1, Status: models.JobStatusOpen},
+					Rank:           0.5,
+					TitleHighlight: "<mark>Backend</mark> <mark>Engineer</mark>",
+					Snippet:        "writing <mark>Go</mark> services",
+				}}, models.PageInfo{}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `[{"job":{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"title":"Backend Engineer","description":"","CompanyID":1,"status":"open"},"rank":0.5,"title_highlight":"\u003cmark\u003eBackend\u003c/mark\u003e \u003cmark\u003eEngineer\u003c/mark\u003e","snippet":"writing \u003cmark\u003eGo\u003c/mark\u003e services"}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080"+tt.target, nil)
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "123")
+			c.Request = httpRequest.WithContext(ctx)
+			ms := services.NewMockJobService(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+
+			h := &handler{
+				jobs: ms,
+			}
+			h.SearchJobs(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
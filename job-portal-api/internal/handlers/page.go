@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"errors"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/pagination"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// pageRequest reads the paging parameters of a list that can be sorted by one of sorts, the
+// first one being the default. It aborts the request when they are not valid.
+func (h *handler) pageRequest(c *gin.Context, traceID string, sorts ...string) (models.PageRequest, bool) {
+	p, err := h.pages.Parse(c.Request.URL.Query(), sorts...)
+	if err == nil {
+		return p, true
+	}
+
+	log.Error().Err(err).Str("Trace Id", traceID).Send()
+	msg := "Invalid cursor"
+	switch {
+	case errors.Is(err, pagination.ErrInvalidLimit):
+		msg = "Invalid limit"
+	case errors.Is(err, pagination.ErrInvalidSort):
+		msg = "Invalid sort"
+	}
+	apperr.Abort(c, traceID, http.StatusBadRequest, msg)
+	return models.PageRequest{}, false
+}
+
+// setPageLinks points the Link header of the response at the pages around a page. The
+// page is still sent when the links cannot be made.
+func (h *handler) setPageLinks(c *gin.Context, traceID string, info models.PageInfo) {
+	link, err := h.pages.Link(c.Request.URL, info)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		return
+	}
+	if link != "" {
+		c.Header("Link", link)
+	}
+}
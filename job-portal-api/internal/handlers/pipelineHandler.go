@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/auth"
+	middlewares "job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+func (h *handler) ViewPipeline(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceID).Msg("login first")
+		apperr.Abort(c, traceID, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	companyID, err := strconv.ParseUint(c.Param("companyID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.Abort(c, traceID, http.StatusBadRequest, "Invalid company ID")
+		return
+	}
+
+	stages, err := h.as.ViewPipeline(ctx, uint(companyID), claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Failed to fetch pipeline")
+		return
+	}
+
+	c.JSON(http.StatusOK, stages)
+}
+
+func (h *handler) SetPipeline(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceID).Msg("login first")
+		apperr.Abort(c, traceID, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	companyID, err := strconv.ParseUint(c.Param("companyID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.Abort(c, traceID, http.StatusBadRequest, "Invalid company ID")
+		return
+	}
+
+	var np models.NewPipeline
+	if !bindJSON(c, traceID, &np) {
+		return
+	}
+
+	stages, err := h.as.SetPipeline(ctx, uint(companyID), np, claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Failed to update pipeline")
+		return
+	}
+
+	c.JSON(http.StatusOK, stages)
+}
+
+func (h *handler) MoveApplications(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceID).Msg("login first")
+		apperr.Abort(c, traceID, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	var bm models.BulkMove
+	if !bindJSON(c, traceID, &bm) {
+		return
+	}
+
+	apps, err := h.as.MoveApplications(ctx, bm, claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Failed to move applications")
+		return
+	}
+
+	c.JSON(http.StatusOK, apps)
+}
+
+func (h *handler) RejectApplications(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceID).Msg("login first")
+		apperr.Abort(c, traceID, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	var br models.BulkReject
+	if !bindJSON(c, traceID, &br) {
+		return
+	}
+
+	apps, err := h.as.RejectApplications(ctx, br, claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Failed to reject applications")
+		return
+	}
+
+	c.JSON(http.StatusOK, apps)
+}
+
+func (h *handler) ApplicationHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceID, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceID).Msg("login first")
+		apperr.Abort(c, traceID, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	applicationID, err := strconv.ParseUint(c.Param("applicationID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.Abort(c, traceID, http.StatusBadRequest, "Invalid application ID")
+		return
+	}
+
+	changes, err := h.as.ApplicationHistory(ctx, uint(applicationID), claims.Subject)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceID).Send()
+		apperr.AbortWithError(c, traceID, err, "Failed to fetch application history")
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
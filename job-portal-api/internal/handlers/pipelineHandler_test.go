@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/services"
+	"net/http"
+	"testing"
+)
+
+func Test_handler_MoveApplications(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		setup              func(ms *services.MockApplicationService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "no applications",
+			body:               `{"application_ids":[],"stage":"interview"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"application_ids","message":"must not be empty"}]}`,
+		},
+		{
+			name:               "duplicate applications",
+			body:               `{"application_ids":[1,1],"stage":"interview"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"application_ids","message":"must not contain duplicates"}]}`,
+		},
+		{
+			name: "transition not allowed",
+			body: `{"application_ids":[1,2],"stage":"offer","reason":"fast track"}`,
+			setup: func(ms *services.MockApplicationService) {
+				ms.EXPECT().MoveApplications(gomock.Any(), models.BulkMove{ApplicationIDs: []uint{1, 2}, Stage: "offer", Reason: "fast track"}, "1").
+					Return(nil, fmt.Errorf("application 2: cannot move from \"applied\" to \"offer\": %w", apperr.ErrValidation))
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"application 2: cannot move from \"applied\" to \"offer\": validation failed","trace_id":"123"}`,
+		},
+		{
+			name: "success",
+			body: `{"application_ids":[1],"stage":"screening"}`,
+			setup: func(ms *services.MockApplicationService) {
+				ms.EXPECT().MoveApplications(gomock.Any(), models.BulkMove{ApplicationIDs: []uint{1}, Stage: "screening"}, "1").
+					Return([]models.Application{{JobID: 5, UserID: 3, ResumeURL: "https://cv.example.com/3.pdf", Status: models.ApplicationInReview, Stage: "screening"}}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `[{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"job_id":5,"user_id":3,"resume_url":"https://cv.example.com/3.pdf","status":"in_review","stage":"screening"}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, rr := newJobRequest(http.MethodPost, "", tt.body)
+			ms := services.NewMockApplicationService(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+
+			h := &handler{
+				as: ms,
+			}
+			h.MoveApplications(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_SetPipeline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, rr := newCompanyRequest(http.MethodPut, "", "1", `{"stages":[{"name":"new","next":["new"]},{"name":"new","outcome":"rejected"}]}`)
+	ms := services.NewMockApplicationService(gomock.NewController(t))
+
+	h := &handler{
+		as: ms,
+	}
+	h.SetPipeline(c)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"stages","message":"must not contain duplicates"}]}`, rr.Body.String())
+}
@@ -1,21 +1,31 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"io"
+	"job-portal-api/internal/apperr"
 	"job-portal-api/internal/auth"
+	"job-portal-api/internal/metrics"
 	middlewares "job-portal-api/internal/middleware"
 	"job-portal-api/internal/models"
+	"job-portal-api/internal/pagination"
 	"job-portal-api/internal/services"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
 	"github.com/rs/zerolog/log"
 )
 
 type handler struct {
-	s services.Service
-	a *auth.Auth
+	users     services.UserService
+	companies services.CompanyService
+	jobs      services.JobService
+	as        services.ApplicationService
+	a         *auth.Auth
+	pages     pagination.Paginator
+	metrics   *metrics.Metrics
 }
 
 func (h *handler) Register(c *gin.Context) {
@@ -23,33 +33,23 @@ func (h *handler) Register(c *gin.Context) {
 	traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
 	if !ok {
 		log.Error().Msg("traceId missing from context")
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
 	var nu models.NewUser
 
-	err := json.NewDecoder(c.Request.Body).Decode(&nu)
-	if err != nil {
-		log.Error().Err(err).Str("Trace Id", traceId)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
-		return
-	}
-
-	validate := validator.New()
-	err = validate.Struct(nu)
-	if err != nil {
-		log.Error().Err(err).Str("Trace Id", traceId).Send()
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "please provide Name, Email and Password"})
+	if !bindJSON(c, traceId, &nu) {
 		return
 	}
 
-	usr, err := h.s.CreateUser(ctx, nu)
+	usr, err := h.users.CreateUser(ctx, nu)
 	if err != nil {
 		log.Error().Err(err).Str("Trace Id", traceId).Msg("user signup problem")
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "user signup failed"})
+		apperr.AbortWithError(c, traceId, err, "user signup failed")
 		return
 	}
+	h.metrics.UserRegistered()
 
 	c.JSON(http.StatusOK, usr)
 }
@@ -59,7 +59,7 @@ func (h *handler) Login(c *gin.Context) {
 	traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
 	if !ok {
 		log.Error().Msg("traceId missing from context")
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
@@ -68,38 +68,155 @@ func (h *handler) Login(c *gin.Context) {
 		Password string `json:"password" validate:"required"`
 	}
 
-	err := json.NewDecoder(c.Request.Body).Decode(&login)
+	if !bindJSON(c, traceId, &login) {
+		return
+	}
+
+	claims, err := h.users.Authenticate(ctx, login.Email, login.Password)
+	if err != nil {
+		if errors.Is(err, apperr.ErrUnauthorized) {
+			h.metrics.LoginFailed()
+		}
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		apperr.AbortWithError(c, traceId, err, "login failed")
+		return
+	}
+
+	tkn, err := h.tokenResponse(ctx, claims)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Msg("generating token")
+		apperr.Abort(c, traceId, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, tkn)
+}
+
+// tokenResponse signs an access token for claims and pairs it with a refresh token from a new family.
+func (h *handler) tokenResponse(ctx context.Context, claims auth.Claims) (models.TokenResponse, error) {
+	refreshToken, err := h.users.IssueRefreshToken(ctx, claims.Subject)
+	if err != nil {
+		return models.TokenResponse{}, err
+	}
+	return h.signedTokenResponse(claims, refreshToken)
+}
+
+func (h *handler) signedTokenResponse(claims auth.Claims, refreshToken string) (models.TokenResponse, error) {
+	accessToken, err := h.a.GenerateToken(claims)
 	if err != nil {
-		log.Error().Err(err).Str("Trace Id", traceId)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return models.TokenResponse{}, err
+	}
+	tkn := models.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+	}
+	if claims.ExpiresAt != nil {
+		tkn.ExpiresAt = claims.ExpiresAt.Time
+	}
+	return tkn, nil
+}
+
+func (h *handler) RefreshToken(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
-	validate := validator.New()
-	err = validate.Struct(login)
+	var req models.RefreshRequest
+	if !bindJSON(c, traceId, &req) {
+		return
+	}
+
+	claims, refreshToken, err := h.users.RefreshToken(ctx, req.RefreshToken)
 	if err != nil {
 		log.Error().Err(err).Str("Trace Id", traceId).Send()
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "please provide Email and Password"})
+		apperr.AbortWithError(c, traceId, err, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
-	claims, err := h.s.Authenticate(ctx, login.Email, login.Password)
+	tkn, err := h.signedTokenResponse(claims, refreshToken)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Msg("generating token")
+		apperr.Abort(c, traceId, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, tkn)
+}
+
+func (h *handler) Logout(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		apperr.Abort(c, traceId, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	// The refresh token is optional, without it only the access token is revoked.
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	err := decodeJSON(c, &req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		abortWithBodyError(c, traceId, err)
+		return
+	}
+
+	err = h.users.Logout(ctx, claims, req.RefreshToken)
 	if err != nil {
 		log.Error().Err(err).Str("Trace Id", traceId).Send()
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"msg": "login failed"})
+		apperr.AbortWithError(c, traceId, err, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
-	var tkn struct {
-		Token string `json:"token"`
+	c.Status(http.StatusNoContent)
+}
+
+// JWKS publishes the public keys that validate our tokens so other services can verify them.
+func (h *handler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.a.JWKS())
+}
+
+func (h *handler) UpdateUserRole(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
 	}
 
-	tkn.Token, err = h.a.GenerateToken(claims)
+	userID, err := strconv.ParseUint(c.Param("userID"), 10, 64)
 	if err != nil {
-		log.Error().Err(err).Msg("generating token")
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		apperr.Abort(c, traceId, http.StatusBadRequest, "Invalid user ID")
 		return
 	}
 
-	c.JSON(http.StatusOK, tkn.Token)
+	var ur models.UpdateRole
+	if !bindJSON(c, traceId, &ur) {
+		return
+	}
+
+	usr, err := h.users.UpdateUserRole(ctx, uint(userID), ur.Role)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Msg("updating user role")
+		apperr.AbortWithError(c, traceId, err, "Failed to update user role")
+		return
+	}
+
+	c.JSON(http.StatusOK, usr)
 }
@@ -3,28 +3,36 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/auth"
 	middlewares "job-portal-api/internal/middleware"
+	"job-portal-api/internal/models"
 	"job-portal-api/internal/services"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func Test_handler_Register(t *testing.T) {
 	tests := []struct {
 		name               string
-		setup              func() (*gin.Context, *httptest.ResponseRecorder, services.Service)
+		setup              func() (*gin.Context, *httptest.ResponseRecorder, services.UserService)
 		expectedStatusCode int
 		expectedResponse   string
 	}{
 		{
 			name: "missing trace id",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com", nil)
@@ -33,11 +41,11 @@ func Test_handler_Register(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponse:   `{"msg":"Internal Server Error"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Internal Server Error","status":500}`,
 		},
 		{
 			name: "invalid user data",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(`{}`))
@@ -49,7 +57,7 @@ func Test_handler_Register(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse:   `{"msg":"please provide Name, Email and Password"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"name","message":"is required"},{"field":"email","message":"is required"},{"field":"password","message":"is required"}]}`,
 		},
 	}
 	for _, tt := range tests {
@@ -58,7 +66,7 @@ func Test_handler_Register(t *testing.T) {
 			c, rr, ms := tt.setup()
 
 			h := &handler{
-				s: ms,
+				users: ms,
 			}
 			h.Register(c)
 			assert.Equal(t, tt.expectedStatusCode, rr.Code)
@@ -70,14 +78,14 @@ func Test_handler_Register(t *testing.T) {
 func Test_handler_Login(t *testing.T) {
 	tests := []struct {
 		name               string
-		setup              func() (*gin.Context, *httptest.ResponseRecorder, services.Service)
+		setup              func() (*gin.Context, *httptest.ResponseRecorder, services.UserService)
 		expectedStatusCode int
 		expectedResponse   string
 	}{
 
 		{
 			name: "missing trace id",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", nil)
@@ -86,11 +94,11 @@ func Test_handler_Login(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponse:   `{"msg":"Internal Server Error"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Internal Server Error","status":500}`,
 		},
 		{
 			name: "error during user login",
-			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.Service) {
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
 
@@ -106,15 +114,15 @@ func Test_handler_Login(t *testing.T) {
 
 				// Create a mock UserService
 				mc := gomock.NewController(t)
-				ms := services.NewMockService(mc)
+				ms := services.NewMockUserService(mc)
 
 				// Expect the UserLoginService to be called and return an error
-				ms.EXPECT().Authenticate(c.Request.Context(), gomock.Any(), gomock.Any()).Return(jwt.RegisteredClaims{}, errors.New("test service error")).AnyTimes()
+				ms.EXPECT().Authenticate(c.Request.Context(), gomock.Any(), gomock.Any()).Return(auth.Claims{}, apperr.Wrap(apperr.ErrUnauthorized, errors.New("test service error"), "invalid email or password")).AnyTimes()
 
 				return c, rr, ms
 			},
 			expectedStatusCode: 401,
-			expectedResponse:   `{"msg":"login failed"}`,
+			expectedResponse:   `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"invalid email or password","trace_id":"123"}`,
 		},
 	}
 
@@ -124,7 +132,7 @@ func Test_handler_Login(t *testing.T) {
 			c, rr, ms := tt.setup()
 
 			h := &handler{
-				s: ms,
+				users: ms,
 			}
 			h.Login(c)
 			assert.Equal(t, tt.expectedStatusCode, rr.Code)
@@ -132,3 +140,250 @@ func Test_handler_Login(t *testing.T) {
 		})
 	}
 }
+
+func Test_handler_UpdateUserRole(t *testing.T) {
+	tests := []struct {
+		name               string
+		setup              func() (*gin.Context, *httptest.ResponseRecorder, services.UserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name: "invalid user id",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPut, "http://test.com:8080", bytes.NewBufferString(`{"role":"admin"}`))
+				ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "123")
+				c.Request = httpRequest.WithContext(ctx)
+				c.Params = append(c.Params, gin.Param{Key: "userID", Value: "abc"})
+
+				return c, rr, nil
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Invalid user ID","trace_id":"123"}`,
+		},
+		{
+			name: "unknown role",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPut, "http://test.com:8080", bytes.NewBufferString(`{"role":"superuser"}`))
+				ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "123")
+				c.Request = httpRequest.WithContext(ctx)
+				c.Params = append(c.Params, gin.Param{Key: "userID", Value: "1"})
+
+				return c, rr, nil
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"role","message":"must be one of candidate, employer, admin"}]}`,
+		},
+		{
+			name: "success",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPut, "http://test.com:8080", bytes.NewBufferString(`{"role":"employer"}`))
+				ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "123")
+				c.Request = httpRequest.WithContext(ctx)
+				c.Params = append(c.Params, gin.Param{Key: "userID", Value: "1"})
+
+				mc := gomock.NewController(t)
+				ms := services.NewMockUserService(mc)
+				ms.EXPECT().UpdateUserRole(c.Request.Context(), uint(1), models.RoleEmployer).Return(models.User{Name: "satyam", Email: "satyam@gmail.com", Role: models.RoleEmployer}, nil)
+
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"name":"satyam","email":"satyam@gmail.com","role":"employer"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, rr, ms := tt.setup()
+
+			h := &handler{
+				users: ms,
+			}
+			h.UpdateUserRole(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func newTestAuth(t *testing.T) *auth.Auth {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := auth.NewAuth(key, &key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func Test_handler_RefreshToken(t *testing.T) {
+	a := newTestAuth(t)
+	expiresAt := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name               string
+		body               string
+		setup              func(ms *services.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "missing refresh token",
+			body:               `{}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"The request has invalid fields","trace_id":"123","errors":[{"field":"refresh_token","message":"is required"}]}`,
+		},
+		{
+			name: "rejected refresh token",
+			body: `{"refresh_token":"old"}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().RefreshToken(gomock.Any(), "old").Return(auth.Claims{}, "", fmt.Errorf("refresh token reused: %w", apperr.ErrUnauthorized))
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"refresh token reused: unauthorized","trace_id":"123"}`,
+		},
+		{
+			name: "success",
+			body: `{"refresh_token":"old"}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().RefreshToken(gomock.Any(), "old").Return(auth.Claims{RegisteredClaims: jwt.RegisteredClaims{
+					ID:        "jti",
+					Subject:   "1",
+					ExpiresAt: jwt.NewNumericDate(expiresAt),
+				}}, "new", nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(tt.body))
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "123")
+			c.Request = httpRequest.WithContext(ctx)
+
+			mc := gomock.NewController(t)
+			ms := services.NewMockUserService(mc)
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+
+			h := &handler{
+				users: ms,
+				a:     a,
+			}
+			h.RefreshToken(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			if tt.expectedResponse != "" {
+				assert.Equal(t, tt.expectedResponse, rr.Body.String())
+				return
+			}
+
+			var tkn models.TokenResponse
+			err := json.Unmarshal(rr.Body.Bytes(), &tkn)
+			assert.NoError(t, err)
+			assert.Equal(t, "new", tkn.RefreshToken)
+			assert.Equal(t, "Bearer", tkn.TokenType)
+			assert.Equal(t, expiresAt, tkn.ExpiresAt.UTC())
+			claims, err := a.ValidateToken(tkn.AccessToken)
+			assert.NoError(t, err)
+			assert.Equal(t, "jti", claims.ID)
+		})
+	}
+}
+
+func Test_handler_Logout(t *testing.T) {
+	claims := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{ID: "jti", Subject: "1"}}
+	tests := []struct {
+		name               string
+		body               string
+		setup              func(ms *services.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "invalid request body",
+			body:               `{"refresh_token":`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Request body is not valid json","trace_id":"123"}`,
+		},
+		{
+			name: "access token only",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().Logout(gomock.Any(), claims, "").Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "with refresh token",
+			body: `{"refresh_token":"refresh"}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().Logout(gomock.Any(), claims, "refresh").Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "refresh token of another user",
+			body: `{"refresh_token":"refresh"}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().Logout(gomock.Any(), claims, "refresh").Return(apperr.ErrForbidden)
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   `{"type":"about:blank","title":"Forbidden","status":403,"trace_id":"123"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", bytes.NewBufferString(tt.body))
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "123")
+			ctx = context.WithValue(ctx, auth.Key, claims)
+			c.Request = httpRequest.WithContext(ctx)
+
+			mc := gomock.NewController(t)
+			ms := services.NewMockUserService(mc)
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+
+			h := &handler{
+				users: ms,
+			}
+			h.Logout(c)
+			c.Writer.WriteHeaderNow()
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_JWKS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	a := newTestAuth(t)
+	rr := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rr)
+	c.Request, _ = http.NewRequest(http.MethodGet, "http://test.com:8080/.well-known/jwks.json", nil)
+
+	h := &handler{
+		a: a,
+	}
+	h.JWKS(c)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var set auth.JWKSet
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &set))
+	assert.Equal(t, a.JWKS(), set)
+}
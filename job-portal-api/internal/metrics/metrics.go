@@ -0,0 +1,185 @@
+// Package metrics counts what the api does, from the requests it serves and the queries
+// they run down to the jobs posted, and exposes the counts to Prometheus.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gorm.io/gorm"
+)
+
+const namespace = "job_portal"
+
+// Metrics are the metrics of one api. Each keeps its own registry, so apis started side by
+// side, as in the tests, do not count each other's requests. The methods of a nil Metrics
+// do nothing.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	inFlight        *prometheus.GaugeVec
+	queryDuration   *prometheus.HistogramVec
+
+	jobsPosted    prometheus.Counter
+	loginsFailed  prometheus.Counter
+	registrations prometheus.Counter
+}
+
+// New returns Metrics registered along with the metrics of the go runtime and the process.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Requests served, by method, route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Time taken to serve requests, by method, route pattern and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_in_flight",
+			Help:      "Requests being served, by method and route pattern.",
+		}, []string{"method", "route"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      "query_duration_seconds",
+			Help:      "Time taken by database statements, by kind of statement and table.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 2, 12),
+		}, []string{"operation", "table"}),
+		jobsPosted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "jobs_posted_total",
+			Help:      "Jobs posted by employers.",
+		}),
+		loginsFailed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "logins_failed_total",
+			Help:      "Logins refused because of a wrong email or password.",
+		}),
+		registrations: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "registrations_total",
+			Help:      "Users registered through the api.",
+		}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests, m.requestDuration, m.inFlight, m.queryDuration,
+		m.jobsPosted, m.loginsFailed, m.registrations,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// StartRequest counts a request to route as in flight until done is called with the
+// status it was answered with.
+func (m *Metrics) StartRequest(method, route string) (done func(status int)) {
+	if m == nil {
+		return func(int) {}
+	}
+	start := time.Now()
+	inFlight := m.inFlight.WithLabelValues(method, route)
+	inFlight.Inc()
+	return func(status int) {
+		inFlight.Dec()
+		code := strconv.Itoa(status)
+		m.requests.WithLabelValues(method, route, code).Inc()
+		m.requestDuration.WithLabelValues(method, route, code).Observe(time.Since(start).Seconds())
+	}
+}
+
+// JobPosted counts a job posted by an employer.
+func (m *Metrics) JobPosted() {
+	if m != nil {
+		m.jobsPosted.Inc()
+	}
+}
+
+// LoginFailed counts a login refused because of its credentials.
+func (m *Metrics) LoginFailed() {
+	if m != nil {
+		m.loginsFailed.Inc()
+	}
+}
+
+// UserRegistered counts a user registered through the api.
+func (m *Metrics) UserRegistered() {
+	if m != nil {
+		m.registrations.Inc()
+	}
+}
+
+// startKey holds the time a statement started at in its gorm instance.
+const startKey = "metrics:start"
+
+// InstrumentDB times every statement run through db and exports the stats of its
+// connection pool.
+func (m *Metrics) InstrumentDB(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	err = m.registry.Register(collectors.NewDBStatsCollector(sqlDB, db.Dialector.Name()))
+	if err != nil {
+		return err
+	}
+
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().Before("*").Register("metrics:before_create", startStatement),
+		cb.Create().After("*").Register("metrics:after_create", m.endStatement("create")),
+		cb.Query().Before("*").Register("metrics:before_query", startStatement),
+		cb.Query().After("*").Register("metrics:after_query", m.endStatement("query")),
+		cb.Update().Before("*").Register("metrics:before_update", startStatement),
+		cb.Update().After("*").Register("metrics:after_update", m.endStatement("update")),
+		cb.Delete().Before("*").Register("metrics:before_delete", startStatement),
+		cb.Delete().After("*").Register("metrics:after_delete", m.endStatement("delete")),
+		cb.Row().Before("*").Register("metrics:before_row", startStatement),
+		cb.Row().After("*").Register("metrics:after_row", m.endStatement("row")),
+		cb.Raw().Before("*").Register("metrics:before_raw", startStatement),
+		cb.Raw().After("*").Register("metrics:after_raw", m.endStatement("raw")),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startStatement(db *gorm.DB) {
+	db.InstanceSet(startKey, time.Now())
+}
+
+func (m *Metrics) endStatement(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+		start, ok := v.(time.Time)
+		if !ok {
+			return
+		}
+		m.queryDuration.WithLabelValues(operation, db.Statement.Table).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/database"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/logger"
+)
+
+// scrape returns the lines m serves to Prometheus.
+func scrape(t *testing.T, m *Metrics) []string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	return strings.Split(rec.Body.String(), "\n")
+}
+
+func TestMetrics_InstrumentDB(t *testing.T) {
+	db, err := database.Open(config.DBConfig{Driver: config.DriverSQLite, Path: filepath.Join(t.TempDir(), "test.db")})
+	require.NoError(t, err)
+	db.Logger = logger.Discard
+	m := New()
+	require.NoError(t, m.InstrumentDB(db))
+
+	type note struct {
+		ID   int
+		Text string
+	}
+	require.NoError(t, db.Exec("CREATE TABLE notes (id integer PRIMARY KEY, text text)").Error)
+	require.NoError(t, db.Create(&note{ID: 1, Text: "a"}).Error)
+	require.NoError(t, db.Model(&note{}).Where("id = ?", 1).Update("text", "b").Error)
+	var notes []note
+	require.NoError(t, db.Find(&notes).Error)
+	require.NoError(t, db.Find(&notes).Error)
+	require.NoError(t, db.Delete(&note{}, 1).Error)
+
+	scraped := scrape(t, m)
+	for _, line := range []string{
+		`job_portal_db_query_duration_seconds_count{operation="raw",table=""} 1`,
+		`job_portal_db_query_duration_seconds_count{operation="create",table="notes"} 1`,
+		`job_portal_db_query_duration_seconds_count{operation="update",table="notes"} 1`,
+		`job_portal_db_query_duration_seconds_count{operation="query",table="notes"} 2`,
+		`job_portal_db_query_duration_seconds_count{operation="delete",table="notes"} 1`,
+		`go_sql_open_connections{db_name="sqlite"} 1`,
+	} {
+		assert.Contains(t, scraped, line)
+	}
+
+	// The pool of a database is exported once.
+	assert.Error(t, m.InstrumentDB(db))
+}
+
+func TestMetrics_nil(t *testing.T) {
+	var m *Metrics
+	assert.NotPanics(t, func() {
+		m.StartRequest(http.MethodGet, "/api/jobs")(http.StatusOK)
+		m.JobPosted()
+		m.LoginFailed()
+		m.UserRegistered()
+	})
+}
@@ -3,6 +3,7 @@ package middlewares
 import (
 	"context"
 	"errors"
+	"job-portal-api/internal/apperr"
 	"job-portal-api/internal/auth"
 	"net/http"
 	"strings"
@@ -17,7 +18,7 @@ func (m *Mid) Authenticate(next gin.HandlerFunc) gin.HandlerFunc {
 		traceId, ok := ctx.Value(TraceIdKey).(string)
 		if !ok {
 			log.Error().Msg("trace id not present in the context")
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+			apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 			return
 		}
 
@@ -26,14 +27,31 @@ func (m *Mid) Authenticate(next gin.HandlerFunc) gin.HandlerFunc {
 		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
 			err := errors.New("expected authorization header format: Bearer <token>")
 			log.Error().Err(err).Str("Trace Id", traceId).Send()
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			apperr.Abort(c, traceId, http.StatusUnauthorized, err.Error())
 			return
 		}
 
 		claims, err := m.a.ValidateToken(parts[1])
 		if err != nil {
 			log.Error().Err(err).Str("Trace Id", traceId).Send()
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+			apperr.Abort(c, traceId, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+			return
+		}
+
+		if claims.ID == "" {
+			log.Error().Str("Trace Id", traceId).Msg("token has no jti")
+			apperr.Abort(c, traceId, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+			return
+		}
+		revoked, err := m.rc.IsTokenRevoked(ctx, claims.ID)
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceId).Send()
+			apperr.Abort(c, traceId, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+		if revoked {
+			log.Error().Str("Trace Id", traceId).Str("jti", claims.ID).Msg("token has been revoked")
+			apperr.Abort(c, traceId, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
 			return
 		}
 
@@ -2,29 +2,64 @@ package middlewares
 
 import (
 	"context"
+	"job-portal-api/internal/tracing"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type key string
 
+// TraceIdKey holds the OpenTelemetry trace id of a request in its context, as the hex
+// string the logs of the request carry.
 const TraceIdKey key = "1"
 
+// Log runs the request in a span, a child of the one named by its traceparent header when
+// it has one, and logs its start and end with the trace id.
 func (m *Mid) Log() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		traceId := uuid.NewString()
-		ctx := c.Request.Context()
+		propagator := otel.GetTextMapPropagator()
+		ctx := propagator.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		name := c.Request.Method
+		if c.FullPath() != "" {
+			name += " " + c.FullPath()
+		}
+		ctx, span := tracing.Tracer().Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPMethod(c.Request.Method), semconv.HTTPTarget(c.Request.URL.Path)))
+		defer span.End()
+		if c.FullPath() != "" {
+			span.SetAttributes(semconv.HTTPRoute(c.FullPath()))
+		}
+
+		traceId := span.SpanContext().TraceID().String()
 		ctx = context.WithValue(ctx, TraceIdKey, traceId)
 		req := c.Request.WithContext(ctx)
 		c.Request = req
+		// The trace id is sent back so clients can quote it when reporting a failed request,
+		// and the trace context so callers can carry on the trace.
+		c.Header("X-Trace-Id", traceId)
+		propagator.Inject(ctx, propagation.HeaderCarrier(c.Writer.Header()))
 
 		log.Info().Str("Trace Id", traceId).Str("Method", c.Request.Method).
 			Str("URL Path", c.Request.URL.Path).Msg("request started")
 
-		defer log.Info().Str("Trace Id", traceId).Str("Method", c.Request.Method).
-			Str("URL Path", c.Request.URL.Path).
-			Int("status Code", c.Writer.Status()).Msg("Request processing completed")
+		defer func() {
+			status := c.Writer.Status()
+			span.SetAttributes(semconv.HTTPStatusCode(status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+			log.Info().Str("Trace Id", traceId).Str("Method", c.Request.Method).
+				Str("URL Path", c.Request.URL.Path).
+				Int("status Code", status).Msg("Request processing completed")
+		}()
 
 		c.Next()
 	}
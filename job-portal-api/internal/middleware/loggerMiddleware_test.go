@@ -0,0 +1,83 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordSpans makes the spans started until the end of the test go to the recorder it
+// returns, and the trace context travel in W3C headers.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	tp, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(tp)
+		otel.SetTextMapPropagator(propagator)
+	})
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return sr
+}
+
+func TestMid_Log(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := &Mid{}
+	r := gin.New()
+	r.Use(m.Log())
+	var traceId string
+	r.GET("/api/jobs/:jobID", func(c *gin.Context) {
+		traceId, _ = c.Request.Context().Value(TraceIdKey).(string)
+		c.Status(http.StatusInternalServerError)
+	})
+
+	t.Run("continues the trace of the caller", func(t *testing.T) {
+		sr := recordSpans(t)
+		req := httptest.NewRequest(http.MethodGet, "/api/jobs/5", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		req.Header.Set("tracestate", "gateway=abc")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceId)
+		assert.Equal(t, traceId, rec.Header().Get("X-Trace-Id"))
+		spans := sr.Ended()
+		require.Len(t, spans, 1)
+		span := spans[0]
+		assert.Equal(t, "GET /api/jobs/:jobID", span.Name())
+		assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+		assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+		assert.True(t, span.Parent().IsRemote())
+		assert.Equal(t, codes.Error, span.Status().Code)
+		assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+span.SpanContext().SpanID().String()+"-01",
+			rec.Header().Get("traceparent"))
+		assert.Equal(t, "gateway=abc", rec.Header().Get("tracestate"))
+	})
+
+	t.Run("starts a trace", func(t *testing.T) {
+		sr := recordSpans(t)
+		var ids []string
+		for i := 0; i < 2; i++ {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/jobs/5", nil))
+			assert.Equal(t, traceId, rec.Header().Get("X-Trace-Id"))
+			assert.True(t, strings.HasPrefix(rec.Header().Get("traceparent"), "00-"+traceId+"-"))
+			ids = append(ids, traceId)
+		}
+		assert.NotEqual(t, ids[0], ids[1])
+		for _, span := range sr.Ended() {
+			assert.False(t, span.Parent().IsValid(), "span %s has a parent", span.Name())
+		}
+	})
+}
@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"job-portal-api/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute labels the requests no route matched. Their paths and methods come from
+// the clients, so they are not used as labels.
+const unmatchedRoute = "unmatched"
+
+// Metrics counts and times the requests by route pattern, as in "GET /api/jobs/:jobID",
+// and the status they were answered with.
+func Metrics(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		method, route := c.Request.Method, c.FullPath()
+		if route == "" {
+			method, route = "", unmatchedRoute
+		}
+		done := m.StartRequest(method, route)
+		defer func() { done(c.Writer.Status()) }()
+		c.Next()
+	}
+}
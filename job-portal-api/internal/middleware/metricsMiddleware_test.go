@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"job-portal-api/internal/metrics"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mt := metrics.New()
+	r := gin.New()
+	r.Use(Metrics(mt), gin.Recovery())
+	r.GET("/api/jobs/:jobID", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/api/jobs/:jobID/close", func(c *gin.Context) { panic("boom") })
+
+	for _, path := range []string{"/api/jobs/1", "/api/jobs/2"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/jobs/1/close", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("BREW", "/coffee", nil))
+
+	rec := httptest.NewRecorder()
+	mt.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	scraped := strings.Split(rec.Body.String(), "\n")
+	for _, line := range []string{
+		`job_portal_http_requests_total{method="GET",route="/api/jobs/:jobID",status="200"} 2`,
+		`job_portal_http_requests_total{method="POST",route="/api/jobs/:jobID/close",status="500"} 1`,
+		`job_portal_http_requests_total{method="",route="unmatched",status="404"} 1`,
+		`job_portal_http_requests_in_flight{method="GET",route="/api/jobs/:jobID"} 0`,
+		`job_portal_http_request_duration_seconds_count{method="GET",route="/api/jobs/:jobID",status="200"} 2`,
+	} {
+		assert.Contains(t, scraped, line)
+	}
+}
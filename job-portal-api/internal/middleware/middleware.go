@@ -1,17 +1,27 @@
 package middlewares
 
 import (
+	"context"
 	"errors"
 	"job-portal-api/internal/auth"
 )
 
+// RevocationChecker reports whether the access token with the given jti has been revoked.
+type RevocationChecker interface {
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
+
 type Mid struct {
-	a *auth.Auth
+	a  *auth.Auth
+	rc RevocationChecker
 }
 
-func NewMid(a *auth.Auth) (Mid, error) {
+func NewMid(a *auth.Auth, rc RevocationChecker) (Mid, error) {
 	if a == nil {
 		return Mid{}, errors.New("auth can't be nil")
 	}
-	return Mid{a: a}, nil
+	if rc == nil {
+		return Mid{}, errors.New("revocation checker can't be nil")
+	}
+	return Mid{a: a, rc: rc}, nil
 }
@@ -0,0 +1,43 @@
+package middlewares
+
+import (
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/auth"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// Require only lets the request through to next when the authenticated user holds one
+// of the given roles. It must be wrapped by Authenticate so the claims are present.
+func (m *Mid) Require(roles ...string) func(next gin.HandlerFunc) gin.HandlerFunc {
+	return func(next gin.HandlerFunc) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			ctx := c.Request.Context()
+			traceId, ok := ctx.Value(TraceIdKey).(string)
+			if !ok {
+				log.Error().Msg("trace id not present in the context")
+				apperr.Abort(c, "", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+				return
+			}
+
+			claims, ok := ctx.Value(auth.Key).(auth.Claims)
+			if !ok {
+				log.Error().Str("Trace Id", traceId).Msg("claims not present in the context")
+				apperr.Abort(c, traceId, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+				return
+			}
+
+			for _, r := range roles {
+				if claims.Role == r {
+					next(c)
+					return
+				}
+			}
+
+			log.Error().Str("Trace Id", traceId).Str("role", claims.Role).Strs("required", roles).Msg("role not allowed")
+			apperr.Abort(c, traceId, http.StatusForbidden, http.StatusText(http.StatusForbidden))
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package middlewares
+
+import (
+	"context"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMid_Require(t *testing.T) {
+	tests := []struct {
+		name               string
+		ctx                func(ctx context.Context) context.Context
+		roles              []string
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "missing trace id",
+			ctx:                func(ctx context.Context) context.Context { return ctx },
+			roles:              []string{models.RoleAdmin},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   `{"type":"about:blank","title":"Internal Server Error","status":500}`,
+		},
+		{
+			name: "missing claims",
+			ctx: func(ctx context.Context) context.Context {
+				return context.WithValue(ctx, TraceIdKey, "123")
+			},
+			roles:              []string{models.RoleAdmin},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   `{"type":"about:blank","title":"Unauthorized","status":401,"trace_id":"123"}`,
+		},
+		{
+			name: "role not allowed",
+			ctx: func(ctx context.Context) context.Context {
+				ctx = context.WithValue(ctx, TraceIdKey, "123")
+				return context.WithValue(ctx, auth.Key, auth.Claims{Role: models.RoleCandidate})
+			},
+			roles:              []string{models.RoleEmployer, models.RoleAdmin},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   `{"type":"about:blank","title":"Forbidden","status":403,"trace_id":"123"}`,
+		},
+		{
+			name: "role allowed",
+			ctx: func(ctx context.Context) context.Context {
+				ctx = context.WithValue(ctx, TraceIdKey, "123")
+				return context.WithValue(ctx, auth.Key, auth.Claims{Role: models.RoleEmployer})
+			},
+			roles:              []string{models.RoleEmployer, models.RoleAdmin},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"msg":"ok"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com", nil)
+			c.Request = httpRequest.WithContext(tt.ctx(httpRequest.Context()))
+
+			m := &Mid{}
+			m.Require(tt.roles...)(func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"msg": "ok"})
+			})(c)
+
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueryTimeouts bounds the time the queries of a request may take. Routes, keyed by
+// method and path pattern as in "GET /api/jobs/:jobID", override Default. A timeout of
+// zero leaves the requests it applies to unbounded.
+type QueryTimeouts struct {
+	Default time.Duration
+	Routes  map[string]time.Duration
+}
+
+// For returns the timeout of a route.
+func (t QueryTimeouts) For(method, path string) time.Duration {
+	if d, ok := t.Routes[method+" "+path]; ok {
+		return d
+	}
+	return t.Default
+}
+
+// Timeout gives the context of a request the deadline of its route. The repositories run
+// their queries with that context, so queries still running at the deadline are
+// cancelled, like the ones of a client that went away.
+func Timeout(t QueryTimeouts) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		d := t.For(c.Request.Method, c.FullPath())
+		if d <= 0 {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	timeouts := QueryTimeouts{
+		Default: time.Second,
+		Routes: map[string]time.Duration{
+			"GET /api/jobs/search": time.Minute,
+			"GET /api/stream":      0,
+		},
+	}
+	tests := []struct {
+		name    string
+		path    string
+		want    time.Duration
+		unbound bool
+	}{
+		{name: "default", path: "/api/jobs/5", want: time.Second},
+		{name: "route override", path: "/api/jobs/search", want: time.Minute},
+		{name: "route without a limit", path: "/api/stream", unbound: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(Timeout(timeouts))
+			var deadline time.Time
+			var bounded bool
+			handler := func(c *gin.Context) {
+				deadline, bounded = c.Request.Context().Deadline()
+				c.Status(http.StatusOK)
+			}
+			r.GET("/api/jobs/search", handler)
+			r.GET("/api/jobs/:jobID", handler)
+			r.GET("/api/stream", handler)
+
+			start := time.Now()
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if tt.unbound {
+				assert.False(t, bounded, "request has a deadline")
+				return
+			}
+			assert.True(t, bounded, "request has no deadline")
+			assert.WithinDuration(t, start.Add(tt.want), deadline, 100*time.Millisecond)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// Statuses an application goes through. Only submitted applications that are still
+// being looked at can be withdrawn by the candidate.
+const (
+	ApplicationSubmitted = "submitted"
+	ApplicationInReview  = "in_review"
+	ApplicationRejected  = "rejected"
+	ApplicationHired     = "hired"
+	ApplicationWithdrawn = "withdrawn"
+)
+
+// Application is a candidate applying to a job, a candidate can apply to a job only once.
+type Application struct {
+	gorm.Model
+	JobID       uint   `json:"job_id" gorm:"not null;uniqueIndex:idx_job_applicant"`
+	UserID      uint   `json:"user_id" gorm:"not null;uniqueIndex:idx_job_applicant;index"`
+	CoverLetter string `json:"cover_letter,omitempty" gorm:"type:text"`
+	ResumeURL   string `json:"resume_url"`
+	Status      string `json:"status" gorm:"not null;default:submitted;index"`
+	// Stage is the pipeline stage of the company the application is in.
+	Stage string `json:"stage" gorm:"index"`
+	Job   *Job   `json:"job,omitempty"`
+}
+
+type NewApplication struct {
+	CoverLetter string `json:"cover_letter" validate:"max=5000"`
+	ResumeURL   string `json:"resume_url" validate:"required,weburl,max=2048"`
+}
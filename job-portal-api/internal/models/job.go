@@ -1,30 +1,212 @@
 package models
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
+// Roles a user can hold within a single company.
+const (
+	CompanyRoleOwner     = "owner"
+	CompanyRoleRecruiter = "recruiter"
+	CompanyRoleViewer    = "viewer"
+)
+
 type Companies struct {
 	gorm.Model
-	CompanyName string `json:"company_name"`
-	FoundedYear int    `json:"founded_year"`
-	Location    string `json:"location"`
-	UserId      uint   `json:"user_id"`
-	Address     string `json:"address"`
-	Jobs        []Job  `json:"jobs,omitempty" gorm:"foreignKey:CompanyID"`
+	CompanyName string          `json:"company_name"`
+	FoundedYear int             `json:"founded_year"`
+	Location    string          `json:"location"`
+	UserId      uint            `json:"user_id"`
+	Address     string          `json:"address"`
+	Jobs        []Job           `json:"jobs,omitempty" gorm:"foreignKey:CompanyID"`
+	Members     []CompanyMember `json:"members,omitempty" gorm:"foreignKey:CompanyID"`
+}
+
+type CompanyMember struct {
+	gorm.Model
+	CompanyID uint   `json:"company_id" gorm:"uniqueIndex:idx_company_member"`
+	UserID    uint   `json:"user_id" gorm:"uniqueIndex:idx_company_member"`
+	Role      string `json:"role" gorm:"not null"`
+}
+
+type NewCompanyMember struct {
+	UserID uint   `json:"user_id" validate:"required"`
+	Role   string `json:"role" validate:"required,oneof=owner recruiter viewer"`
 }
 
 type NewComapanies struct {
+	CompanyName string   `json:"company_name" validate:"required"`
+	FoundedYear int      `json:"founded_year" validate:"required,pastyear"`
+	Location    string   `json:"location" validate:"required"`
+	Address     string   `json:"address" validate:"required"`
+	Jobs        []NewJob `json:"jobs" validate:"dive"`
+}
+
+// UpdateCompany replaces every editable field of a company.
+type UpdateCompany struct {
 	CompanyName string `json:"company_name" validate:"required"`
-	FoundedYear int    `json:"founded_year" validate:"required,number"`
+	FoundedYear int    `json:"founded_year" validate:"required,pastyear"`
 	Location    string `json:"location" validate:"required"`
 	Address     string `json:"address" validate:"required"`
-	Jobs        []Job  `json:"jobs"`
 }
 
+// CompanyPatch changes only the fields that are present.
+type CompanyPatch struct {
+	CompanyName *string `json:"company_name" validate:"omitempty,min=1"`
+	FoundedYear *int    `json:"founded_year" validate:"omitempty,pastyear"`
+	Location    *string `json:"location" validate:"omitempty,min=1"`
+	Address     *string `json:"address" validate:"omitempty,min=1"`
+}
+
+// Statuses of a job posting, candidates can only apply to open jobs.
+const (
+	JobStatusOpen   = "open"
+	JobStatusClosed = "closed"
+)
+
+// Where the work of a job is done.
+const (
+	RemotePolicyOnsite = "onsite"
+	RemotePolicyHybrid = "hybrid"
+	RemotePolicyRemote = "remote"
+)
+
+// Kinds of employment a job offers.
+const (
+	EmploymentFullTime   = "full_time"
+	EmploymentPartTime   = "part_time"
+	EmploymentContract   = "contract"
+	EmploymentInternship = "internship"
+)
+
+// Experience levels a job is aimed at.
+const (
+	SeniorityIntern = "intern"
+	SeniorityJunior = "junior"
+	SeniorityMid    = "mid"
+	SenioritySenior = "senior"
+	SeniorityLead   = "lead"
+)
+
+// Periods a salary range is expressed in.
+const (
+	SalaryPeriodHour  = "hour"
+	SalaryPeriodMonth = "month"
+	SalaryPeriodYear  = "year"
+)
+
 type Job struct {
 	gorm.Model
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	CompanyID   uint   `json:"CompanyID"`
+	Status      string `json:"status" gorm:"not null;default:open"`
+
+	// The salary range is in whole units of Currency, an ISO 4217 code, per SalaryPeriod.
+	SalaryMin      int        `json:"salary_min,omitempty"`
+	SalaryMax      int        `json:"salary_max,omitempty"`
+	SalaryCurrency string     `json:"salary_currency,omitempty" gorm:"size:3"`
+	SalaryPeriod   string     `json:"salary_period,omitempty"`
+	City           string     `json:"city,omitempty"`
+	Country        string     `json:"country,omitempty" gorm:"size:2;index"`
+	RemotePolicy   string     `json:"remote_policy,omitempty" gorm:"not null;default:onsite;index"`
+	EmploymentType string     `json:"employment_type,omitempty" gorm:"not null;default:full_time;index"`
+	Seniority      string     `json:"seniority,omitempty" gorm:"index"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty" gorm:"index"`
+	Skills         []JobSkill `json:"skills,omitempty" gorm:"foreignKey:JobID"`
+}
+
+// JobSkill is a skill a job asks for. Skills that are not required are nice to have.
+type JobSkill struct {
+	ID       uint   `json:"-"`
+	JobID    uint   `json:"-" gorm:"uniqueIndex:idx_job_skill"`
+	Name     string `json:"name" gorm:"not null;uniqueIndex:idx_job_skill"`
+	Required bool   `json:"required"`
+}
+
+// NewJob holds the details of a job posting sent by an employer.
+type NewJob struct {
+	Title          string        `json:"title" validate:"required"`
+	Description    string        `json:"description" validate:"required"`
+	SalaryMin      int           `json:"salary_min" validate:"omitempty,min=0"`
+	SalaryMax      int           `json:"salary_max" validate:"omitempty,gtefield=SalaryMin"`
+	SalaryCurrency string        `json:"salary_currency" validate:"required_with=SalaryMin SalaryMax,omitempty,iso4217"`
+	SalaryPeriod   string        `json:"salary_period" validate:"required_with=SalaryMin SalaryMax,omitempty,oneof=hour month year"`
+	City           string        `json:"city" validate:"omitempty,max=100"`
+	Country        string        `json:"country" validate:"omitempty,country"`
+	RemotePolicy   string        `json:"remote_policy" validate:"omitempty,oneof=onsite hybrid remote"`
+	EmploymentType string        `json:"employment_type" validate:"omitempty,oneof=full_time part_time contract internship"`
+	Seniority      string        `json:"seniority" validate:"omitempty,oneof=intern junior mid senior lead"`
+	ExpiresAt      *time.Time    `json:"expires_at" validate:"omitempty,gt"`
+	Skills         []NewJobSkill `json:"skills" validate:"max=30,unique=Name,dive"`
+}
+
+type NewJobSkill struct {
+	Name     string `json:"name" validate:"required,max=50"`
+	Required bool   `json:"required"`
+}
+
+// Job turns the posting into a job of the given company.
+func (nj NewJob) Job(companyID uint) Job {
+	return Job{
+		Title:          nj.Title,
+		Description:    nj.Description,
+		CompanyID:      companyID,
+		SalaryMin:      nj.SalaryMin,
+		SalaryMax:      nj.SalaryMax,
+		SalaryCurrency: nj.SalaryCurrency,
+		SalaryPeriod:   nj.SalaryPeriod,
+		City:           nj.City,
+		Country:        nj.Country,
+		RemotePolicy:   nj.RemotePolicy,
+		EmploymentType: nj.EmploymentType,
+		Seniority:      nj.Seniority,
+		ExpiresAt:      nj.ExpiresAt,
+		Skills:         JobSkills(nj.Skills),
+	}
+}
+
+// JobSkills converts the skills of a request, nil stays nil so callers can tell
+// "no change" apart from "no skills".
+func JobSkills(skills []NewJobSkill) []JobSkill {
+	if skills == nil {
+		return nil
+	}
+	js := make([]JobSkill, 0, len(skills))
+	for _, sk := range skills {
+		js = append(js, JobSkill{Name: sk.Name, Required: sk.Required})
+	}
+	return js
+}
+
+// UpdateJob replaces every editable field of a job. Fields left out are cleared.
+type UpdateJob NewJob
+
+// JobPatch changes only the fields that are present. The salary range is checked
+// against the stored one by the service.
+type JobPatch struct {
+	Title          *string        `json:"title" validate:"omitempty,min=1"`
+	Description    *string        `json:"description" validate:"omitempty,min=1"`
+	SalaryMin      *int           `json:"salary_min" validate:"omitempty,min=0"`
+	SalaryMax      *int           `json:"salary_max" validate:"omitempty,min=0"`
+	SalaryCurrency *string        `json:"salary_currency" validate:"omitempty,iso4217"`
+	SalaryPeriod   *string        `json:"salary_period" validate:"omitempty,oneof=hour month year"`
+	City           *string        `json:"city" validate:"omitempty,max=100"`
+	Country        *string        `json:"country" validate:"omitempty,country"`
+	RemotePolicy   *string        `json:"remote_policy" validate:"omitempty,oneof=onsite hybrid remote"`
+	EmploymentType *string        `json:"employment_type" validate:"omitempty,oneof=full_time part_time contract internship"`
+	Seniority      *string        `json:"seniority" validate:"omitempty,oneof=intern junior mid senior lead"`
+	ExpiresAt      *time.Time     `json:"expires_at" validate:"omitempty,gt"`
+	Skills         *[]NewJobSkill `json:"skills" validate:"omitempty,max=30,unique=Name,dive"`
+}
+
+// JobSearchResult is a job matching a search. The highlights are escaped html, the matching
+// words marked with <mark>.
+type JobSearchResult struct {
+	Job            Job     `json:"job"`
+	Rank           float64 `json:"rank"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
 }
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JobQuery is the set of filters of a job listing. Filters are combined with AND, the values
+// of a list filter with OR, except for skills which must all be asked for by the job.
+type JobQuery struct {
+	CompanyIDs      []uint     `query:"company_id" validate:"max=50,dive,min=1"`
+	City            string     `query:"city" validate:"max=100"`
+	Country         string     `query:"country" validate:"omitempty,country"`
+	RemotePolicies  []string   `query:"remote_policy" validate:"dive,oneof=onsite hybrid remote"`
+	EmploymentTypes []string   `query:"employment_type" validate:"dive,oneof=full_time part_time contract internship"`
+	Seniorities     []string   `query:"seniority" validate:"dive,oneof=intern junior mid senior lead"`
+	Status          string     `query:"status" validate:"omitempty,oneof=open closed"`
+	SalaryMin       int        `query:"salary_min" validate:"min=0"`
+	SalaryMax       int        `query:"salary_max" validate:"omitempty,gtefield=SalaryMin"`
+	SalaryCurrency  string     `query:"currency" validate:"required_with=SalaryMin SalaryMax,omitempty,iso4217"`
+	PostedSince     *time.Time `query:"posted_since" validate:"omitempty"`
+	Skills          []string   `query:"skills" validate:"max=10,dive,required,max=50"`
+}
+
+// ParseJobQuery reads the filters from the query string of a request. List filters take
+// either repeated parameters or comma separated values, posted_since takes a date or an
+// RFC 3339 time.
+func ParseJobQuery(v url.Values) (JobQuery, error) {
+	q := JobQuery{
+		City:            strings.TrimSpace(v.Get("city")),
+		Country:         strings.ToUpper(strings.TrimSpace(v.Get("country"))),
+		RemotePolicies:  list(v, "remote_policy"),
+		EmploymentTypes: list(v, "employment_type"),
+		Seniorities:     list(v, "seniority"),
+		Status:          v.Get("status"),
+		SalaryCurrency:  strings.ToUpper(strings.TrimSpace(v.Get("currency"))),
+		Skills:          list(v, "skills"),
+	}
+
+	for _, s := range list(v, "company_id") {
+		id, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return JobQuery{}, fmt.Errorf("company_id %q: %w", s, err)
+		}
+		q.CompanyIDs = append(q.CompanyIDs, uint(id))
+	}
+
+	var err error
+	q.SalaryMin, err = intParam(v, "salary_min")
+	if err != nil {
+		return JobQuery{}, err
+	}
+	q.SalaryMax, err = intParam(v, "salary_max")
+	if err != nil {
+		return JobQuery{}, err
+	}
+
+	if s := v.Get("posted_since"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t, err = time.Parse(time.DateOnly, s)
+		}
+		if err != nil {
+			return JobQuery{}, fmt.Errorf("posted_since %q is neither a date nor an RFC 3339 time", s)
+		}
+		q.PostedSince = &t
+	}
+	return q, nil
+}
+
+// list collects the values of a parameter given several times or separated by commas.
+func list(v url.Values, key string) []string {
+	var values []string
+	for _, raw := range v[key] {
+		for _, s := range strings.Split(raw, ",") {
+			s = strings.TrimSpace(s)
+			if s != "" {
+				values = append(values, s)
+			}
+		}
+	}
+	return values
+}
+
+func intParam(v url.Values, key string) (int, error) {
+	s := v.Get(key)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s %q: %w", key, s, err)
+	}
+	return n, nil
+}
+
+// FacetCount is the number of jobs that have Value for a facet. Label is a readable name
+// for values that are ids.
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// JobFacets counts the jobs matching a query for each value of the facets. The counts of a
+// facet ignore the filter on that facet, so they tell how many jobs choosing another value
+// would add.
+type JobFacets struct {
+	Companies       []FacetCount `json:"companies"`
+	Countries       []FacetCount `json:"countries"`
+	RemotePolicies  []FacetCount `json:"remote_policies"`
+	EmploymentTypes []FacetCount `json:"employment_types"`
+	Seniorities     []FacetCount `json:"seniorities"`
+	Skills          []FacetCount `json:"skills"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Orders a list can be sorted in. Ties are always broken on the id, in the same direction,
+// so every order is total and a cursor points at exactly one position.
+const (
+	SortNewest    = "newest"
+	SortOldest    = "oldest"
+	SortSalary    = "salary"
+	SortRelevance = "relevance"
+)
+
+// Cursor is the position of a row in a sorted list. It holds the values the list is sorted
+// on, Time for newest and oldest, Value for salary and relevance.
+type Cursor struct {
+	Sort string    `json:"s"`
+	ID   uint      `json:"id"`
+	Time time.Time `json:"t"`
+	// Value is the salary or the search rank of the row.
+	Value float64 `json:"v,omitempty"`
+	// Backward asks for the rows before the cursor instead of the ones after it.
+	Backward bool `json:"b,omitempty"`
+}
+
+// PageRequest asks for at most Limit rows of a list in the order of Sort, starting next
+// to Cursor or at the start of the list when Cursor is nil.
+type PageRequest struct {
+	Limit  int
+	Sort   string
+	Cursor *Cursor
+}
+
+// PageInfo holds the cursors of the pages around a page, they are nil when there is no
+// page in that direction.
+type PageInfo struct {
+	Next *Cursor
+	Prev *Cursor
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Outcomes of the terminal stages of a pipeline, an application in such a stage can't move anymore.
+const (
+	OutcomeHired    = "hired"
+	OutcomeRejected = "rejected"
+)
+
+// PipelineStage is one of the stages a company moves applications through. Next lists the
+// stages an application can move to from this one.
+type PipelineStage struct {
+	gorm.Model
+	CompanyID uint     `json:"company_id" gorm:"not null;uniqueIndex:idx_company_stage"`
+	Name      string   `json:"name" gorm:"not null;uniqueIndex:idx_company_stage"`
+	Position  int      `json:"position"`
+	Outcome   string   `json:"outcome,omitempty"`
+	Next      []string `json:"next" gorm:"serializer:json"`
+}
+
+// ApplicationStageChange records who moved an application between two stages, when and why.
+type ApplicationStageChange struct {
+	ID            uint      `json:"id"`
+	ApplicationID uint      `json:"application_id" gorm:"not null;index"`
+	FromStage     string    `json:"from_stage"`
+	ToStage       string    `json:"to_stage"`
+	Status        string    `json:"status"`
+	ChangedBy     uint      `json:"changed_by"`
+	Reason        string    `json:"reason,omitempty" gorm:"type:text"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// NewPipeline replaces the pipeline of a company, the stages are given in order and the
+// first one is where new applications start.
+type NewPipeline struct {
+	Stages []NewPipelineStage `json:"stages" validate:"required,min=2,max=20,unique=Name,dive"`
+}
+
+type NewPipelineStage struct {
+	Name    string   `json:"name" validate:"required,max=50"`
+	Outcome string   `json:"outcome" validate:"omitempty,oneof=hired rejected"`
+	Next    []string `json:"next" validate:"max=20,dive,required"`
+}
+
+// BulkMove moves several applications of the same company to one stage.
+type BulkMove struct {
+	ApplicationIDs []uint `json:"application_ids" validate:"required,min=1,max=100,unique"`
+	Stage          string `json:"stage" validate:"required"`
+	Reason         string `json:"reason" validate:"max=1000"`
+}
+
+// BulkReject moves several applications of the same company to the company's rejection stage.
+type BulkReject struct {
+	ApplicationIDs []uint `json:"application_ids" validate:"required,min=1,max=100,unique"`
+	Reason         string `json:"reason" validate:"max=1000"`
+}
@@ -4,15 +4,29 @@ import (
 	"gorm.io/gorm"
 )
 
+// Roles a user can hold across the whole portal.
+const (
+	RoleCandidate = "candidate"
+	RoleEmployer  = "employer"
+	RoleAdmin     = "admin"
+)
+
 type User struct {
 	gorm.Model
 	Name         string `json:"name"`
 	Email        string `json:"email" gorm:"unique;not null"`
 	PasswordHash string `json:"-"`
+	Role         string `json:"role" gorm:"not null;default:candidate"`
 }
 
 type NewUser struct {
 	Name     string `json:"name" validate:"required"`
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required"`
+	Password string `json:"password" validate:"required,password"`
+	// Role is the role the user signs up with, admins can only be appointed by another admin.
+	Role string `json:"role" validate:"omitempty,oneof=candidate employer"`
+}
+
+type UpdateRole struct {
+	Role string `json:"role" validate:"required,oneof=candidate employer admin"`
 }
@@ -0,0 +1,164 @@
+// Package pagination reads the paging parameters of list requests and links to the pages
+// around a page with opaque cursors.
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"job-portal-api/internal/models"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Bounds of the page size used when a Paginator does not set them.
+const (
+	DefaultSize = 20
+	MaxSize     = 100
+)
+
+var (
+	ErrInvalidLimit  = errors.New("invalid limit")
+	ErrInvalidSort   = errors.New("invalid sort")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// Paginator turns page requests into query parameters and back. Cursors are signed with an
+// HMAC of the secret so clients can neither forge a position nor change the sort of one.
+type Paginator struct {
+	secret      []byte
+	defaultSize int
+	maxSize     int
+}
+
+// New returns a Paginator signing cursors with secret. A size of zero picks the package
+// default.
+func New(secret []byte, defaultSize, maxSize int) (Paginator, error) {
+	if len(secret) == 0 {
+		return Paginator{}, errors.New("cursor secret cannot be empty")
+	}
+	if defaultSize < 0 || maxSize < 0 {
+		return Paginator{}, errors.New("page sizes cannot be negative")
+	}
+	p := Paginator{secret: secret, defaultSize: defaultSize, maxSize: maxSize}
+	if p.size() > p.max() {
+		return Paginator{}, fmt.Errorf("default page size %d is above the maximum %d", p.size(), p.max())
+	}
+	return p, nil
+}
+
+func (p Paginator) size() int {
+	if p.defaultSize == 0 {
+		return DefaultSize
+	}
+	return p.defaultSize
+}
+
+func (p Paginator) max() int {
+	if p.maxSize == 0 {
+		return MaxSize
+	}
+	return p.maxSize
+}
+
+// Parse reads the limit, sort and cursor parameters of a list that can be sorted by one of
+// sorts, the first one being the default. A limit above the maximum is lowered to it and a
+// cursor keeps the sort it was made for.
+func (p Paginator) Parse(v url.Values, sorts ...string) (models.PageRequest, error) {
+	req := models.PageRequest{Limit: p.size(), Sort: v.Get("sort")}
+	if s := v.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			return models.PageRequest{}, fmt.Errorf("limit %q: %w", s, ErrInvalidLimit)
+		}
+		req.Limit = min(n, p.max())
+	}
+	if token := v.Get("cursor"); token != "" {
+		c, err := p.Decode(token)
+		if err != nil {
+			return models.PageRequest{}, err
+		}
+		if req.Sort != "" && req.Sort != c.Sort {
+			return models.PageRequest{}, fmt.Errorf("cursor for sort %q used with %q: %w", c.Sort, req.Sort, ErrInvalidCursor)
+		}
+		req.Sort = c.Sort
+		req.Cursor = &c
+	}
+	if req.Sort == "" && len(sorts) > 0 {
+		req.Sort = sorts[0]
+	}
+	if !slices.Contains(sorts, req.Sort) {
+		return models.PageRequest{}, fmt.Errorf("sort %q: %w", req.Sort, ErrInvalidSort)
+	}
+	return req, nil
+}
+
+// Link returns the value of a Link header pointing at the pages around the page listed by
+// u, or "" when there are none. The links keep the other parameters of u.
+func (p Paginator) Link(u *url.URL, info models.PageInfo) (string, error) {
+	var links []string
+	for _, l := range []struct {
+		rel    string
+		cursor *models.Cursor
+	}{{"next", info.Next}, {"prev", info.Prev}} {
+		if l.cursor == nil {
+			continue
+		}
+		token, err := p.Encode(*l.cursor)
+		if err != nil {
+			return "", err
+		}
+		q := u.Query()
+		q.Set("cursor", token)
+		q.Set("sort", l.cursor.Sort)
+		target := url.URL{Path: u.Path, RawQuery: q.Encode()}
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, target.String(), l.rel))
+	}
+	return strings.Join(links, ", "), nil
+}
+
+// Encode turns c into an opaque token.
+func (p Paginator) Encode(c models.Cursor) (string, error) {
+	if len(p.secret) == 0 {
+		return "", errors.New("cursor secret is not set")
+	}
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("encoding cursor: %w", err)
+	}
+	body := base64.RawURLEncoding.EncodeToString(payload)
+	return body + "." + base64.RawURLEncoding.EncodeToString(p.sign(body)), nil
+}
+
+// Decode returns the cursor held by a token made by Encode.
+func (p Paginator) Decode(token string) (models.Cursor, error) {
+	body, sig, ok := strings.Cut(token, ".")
+	if !ok || len(p.secret) == 0 {
+		return models.Cursor{}, ErrInvalidCursor
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, p.sign(body)) {
+		return models.Cursor{}, ErrInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return models.Cursor{}, ErrInvalidCursor
+	}
+	var c models.Cursor
+	err = json.Unmarshal(payload, &c)
+	if err != nil {
+		return models.Cursor{}, fmt.Errorf("decoding cursor: %w", ErrInvalidCursor)
+	}
+	return c, nil
+}
+
+func (p Paginator) sign(body string) []byte {
+	m := hmac.New(sha256.New, p.secret)
+	m.Write([]byte(body))
+	return m.Sum(nil)
+}
@@ -0,0 +1,108 @@
+package pagination
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"job-portal-api/internal/models"
+)
+
+func newPaginator(t *testing.T) Paginator {
+	t.Helper()
+	p, err := New([]byte("test-secret"), 10, 50)
+	require.NoError(t, err)
+	return p
+}
+
+func TestPaginator_EncodeDecode(t *testing.T) {
+	p := newPaginator(t)
+	c := models.Cursor{Sort: models.SortSalary, ID: 7, Time: time.Date(2026, 3, 1, 10, 0, 0, 123456000, time.UTC), Value: 90000, Backward: true}
+
+	token, err := p.Encode(c)
+	require.NoError(t, err)
+	got, err := p.Decode(token)
+	require.NoError(t, err)
+	assert.Equal(t, c, got)
+
+	body, sig, _ := strings.Cut(token, ".")
+	forged, err := p.Encode(models.Cursor{Sort: models.SortSalary, ID: 1})
+	require.NoError(t, err)
+	forgedBody, _, _ := strings.Cut(forged, ".")
+	_, err = p.Decode(forgedBody + "." + sig)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	_, err = p.Decode(body)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	other, err := New([]byte("other-secret"), 0, 0)
+	require.NoError(t, err)
+	_, err = other.Decode(token)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestPaginator_Parse(t *testing.T) {
+	p := newPaginator(t)
+	cursor := models.Cursor{Sort: models.SortOldest, ID: 3}
+	token, err := p.Encode(cursor)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		query   string
+		want    models.PageRequest
+		wantErr error
+	}{
+		{name: "defaults", query: "", want: models.PageRequest{Limit: 10, Sort: models.SortNewest}},
+		{name: "limit and sort", query: "limit=5&sort=oldest", want: models.PageRequest{Limit: 5, Sort: models.SortOldest}},
+		{name: "limit is capped", query: "limit=500", want: models.PageRequest{Limit: 50, Sort: models.SortNewest}},
+		{name: "cursor keeps its sort", query: "cursor=" + token, want: models.PageRequest{Limit: 10, Sort: models.SortOldest, Cursor: &cursor}},
+		{name: "invalid limit", query: "limit=0", wantErr: ErrInvalidLimit},
+		{name: "unsupported sort", query: "sort=relevance", wantErr: ErrInvalidSort},
+		{name: "cursor of another sort", query: "sort=newest&cursor=" + token, wantErr: ErrInvalidCursor},
+		{name: "garbage cursor", query: "cursor=abc", wantErr: ErrInvalidCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+			got, err := p.Parse(v, models.SortNewest, models.SortOldest)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPaginator_Link(t *testing.T) {
+	p := newPaginator(t)
+	next := &models.Cursor{Sort: models.SortNewest, ID: 9}
+	prev := &models.Cursor{Sort: models.SortNewest, ID: 12, Backward: true}
+	u, err := url.Parse("/api/jobs?country=IN&limit=2")
+	require.NoError(t, err)
+
+	link, err := p.Link(u, models.PageInfo{})
+	require.NoError(t, err)
+	assert.Empty(t, link)
+
+	link, err = p.Link(u, models.PageInfo{Next: next, Prev: prev})
+	require.NoError(t, err)
+	nextToken, err := p.Encode(*next)
+	require.NoError(t, err)
+	prevToken, err := p.Encode(*prev)
+	require.NoError(t, err)
+	assert.Equal(t, `</api/jobs?country=IN&cursor=`+nextToken+`&limit=2&sort=newest>; rel="next", `+
+		`</api/jobs?country=IN&cursor=`+prevToken+`&limit=2&sort=newest>; rel="prev"`, link)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(nil, 0, 0)
+	assert.Error(t, err)
+	_, err = New([]byte("s"), 200, 0)
+	assert.Error(t, err)
+}
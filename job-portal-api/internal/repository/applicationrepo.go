@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"job-portal-api/internal/models"
+)
+
+func (r *Repo) CreateApplication(ctx context.Context, app models.Application) (models.Application, error) {
+	result := r.DB.WithContext(ctx).Create(&app)
+	if result.Error != nil {
+		return models.Application{}, dbError(result.Error, "application")
+	}
+	return app, nil
+}
+
+func (r *Repo) ViewApplication(ctx context.Context, id uint) (models.Application, error) {
+	var app models.Application
+	result := r.DB.WithContext(ctx).First(&app, id)
+	if result.Error != nil {
+		return models.Application{}, dbError(result.Error, "application")
+	}
+	return app, nil
+}
+
+// ViewApplicationByJobAndUser finds the application of a user to a job, whatever its status.
+func (r *Repo) ViewApplicationByJobAndUser(ctx context.Context, jid uint64, uid uint) (models.Application, error) {
+	var app models.Application
+	result := r.DB.WithContext(ctx).Where("job_id = ? AND user_id = ?", jid, uid).First(&app)
+	if result.Error != nil {
+		return models.Application{}, dbError(result.Error, "application")
+	}
+	return app, nil
+}
+
+// ListApplicationsByUser returns a page of the applications of a user together with the
+// jobs they were made to.
+func (r *Repo) ListApplicationsByUser(ctx context.Context, uid uint, p models.PageRequest) ([]models.Application, models.PageInfo, error) {
+	var apps []models.Application
+	result := r.DB.WithContext(ctx).Scopes(paginate(p, applicationColumns)).Preload("Job").Where("user_id = ?", uid).Find(&apps)
+	if result.Error != nil {
+		return nil, models.PageInfo{}, dbError(result.Error, "application")
+	}
+	apps, info := pageOf(apps, p, applicationCursor)
+	return apps, info, nil
+}
+
+// ListApplicationsByJob returns a page of the applications made to a job.
+func (r *Repo) ListApplicationsByJob(ctx context.Context, jid uint64, p models.PageRequest) ([]models.Application, models.PageInfo, error) {
+	var apps []models.Application
+	result := r.DB.WithContext(ctx).Scopes(paginate(p, applicationColumns)).Where("job_id = ?", jid).Find(&apps)
+	if result.Error != nil {
+		return nil, models.PageInfo{}, dbError(result.Error, "application")
+	}
+	apps, info := pageOf(apps, p, applicationCursor)
+	return apps, info, nil
+}
+
+func (r *Repo) UpdateApplicationStatus(ctx context.Context, id uint, status string) (models.Application, error) {
+	result := r.DB.WithContext(ctx).Model(&models.Application{}).Where("id = ?", id).Update("status", status)
+	if result.Error != nil {
+		return models.Application{}, dbError(result.Error, "application")
+	}
+	if result.RowsAffected == 0 {
+		return models.Application{}, dbError(gorm.ErrRecordNotFound, "application")
+	}
+	return r.ViewApplication(ctx, id)
+}
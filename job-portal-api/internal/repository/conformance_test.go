@@ -0,0 +1,796 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/database"
+	"job-portal-api/internal/models"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// store is what the conformance suite runs against, every implementation of the repository.
+type store = Repos
+
+func TestMemoryRepo(t *testing.T) {
+	testStore(t, func(t *testing.T) store { return NewMemoryRepository() })
+}
+
+// TestRepo runs the conformance suite against the postgres database JOB_PORTAL_TEST_DSN
+// points to. The tables are emptied before every test, so never point it at real data.
+func TestRepo(t *testing.T) {
+	dsn := os.Getenv("JOB_PORTAL_TEST_DSN")
+	if dsn == "" {
+		t.Skip("JOB_PORTAL_TEST_DSN is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true, Logger: logger.Discard})
+	require.NoError(t, err)
+	migrator, err := database.NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	testStore(t, func(t *testing.T) store {
+		err := db.Exec(`TRUNCATE users, refresh_tokens, revoked_tokens, companies, company_members, jobs,
+			job_skills, applications, pipeline_stages, application_stage_changes RESTART IDENTITY CASCADE`).Error
+		require.NoError(t, err)
+		return &Repo{DB: db}
+	})
+}
+
+// TestRepo_sqlite runs the conformance suite against a sqlite database in a temporary
+// file, a fresh one for every test.
+func TestRepo_sqlite(t *testing.T) {
+	testStore(t, func(t *testing.T) store { return newSQLiteRepo(t) })
+}
+
+// newSQLiteRepo returns a repository on a migrated sqlite database in a temporary file.
+func newSQLiteRepo(t *testing.T) *Repo {
+	t.Helper()
+	db, err := database.Open(config.DBConfig{Driver: config.DriverSQLite, Path: filepath.Join(t.TempDir(), "jobs.db")})
+	require.NoError(t, err)
+	db.Logger = logger.Discard
+	t.Cleanup(func() {
+		sqlDB, err := db.DB()
+		if err == nil {
+			sqlDB.Close()
+		}
+	})
+	migrator, err := database.NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+	return &Repo{DB: db}
+}
+
+// testStore runs every conformance test on an empty store made by newStore.
+func testStore(t *testing.T, newStore func(t *testing.T) store) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, s store)
+	}{
+		{"users", testUsers},
+		{"tokens", testTokens},
+		{"companies", testCompanies},
+		{"company deletes", testCompanyDeletes},
+		{"company members", testCompanyMembers},
+		{"jobs", testJobs},
+		{"job listings", testJobListings},
+		{"job search", testJobSearch},
+		{"applications", testApplications},
+		{"pipelines", testPipelines},
+		{"concurrent writes", testConcurrentWrites},
+		{"transactions", testTransactions},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStore(t))
+		})
+	}
+}
+
+var ctx = context.Background()
+
+func createUser(t *testing.T, s store, email, role string) models.User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("Passw0rd123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	u, err := s.CreateUser(ctx, models.User{Name: email, Email: email, PasswordHash: string(hash), Role: role})
+	require.NoError(t, err)
+	return u
+}
+
+func createCompany(t *testing.T, s store, owner uint, name string) models.Companies {
+	t.Helper()
+	c, err := s.CreateCompany(ctx, models.Companies{
+		CompanyName: name,
+		FoundedYear: 2001,
+		Location:    "Berlin",
+		UserId:      owner,
+		Members:     []models.CompanyMember{{UserID: owner, Role: models.CompanyRoleOwner}},
+	})
+	require.NoError(t, err)
+	return c
+}
+
+func createJob(t *testing.T, s store, job models.Job) models.Job {
+	t.Helper()
+	j, err := s.CreateJob(ctx, job)
+	require.NoError(t, err)
+	return j
+}
+
+func jobIDs(jobs []models.Job) []uint {
+	ids := make([]uint, 0, len(jobs))
+	for _, j := range jobs {
+		ids = append(ids, j.ID)
+	}
+	return ids
+}
+
+func testUsers(t *testing.T, s store) {
+	u := createUser(t, s, "ada@example.com", "")
+	assert.NotZero(t, u.ID)
+	assert.False(t, u.CreatedAt.IsZero())
+	assert.Equal(t, models.RoleCandidate, u.Role)
+
+	_, err := s.CreateUser(ctx, models.User{Name: "Ada", Email: "ada@example.com"})
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+
+	claims, err := s.CheckEmail(ctx, "ada@example.com", "Passw0rd123")
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprint(u.ID), claims.Subject)
+	assert.Equal(t, models.RoleCandidate, claims.Role)
+	_, err = s.CheckEmail(ctx, "ada@example.com", "wrong")
+	assert.ErrorIs(t, err, apperr.ErrUnauthorized)
+	_, err = s.CheckEmail(ctx, "nobody@example.com", "Passw0rd123")
+	assert.ErrorIs(t, err, apperr.ErrUnauthorized)
+	cost, err := bcrypt.Cost(unknownUserHash)
+	require.NoError(t, err, "unknown emails are checked against a valid hash")
+	assert.Equal(t, bcrypt.DefaultCost, cost, "and take as long as the passwords of registered users")
+
+	found, err := s.ViewUserByEmail(ctx, "ada@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, u.ID, found.ID)
+	_, err = s.ViewUserByEmail(ctx, "nobody@example.com")
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+	found, err = s.ViewUserById(ctx, u.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "ada@example.com", found.Email)
+	_, err = s.ViewUserById(ctx, u.ID+100)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+
+	u, err = s.UpdateUserRole(ctx, u.ID, models.RoleEmployer)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleEmployer, u.Role)
+	_, err = s.UpdateUserRole(ctx, u.ID+100, models.RoleEmployer)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+
+	c := createCompany(t, s, u.ID, "Acme")
+	claims, err = s.ViewUserClaims(ctx, u.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleEmployer, claims.Role)
+	assert.Equal(t, map[uint]string{c.ID: models.CompanyRoleOwner}, claims.CompanyRoles)
+	_, err = s.ViewUserClaims(ctx, u.ID+100)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+
+	token, err := s.CreateRefreshToken(ctx, models.RefreshToken{UserID: u.ID, TokenHash: "h1", FamilyID: "f1", ExpiresAt: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	require.NoError(t, s.UpdatePassword(ctx, u.ID, "new hash"))
+	token, err = s.ViewRefreshToken(ctx, token.TokenHash)
+	require.NoError(t, err)
+	assert.NotNil(t, token.RevokedAt, "changing the password revokes the refresh tokens")
+	found, err = s.ViewUserByEmail(ctx, "ada@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "new hash", found.PasswordHash)
+	assert.ErrorIs(t, s.UpdatePassword(ctx, u.ID+100, "hash"), apperr.ErrNotFound)
+}
+
+func testTokens(t *testing.T, s store) {
+	u := createUser(t, s, "ada@example.com", "")
+	expires := time.Now().Add(time.Hour)
+	first, err := s.CreateRefreshToken(ctx, models.RefreshToken{UserID: u.ID, TokenHash: "h1", FamilyID: "f1", ExpiresAt: expires})
+	require.NoError(t, err)
+	assert.NotZero(t, first.ID)
+	_, err = s.CreateRefreshToken(ctx, models.RefreshToken{UserID: u.ID, TokenHash: "h1", FamilyID: "f2", ExpiresAt: expires})
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+	second, err := s.CreateRefreshToken(ctx, models.RefreshToken{UserID: u.ID, TokenHash: "h2", FamilyID: "f1", ExpiresAt: expires})
+	require.NoError(t, err)
+
+	found, err := s.ViewRefreshToken(ctx, "h1")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, found.ID)
+	assert.Nil(t, found.RevokedAt)
+	_, err = s.ViewRefreshToken(ctx, "unknown")
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+
+	revoked, err := s.RevokeRefreshToken(ctx, first.ID)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	revoked, err = s.RevokeRefreshToken(ctx, first.ID)
+	require.NoError(t, err)
+	assert.False(t, revoked, "a token is revoked only once")
+
+	require.NoError(t, s.RevokeTokenFamily(ctx, "f1"))
+	found, err = s.ViewRefreshToken(ctx, "h2")
+	require.NoError(t, err)
+	assert.NotNil(t, found.RevokedAt)
+	revoked, err = s.RevokeRefreshToken(ctx, second.ID)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	require.NoError(t, s.RevokeAccessToken(ctx, "jti-1", time.Now().Add(time.Hour)))
+	assert.ErrorIs(t, s.RevokeAccessToken(ctx, "jti-1", time.Now().Add(time.Hour)), apperr.ErrConflict)
+	require.NoError(t, s.RevokeAccessToken(ctx, "jti-old", time.Now().Add(-time.Hour)))
+	ok, err := s.IsAccessTokenRevoked(ctx, "jti-1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = s.IsAccessTokenRevoked(ctx, "jti-2")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// Revoking another token forgets the ones that expired.
+	require.NoError(t, s.RevokeAccessToken(ctx, "jti-3", time.Now().Add(time.Hour)))
+	ok, err = s.IsAccessTokenRevoked(ctx, "jti-old")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func testCompanies(t *testing.T, s store) {
+	owner := createUser(t, s, "owner@example.com", models.RoleEmployer)
+	var ids []uint
+	for i := 0; i < 5; i++ {
+		ids = append(ids, createCompany(t, s, owner.ID, fmt.Sprintf("Company %d", i)).ID)
+	}
+	c, err := s.CreateCompany(ctx, models.Companies{
+		CompanyName: "With jobs",
+		UserId:      owner.ID,
+		Jobs:        []models.Job{{Title: "Welder", Skills: []models.JobSkill{{Name: "welding"}}}},
+	})
+	require.NoError(t, err)
+	require.Len(t, c.Jobs, 1)
+	assert.NotZero(t, c.Jobs[0].ID)
+	ids = append(ids, c.ID)
+
+	found, err := s.ViewCompanyById(ctx, ids[0])
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "Company 0", found[0].CompanyName)
+	_, err = s.ViewCompanyById(ctx, 1000)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+
+	// Pages walk the list newest first and back.
+	p := models.PageRequest{Limit: 4}
+	page, info, err := s.ViewCompanies(ctx, p)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{ids[5], ids[4], ids[3], ids[2]}, companyIDs(page))
+	require.NotNil(t, info.Next)
+	assert.Nil(t, info.Prev)
+	p.Cursor = info.Next
+	page, info, err = s.ViewCompanies(ctx, p)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{ids[1], ids[0]}, companyIDs(page))
+	assert.Nil(t, info.Next)
+	require.NotNil(t, info.Prev)
+	p.Cursor = info.Prev
+	page, _, err = s.ViewCompanies(ctx, p)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{ids[5], ids[4], ids[3], ids[2]}, companyIDs(page))
+	page, _, err = s.ViewCompanies(ctx, models.PageRequest{Sort: models.SortOldest, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{ids[0], ids[1]}, companyIDs(page))
+	_, _, err = s.ViewCompanies(ctx, models.PageRequest{Sort: models.SortSalary})
+	assert.Error(t, err)
+
+	updated, err := s.UpdateCompany(ctx, ids[0], map[string]interface{}{"company_name": "Renamed", "founded_year": 1999})
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", updated.CompanyName)
+	assert.Equal(t, 1999, updated.FoundedYear)
+	assert.Equal(t, "Berlin", updated.Location)
+	_, err = s.UpdateCompany(ctx, 1000, map[string]interface{}{"company_name": "Renamed"})
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+}
+
+func companyIDs(companies []models.Companies) []uint {
+	ids := make([]uint, 0, len(companies))
+	for _, c := range companies {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+func testCompanyDeletes(t *testing.T, s store) {
+	owner := createUser(t, s, "owner@example.com", models.RoleEmployer)
+	c := createCompany(t, s, owner.ID, "Acme")
+	alone := createJob(t, s, models.Job{Title: "Deleted on its own", CompanyID: c.ID})
+	along := createJob(t, s, models.Job{Title: "Deleted with the company", CompanyID: c.ID})
+	require.NoError(t, s.DeleteJob(ctx, uint64(alone.ID)))
+
+	require.NoError(t, s.DeleteCompany(ctx, c.ID))
+	assert.ErrorIs(t, s.DeleteCompany(ctx, c.ID), apperr.ErrNotFound)
+	_, err := s.ViewCompanyById(ctx, c.ID)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+	_, err = s.ViewJobDetailsById(ctx, uint64(along.ID))
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+	_, err = s.UpdateCompany(ctx, c.ID, map[string]interface{}{"company_name": "Renamed"})
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+
+	restored, err := s.RestoreCompany(ctx, c.ID, true)
+	require.NoError(t, err)
+	assert.False(t, restored.DeletedAt.Valid)
+	_, err = s.RestoreCompany(ctx, c.ID, true)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+	_, err = s.ViewJobDetailsById(ctx, uint64(along.ID))
+	assert.NoError(t, err, "jobs deleted with the company come back with it")
+	_, err = s.ViewJobDetailsById(ctx, uint64(alone.ID))
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+
+	require.NoError(t, s.DeleteCompany(ctx, c.ID))
+	_, err = s.RestoreCompany(ctx, c.ID, false)
+	require.NoError(t, err)
+	_, err = s.ViewJobDetailsById(ctx, uint64(along.ID))
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+}
+
+func testCompanyMembers(t *testing.T, s store) {
+	owner := createUser(t, s, "owner@example.com", models.RoleEmployer)
+	recruiter := createUser(t, s, "recruiter@example.com", models.RoleEmployer)
+	c := createCompany(t, s, owner.ID, "Acme")
+
+	m, err := s.AddCompanyMember(ctx, models.CompanyMember{CompanyID: c.ID, UserID: recruiter.ID, Role: models.CompanyRoleRecruiter})
+	require.NoError(t, err)
+	assert.NotZero(t, m.ID)
+	_, err = s.AddCompanyMember(ctx, models.CompanyMember{CompanyID: c.ID, UserID: recruiter.ID, Role: models.CompanyRoleViewer})
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+	_, err = s.AddCompanyMember(ctx, models.CompanyMember{CompanyID: 1000, UserID: recruiter.ID, Role: models.CompanyRoleViewer})
+	assert.ErrorIs(t, err, apperr.ErrValidation)
+	_, err = s.AddCompanyMember(ctx, models.CompanyMember{CompanyID: c.ID, UserID: 1000, Role: models.CompanyRoleViewer})
+	assert.ErrorIs(t, err, apperr.ErrValidation, "members must be registered users")
+
+	m, err = s.ViewCompanyMember(ctx, c.ID, recruiter.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CompanyRoleRecruiter, m.Role)
+	_, err = s.ViewCompanyMember(ctx, c.ID, 1000)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+
+	c, err = s.TransferCompanyOwnership(ctx, c.ID, recruiter.ID)
+	require.NoError(t, err)
+	assert.Equal(t, recruiter.ID, c.UserId)
+	m, err = s.ViewCompanyMember(ctx, c.ID, recruiter.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CompanyRoleOwner, m.Role)
+	m, err = s.ViewCompanyMember(ctx, c.ID, owner.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CompanyRoleRecruiter, m.Role)
+
+	outsider := createUser(t, s, "outsider@example.com", models.RoleEmployer)
+	c, err = s.TransferCompanyOwnership(ctx, c.ID, outsider.ID)
+	require.NoError(t, err)
+	m, err = s.ViewCompanyMember(ctx, c.ID, outsider.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CompanyRoleOwner, m.Role)
+	_, err = s.TransferCompanyOwnership(ctx, 1000, outsider.ID)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+}
+
+func testJobs(t *testing.T, s store) {
+	owner := createUser(t, s, "owner@example.com", models.RoleEmployer)
+	c := createCompany(t, s, owner.ID, "Acme")
+	expires := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	job := createJob(t, s, models.Job{
+		Title:     "Backend Engineer",
+		CompanyID: c.ID,
+		ExpiresAt: &expires,
+		Skills:    []models.JobSkill{{Name: "go", Required: true}, {Name: "sql"}},
+	})
+	assert.NotZero(t, job.ID)
+	assert.Equal(t, models.JobStatusOpen, job.Status)
+	assert.Equal(t, models.RemotePolicyOnsite, job.RemotePolicy)
+	assert.Equal(t, models.EmploymentFullTime, job.EmploymentType)
+
+	_, err := s.CreateJob(ctx, models.Job{Title: "Orphan", CompanyID: 1000})
+	assert.ErrorIs(t, err, apperr.ErrValidation)
+	_, err = s.CreateJob(ctx, models.Job{Title: "Twice", CompanyID: c.ID, Skills: []models.JobSkill{{Name: "go"}, {Name: "go"}}})
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+
+	found, err := s.ViewJobDetailsById(ctx, uint64(job.ID))
+	require.NoError(t, err)
+	assert.Equal(t, "Backend Engineer", found.Title)
+	assert.True(t, expires.Equal(*found.ExpiresAt))
+	require.Len(t, found.Skills, 2)
+	assert.Equal(t, "go", found.Skills[0].Name)
+	assert.True(t, found.Skills[0].Required)
+	_, err = s.ViewJobDetailsById(ctx, 1000)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+
+	found, err = s.UpdateJob(ctx, uint64(job.ID), map[string]interface{}{"title": "Platform Engineer", "salary_min": 100, "expires_at": nil}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Platform Engineer", found.Title)
+	assert.Equal(t, 100, found.SalaryMin)
+	assert.Len(t, found.Skills, 2, "skills stay when none are given")
+	found, err = s.UpdateJob(ctx, uint64(job.ID), map[string]interface{}{"expires_at": expires}, []models.JobSkill{{Name: "rust"}})
+	require.NoError(t, err)
+	require.Len(t, found.Skills, 1)
+	assert.Equal(t, "rust", found.Skills[0].Name)
+	require.NotNil(t, found.ExpiresAt)
+	found, err = s.UpdateJob(ctx, uint64(job.ID), nil, []models.JobSkill{})
+	require.NoError(t, err)
+	assert.Empty(t, found.Skills)
+	_, err = s.UpdateJob(ctx, 1000, map[string]interface{}{"title": "Nothing"}, nil)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+
+	// Stored jobs do not change with the values handed out.
+	found.Title = "Changed outside"
+	again, err := s.ViewJobDetailsById(ctx, uint64(job.ID))
+	require.NoError(t, err)
+	assert.Equal(t, "Platform Engineer", again.Title)
+
+	require.NoError(t, s.DeleteJob(ctx, uint64(job.ID)))
+	assert.ErrorIs(t, s.DeleteJob(ctx, uint64(job.ID)), apperr.ErrNotFound)
+	_, err = s.ViewJobDetailsById(ctx, uint64(job.ID))
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+	_, err = s.UpdateJob(ctx, uint64(job.ID), map[string]interface{}{"title": "Deleted"}, nil)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+	restored, err := s.RestoreJob(ctx, uint64(job.ID))
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, restored.ID)
+	_, err = s.RestoreJob(ctx, uint64(job.ID))
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+
+	require.NoError(t, s.DeleteJob(ctx, uint64(job.ID)))
+	require.NoError(t, s.DeleteCompany(ctx, c.ID))
+	_, err = s.RestoreJob(ctx, uint64(job.ID))
+	assert.ErrorIs(t, err, apperr.ErrConflict, "the company has to be restored first")
+	_, err = s.RestoreCompany(ctx, c.ID, false)
+	require.NoError(t, err)
+	_, err = s.ViewJobDetailsById(ctx, uint64(job.ID))
+	assert.ErrorIs(t, err, apperr.ErrNotFound, "the failed restore left the job deleted")
+	restored, err = s.RestoreJob(ctx, uint64(job.ID))
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, restored.ID)
+}
+
+func testJobListings(t *testing.T, s store) {
+	owner := createUser(t, s, "owner@example.com", models.RoleEmployer)
+	acme := createCompany(t, s, owner.ID, "Acme")
+	beta := createCompany(t, s, owner.ID, "Beta")
+	gone := createCompany(t, s, owner.ID, "Gone")
+	berlin := createJob(t, s, models.Job{Title: "Go", CompanyID: acme.ID, City: "Berlin", Country: "DE", RemotePolicy: models.RemotePolicyRemote,
+		SalaryMin: 60000, SalaryMax: 80000, SalaryCurrency: "EUR", Skills: []models.JobSkill{{Name: "Go"}, {Name: "Kafka"}}})
+	paris := createJob(t, s, models.Job{Title: "Rust", CompanyID: acme.ID, City: "Paris", Country: "FR",
+		SalaryMin: 50000, SalaryCurrency: "EUR", Skills: []models.JobSkill{{Name: "rust"}, {Name: "go"}}})
+	london := createJob(t, s, models.Job{Title: "Java", CompanyID: beta.ID, City: "London", Country: "GB", Status: models.JobStatusClosed,
+		SalaryMin: 90000, SalaryMax: 100000, SalaryCurrency: "GBP"})
+	createJob(t, s, models.Job{Title: "Hidden", CompanyID: gone.ID, Country: "DE"})
+	require.NoError(t, s.DeleteCompany(ctx, gone.ID))
+
+	tests := []struct {
+		name string
+		q    models.JobQuery
+		p    models.PageRequest
+		want []uint
+	}{
+		{name: "everything newest first", want: []uint{london.ID, paris.ID, berlin.ID}},
+		{name: "city in any case", q: models.JobQuery{City: "berlin"}, want: []uint{berlin.ID}},
+		{name: "companies", q: models.JobQuery{CompanyIDs: []uint{beta.ID}}, want: []uint{london.ID}},
+		{name: "remote policy", q: models.JobQuery{RemotePolicies: []string{models.RemotePolicyRemote, models.RemotePolicyHybrid}}, want: []uint{berlin.ID}},
+		{name: "status", q: models.JobQuery{Status: models.JobStatusOpen}, want: []uint{paris.ID, berlin.ID}},
+		{name: "every skill", q: models.JobQuery{Skills: []string{"GO", "kafka"}}, want: []uint{berlin.ID}},
+		{name: "any skill case", q: models.JobQuery{Skills: []string{"go"}}, want: []uint{paris.ID, berlin.ID}},
+		{name: "salary at least", q: models.JobQuery{SalaryMin: 55000, SalaryCurrency: "EUR"}, want: []uint{berlin.ID}},
+		{name: "salary at most", q: models.JobQuery{SalaryMax: 55000}, want: []uint{paris.ID}},
+		{name: "by salary", p: models.PageRequest{Sort: models.SortSalary}, want: []uint{london.ID, berlin.ID, paris.ID}},
+		{name: "oldest first", p: models.PageRequest{Sort: models.SortOldest, Limit: 2}, want: []uint{berlin.ID, paris.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs, _, err := s.FindAllJobs(ctx, tt.q, tt.p)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, jobIDs(jobs))
+		})
+	}
+
+	jobs, info, err := s.FindAllJobs(ctx, models.JobQuery{}, models.PageRequest{Sort: models.SortSalary, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{london.ID}, jobIDs(jobs))
+	jobs, _, err = s.FindAllJobs(ctx, models.JobQuery{}, models.PageRequest{Sort: models.SortSalary, Limit: 1, Cursor: info.Next})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{berlin.ID}, jobIDs(jobs))
+	assert.Len(t, jobs[0].Skills, 2)
+
+	jobs, _, err = s.ViewJobByCompanyId(ctx, acme.ID, models.PageRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{paris.ID, berlin.ID}, jobIDs(jobs))
+	jobs, _, err = s.ViewJobByCompanyId(ctx, gone.ID, models.PageRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+
+	facets, err := s.JobFacets(ctx, models.JobQuery{Country: "DE", Skills: []string{"go"}})
+	require.NoError(t, err)
+	assert.Equal(t, []models.FacetCount{{Value: "DE", Count: 1}, {Value: "FR", Count: 1}}, facets.Countries,
+		"a facet leaves its own filter out")
+	assert.Equal(t, []models.FacetCount{{Value: fmt.Sprint(acme.ID), Label: "Acme", Count: 1}}, facets.Companies)
+	assert.Equal(t, []models.FacetCount{{Value: models.RemotePolicyRemote, Count: 1}}, facets.RemotePolicies)
+	assert.Equal(t, []models.FacetCount{{Value: "go", Count: 1}, {Value: "kafka", Count: 1}}, facets.Skills)
+	assert.Empty(t, facets.Seniorities)
+}
+
+func testJobSearch(t *testing.T, s store) {
+	owner := createUser(t, s, "owner@example.com", models.RoleEmployer)
+	c := createCompany(t, s, owner.ID, "Acme")
+	backend := createJob(t, s, models.Job{Title: "Backend Engineer", CompanyID: c.ID,
+		Description: "Build the services of the platform with golang.", Skills: []models.JobSkill{{Name: "golang"}}})
+	frontend := createJob(t, s, models.Job{Title: "Frontend Engineer", CompanyID: c.ID,
+		Description: "Build the pages with react, some golang on the side."})
+	createJob(t, s, models.Job{Title: "Closed golang job", CompanyID: c.ID, Status: models.JobStatusClosed})
+	past := time.Now().Add(-time.Hour)
+	createJob(t, s, models.Job{Title: "Expired golang job", CompanyID: c.ID, ExpiresAt: &past})
+
+	tests := []struct {
+		q    string
+		want []uint
+	}{
+		{q: "golang", want: []uint{backend.ID, frontend.ID}},
+		{q: "engineer -frontend", want: []uint{backend.ID}},
+		{q: "react OR services", want: []uint{frontend.ID, backend.ID}},
+		{q: `"backend engineer"`, want: []uint{backend.ID}},
+		{q: "front*", want: []uint{frontend.ID}},
+		{q: "acme", want: []uint{frontend.ID, backend.ID}},
+		{q: "kotlin", want: []uint{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.q, func(t *testing.T) {
+			results, _, err := s.SearchJobs(ctx, tt.q, models.PageRequest{})
+			require.NoError(t, err)
+			ids := []uint{}
+			for _, r := range results {
+				ids = append(ids, r.Job.ID)
+			}
+			assert.ElementsMatch(t, tt.want, ids)
+		})
+	}
+
+	results, _, err := s.SearchJobs(ctx, "golang", models.PageRequest{})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, backend.ID, results[0].Job.ID, "a skill weighs more than the description")
+	assert.Greater(t, results[0].Rank, results[1].Rank)
+	assert.Contains(t, results[0].Snippet, "<mark>golang</mark>")
+
+	results, _, err = s.SearchJobs(ctx, "backend", models.PageRequest{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "<mark>Backend</mark> Engineer", results[0].TitleHighlight)
+
+	results, info, err := s.SearchJobs(ctx, `"" OR`, models.PageRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Nil(t, info.Next)
+
+	createJob(t, s, models.Job{Title: "Security <b>Analyst</b>", CompanyID: c.ID,
+		Description: "Audit the security of <script>alert(document.cookie)</script> our \uE000apps\uE001."})
+	results, _, err = s.SearchJobs(ctx, "security", models.PageRequest{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "<mark>Security</mark> &lt;b&gt;Analyst&lt;/b&gt;", results[0].TitleHighlight,
+		"the text employers write is escaped around the highlights")
+	assert.Contains(t, results[0].Snippet, "<mark>security</mark>")
+	assert.Contains(t, results[0].Snippet, "&lt;script&gt;")
+	assert.NotContains(t, results[0].Snippet, "<script>")
+	assert.Equal(t, 2, strings.Count(results[0].Snippet, "mark>"), "only the matches are marked")
+}
+
+func testApplications(t *testing.T, s store) {
+	owner := createUser(t, s, "owner@example.com", models.RoleEmployer)
+	ada := createUser(t, s, "ada@example.com", models.RoleCandidate)
+	bob := createUser(t, s, "bob@example.com", models.RoleCandidate)
+	c := createCompany(t, s, owner.ID, "Acme")
+	first := createJob(t, s, models.Job{Title: "First", CompanyID: c.ID})
+	second := createJob(t, s, models.Job{Title: "Second", CompanyID: c.ID})
+
+	a1, err := s.CreateApplication(ctx, models.Application{JobID: first.ID, UserID: ada.ID, ResumeURL: "https://cv/ada", Stage: "applied"})
+	require.NoError(t, err)
+	assert.Equal(t, models.ApplicationSubmitted, a1.Status)
+	_, err = s.CreateApplication(ctx, models.Application{JobID: first.ID, UserID: ada.ID})
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+	_, err = s.CreateApplication(ctx, models.Application{JobID: 1000, UserID: ada.ID})
+	assert.ErrorIs(t, err, apperr.ErrValidation)
+	a2, err := s.CreateApplication(ctx, models.Application{JobID: second.ID, UserID: ada.ID, Stage: "applied"})
+	require.NoError(t, err)
+	a3, err := s.CreateApplication(ctx, models.Application{JobID: first.ID, UserID: bob.ID, Stage: "screen"})
+	require.NoError(t, err)
+
+	found, err := s.ViewApplication(ctx, a1.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://cv/ada", found.ResumeURL)
+	_, err = s.ViewApplication(ctx, 1000)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+	found, err = s.ViewApplicationByJobAndUser(ctx, uint64(first.ID), bob.ID)
+	require.NoError(t, err)
+	assert.Equal(t, a3.ID, found.ID)
+	_, err = s.ViewApplicationByJobAndUser(ctx, uint64(second.ID), bob.ID)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+
+	apps, _, err := s.ListApplicationsByUser(ctx, ada.ID, models.PageRequest{})
+	require.NoError(t, err)
+	require.Len(t, apps, 2)
+	assert.Equal(t, a2.ID, apps[0].ID)
+	require.NotNil(t, apps[0].Job)
+	assert.Equal(t, "Second", apps[0].Job.Title)
+	apps, _, err = s.ListApplicationsByJob(ctx, uint64(first.ID), models.PageRequest{Sort: models.SortOldest})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{a1.ID, a3.ID}, []uint{apps[0].ID, apps[1].ID})
+	apps, err = s.ListApplicationsByIDs(ctx, []uint{a1.ID, a3.ID, 1000})
+	require.NoError(t, err)
+	assert.Len(t, apps, 2)
+
+	updated, err := s.UpdateApplicationStatus(ctx, a1.ID, models.ApplicationWithdrawn)
+	require.NoError(t, err)
+	assert.Equal(t, models.ApplicationWithdrawn, updated.Status)
+	_, err = s.UpdateApplicationStatus(ctx, 1000, models.ApplicationWithdrawn)
+	assert.ErrorIs(t, err, apperr.ErrNotFound)
+
+	n, err := s.CountOpenApplicationsOutside(ctx, c.ID, []string{"applied"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n, "only the open application in screen is outside")
+
+	err = s.MoveApplications(ctx, []models.ApplicationStageChange{
+		{ApplicationID: a2.ID, FromStage: "applied", ToStage: "screen", Status: models.ApplicationInReview, ChangedBy: owner.ID},
+		{ApplicationID: a3.ID, FromStage: "applied", ToStage: "offer", Status: models.ApplicationInReview, ChangedBy: owner.ID},
+	})
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+	found, err = s.ViewApplication(ctx, a2.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "applied", found.Stage, "no change is applied when one conflicts")
+
+	err = s.MoveApplications(ctx, []models.ApplicationStageChange{
+		{ApplicationID: a2.ID, FromStage: "applied", ToStage: "screen", Status: models.ApplicationInReview, ChangedBy: owner.ID},
+		{ApplicationID: a2.ID, FromStage: "screen", ToStage: "offer", Status: models.ApplicationInReview, ChangedBy: owner.ID, Reason: "great"},
+	})
+	require.NoError(t, err)
+	found, err = s.ViewApplication(ctx, a2.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "offer", found.Stage)
+	assert.Equal(t, models.ApplicationInReview, found.Status)
+	history, err := s.ListStageChanges(ctx, a2.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "screen", history[0].ToStage)
+	assert.Equal(t, "great", history[1].Reason)
+	assert.NotZero(t, history[1].ID)
+}
+
+func testPipelines(t *testing.T, s store) {
+	owner := createUser(t, s, "owner@example.com", models.RoleEmployer)
+	c := createCompany(t, s, owner.ID, "Acme")
+	stages, err := s.ViewPipeline(ctx, c.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stages)
+
+	stages, err = s.ReplacePipeline(ctx, c.ID, []models.PipelineStage{
+		{Name: "applied", Position: 0, Next: []string{"hired"}},
+		{Name: "hired", Position: 1, Outcome: models.OutcomeHired},
+	})
+	require.NoError(t, err)
+	require.Len(t, stages, 2)
+	assert.NotZero(t, stages[0].ID)
+	assert.Equal(t, c.ID, stages[1].CompanyID)
+
+	// The names of the old stages can be used again.
+	_, err = s.ReplacePipeline(ctx, c.ID, []models.PipelineStage{
+		{Name: "hired", Position: 1, Outcome: models.OutcomeHired},
+		{Name: "applied", Position: 0, Next: []string{"screen", "hired"}},
+		{Name: "screen", Position: 2},
+	})
+	require.NoError(t, err)
+	_, err = s.ReplacePipeline(ctx, c.ID, []models.PipelineStage{{Name: "twice"}, {Name: "twice", Position: 1}})
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+
+	stages, err = s.ViewPipeline(ctx, c.ID)
+	require.NoError(t, err)
+	require.Len(t, stages, 3)
+	assert.Equal(t, []string{"applied", "hired", "screen"}, []string{stages[0].Name, stages[1].Name, stages[2].Name})
+	assert.Equal(t, []string{"screen", "hired"}, stages[0].Next)
+}
+
+func testConcurrentWrites(t *testing.T, s store) {
+	owner := createUser(t, s, "owner@example.com", models.RoleEmployer)
+	c := createCompany(t, s, owner.ID, "Acme")
+
+	const writers = 20
+	var wg sync.WaitGroup
+	ids := make([]uint, writers)
+	errs := make([]error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			j, err := s.CreateJob(ctx, models.Job{Title: fmt.Sprintf("Job %d", i), CompanyID: c.ID})
+			ids[i], errs[i] = j.ID, err
+		}(i)
+	}
+	wg.Wait()
+
+	seen := map[uint]bool{}
+	for i := range ids {
+		require.NoError(t, errs[i])
+		assert.False(t, seen[ids[i]], "id %d is given out twice", ids[i])
+		seen[ids[i]] = true
+	}
+	jobs, _, err := s.ViewJobByCompanyId(ctx, c.ID, models.PageRequest{Limit: 100})
+	require.NoError(t, err)
+	assert.Len(t, jobs, writers)
+}
+
+func testTransactions(t *testing.T, s store) {
+	errAbort := errors.New("abort")
+	userExists := func(email string) bool {
+		_, err := s.ViewUserByEmail(ctx, email)
+		if errors.Is(err, apperr.ErrNotFound) {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+
+	// A unit of work that succeeds keeps all of its writes.
+	var company models.Companies
+	err := s.WithTx(ctx, func(tx Repos) error {
+		owner := createUser(t, tx, "committed@example.com", models.RoleEmployer)
+		company = createCompany(t, tx, owner.ID, "Committed")
+		_, err := tx.CreateJob(ctx, models.Job{Title: "Welder", CompanyID: company.ID})
+		return err
+	})
+	require.NoError(t, err)
+	assert.True(t, userExists("committed@example.com"))
+	jobs, _, err := s.ViewJobByCompanyId(ctx, company.ID, models.PageRequest{Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+
+	// A unit of work that fails keeps none, whether fn or one of the repositories fails.
+	err = s.WithTx(ctx, func(tx Repos) error {
+		createUser(t, tx, "aborted@example.com", models.RoleEmployer)
+		return errAbort
+	})
+	assert.ErrorIs(t, err, errAbort)
+	assert.False(t, userExists("aborted@example.com"))
+	err = s.WithTx(ctx, func(tx Repos) error {
+		createUser(t, tx, "duplicate@example.com", models.RoleEmployer)
+		_, err := tx.CreateUser(ctx, models.User{Email: "committed@example.com"})
+		return err
+	})
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+	assert.False(t, userExists("duplicate@example.com"))
+
+	// A nested unit of work that fails is undone alone.
+	err = s.WithTx(ctx, func(tx Repos) error {
+		createUser(t, tx, "outer@example.com", models.RoleEmployer)
+		err := tx.WithTx(ctx, func(tx Repos) error {
+			createUser(t, tx, "inner@example.com", models.RoleEmployer)
+			return errAbort
+		})
+		assert.ErrorIs(t, err, errAbort)
+		return tx.WithTx(ctx, func(tx Repos) error {
+			createUser(t, tx, "second-inner@example.com", models.RoleEmployer)
+			return nil
+		})
+	})
+	require.NoError(t, err)
+	assert.True(t, userExists("outer@example.com"))
+	assert.False(t, userExists("inner@example.com"))
+	assert.True(t, userExists("second-inner@example.com"))
+}
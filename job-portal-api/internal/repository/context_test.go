@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/models"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRepo_cancelledContext checks that every method of the repository runs its queries with
+// the context it is given, so a request that is cancelled or past its deadline stops
+// querying the database.
+func TestRepo_cancelledContext(t *testing.T) {
+	r := newSQLiteRepo(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	page := models.PageRequest{Limit: 10}
+	calls := map[string]func() error{
+		"WithTx": func() error {
+			return r.WithTx(ctx, func(tx Repos) error { return nil })
+		},
+		"CreateUser": func() error {
+			_, err := r.CreateUser(ctx, models.User{Name: "Ann", Email: "ann@example.com", PasswordHash: "x"})
+			return err
+		},
+		"CheckEmail": func() error {
+			_, err := r.CheckEmail(ctx, "ann@example.com", "secret")
+			return err
+		},
+		"UpdateUserRole": func() error {
+			_, err := r.UpdateUserRole(ctx, 1, models.RoleEmployer)
+			return err
+		},
+		"ViewUserByEmail": func() error {
+			_, err := r.ViewUserByEmail(ctx, "ann@example.com")
+			return err
+		},
+		"ViewUserById": func() error {
+			_, err := r.ViewUserById(ctx, 1)
+			return err
+		},
+		"UpdatePassword": func() error { return r.UpdatePassword(ctx, 1, "x") },
+		"ViewUserClaims": func() error {
+			_, err := r.ViewUserClaims(ctx, 1)
+			return err
+		},
+		"CreateRefreshToken": func() error {
+			_, err := r.CreateRefreshToken(ctx, models.RefreshToken{UserID: 1, TokenHash: "h", FamilyID: "f"})
+			return err
+		},
+		"ViewRefreshToken": func() error {
+			_, err := r.ViewRefreshToken(ctx, "h")
+			return err
+		},
+		"RevokeRefreshToken": func() error {
+			_, err := r.RevokeRefreshToken(ctx, 1)
+			return err
+		},
+		"RevokeTokenFamily": func() error { return r.RevokeTokenFamily(ctx, "f") },
+		"RevokeAccessToken": func() error { return r.RevokeAccessToken(ctx, "jti", time.Now().Add(time.Hour)) },
+		"IsAccessTokenRevoked": func() error {
+			_, err := r.IsAccessTokenRevoked(ctx, "jti")
+			return err
+		},
+		"CreateCompany": func() error {
+			_, err := r.CreateCompany(ctx, models.Companies{CompanyName: "Acme", Location: "Pune"})
+			return err
+		},
+		"ViewCompanies": func() error {
+			_, _, err := r.ViewCompanies(ctx, page)
+			return err
+		},
+		"ViewCompanyById": func() error {
+			_, err := r.ViewCompanyById(ctx, 1)
+			return err
+		},
+		"UpdateCompany": func() error {
+			_, err := r.UpdateCompany(ctx, 1, map[string]interface{}{"name": "Acme"})
+			return err
+		},
+		"DeleteCompany": func() error { return r.DeleteCompany(ctx, 1) },
+		"RestoreCompany": func() error {
+			_, err := r.RestoreCompany(ctx, 1, true)
+			return err
+		},
+		"AddCompanyMember": func() error {
+			_, err := r.AddCompanyMember(ctx, models.CompanyMember{CompanyID: 1, UserID: 1, Role: models.CompanyRoleOwner})
+			return err
+		},
+		"ViewCompanyMember": func() error {
+			_, err := r.ViewCompanyMember(ctx, 1, 1)
+			return err
+		},
+		"TransferCompanyOwnership": func() error {
+			_, err := r.TransferCompanyOwnership(ctx, 1, 2)
+			return err
+		},
+		"CreateJob": func() error {
+			_, err := r.CreateJob(ctx, models.Job{CompanyID: 1, Title: "Go developer"})
+			return err
+		},
+		"UpdateJob": func() error {
+			_, err := r.UpdateJob(ctx, 1, map[string]interface{}{"title": "Go developer"}, []models.JobSkill{{Name: "go"}})
+			return err
+		},
+		"DeleteJob": func() error { return r.DeleteJob(ctx, 1) },
+		"RestoreJob": func() error {
+			_, err := r.RestoreJob(ctx, 1)
+			return err
+		},
+		"FindAllJobs": func() error {
+			_, _, err := r.FindAllJobs(ctx, models.JobQuery{}, page)
+			return err
+		},
+		"JobFacets": func() error {
+			_, err := r.JobFacets(ctx, models.JobQuery{})
+			return err
+		},
+		"ViewJobDetailsById": func() error {
+			_, err := r.ViewJobDetailsById(ctx, 1)
+			return err
+		},
+		"ViewJobByCompanyId": func() error {
+			_, _, err := r.ViewJobByCompanyId(ctx, 1, page)
+			return err
+		},
+		"SearchJobs": func() error {
+			_, _, err := r.SearchJobs(ctx, "golang", page)
+			return err
+		},
+		"CreateApplication": func() error {
+			_, err := r.CreateApplication(ctx, models.Application{JobID: 1, UserID: 1})
+			return err
+		},
+		"ViewApplication": func() error {
+			_, err := r.ViewApplication(ctx, 1)
+			return err
+		},
+		"ViewApplicationByJobAndUser": func() error {
+			_, err := r.ViewApplicationByJobAndUser(ctx, 1, 1)
+			return err
+		},
+		"ListApplicationsByUser": func() error {
+			_, _, err := r.ListApplicationsByUser(ctx, 1, page)
+			return err
+		},
+		"ListApplicationsByJob": func() error {
+			_, _, err := r.ListApplicationsByJob(ctx, 1, page)
+			return err
+		},
+		"UpdateApplicationStatus": func() error {
+			_, err := r.UpdateApplicationStatus(ctx, 1, models.ApplicationInReview)
+			return err
+		},
+		"ListApplicationsByIDs": func() error {
+			_, err := r.ListApplicationsByIDs(ctx, []uint{1})
+			return err
+		},
+		"MoveApplications": func() error {
+			return r.MoveApplications(ctx, []models.ApplicationStageChange{{ApplicationID: 1, FromStage: "applied", ToStage: "screening"}})
+		},
+		"ListStageChanges": func() error {
+			_, err := r.ListStageChanges(ctx, 1)
+			return err
+		},
+		"ViewPipeline": func() error {
+			_, err := r.ViewPipeline(ctx, 1)
+			return err
+		},
+		"ReplacePipeline": func() error {
+			_, err := r.ReplacePipeline(ctx, 1, []models.PipelineStage{{CompanyID: 1, Name: "applied", Position: 0}})
+			return err
+		},
+		"CountOpenApplicationsOutside": func() error {
+			_, err := r.CountOpenApplicationsOutside(ctx, 1, []string{"applied"})
+			return err
+		},
+	}
+
+	methods := reflect.TypeOf((*store)(nil)).Elem()
+	for i := 0; i < methods.NumMethod(); i++ {
+		name := methods.Method(i).Name
+		t.Run(name, func(t *testing.T) {
+			call, ok := calls[name]
+			if !ok {
+				t.Fatalf("no call for %s, add one", name)
+			}
+			err := call()
+			assert.True(t, errors.Is(err, context.Canceled), "got %v", err)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/apperr"
+
+	"gorm.io/gorm"
+)
+
+// dbError turns an error of the database about a resource into an error of the apperr
+// package, so the layers above never look at gorm or driver errors. Errors of no known kind
+// and errors that were already turned are returned unchanged.
+//
+// Driver errors are recognised through the gorm errors they are translated to, see the
+// TranslateError setting of the database.
+func dbError(err error, resource string) error {
+	var e *apperr.Error
+	if err == nil || errors.As(err, &e) {
+		return err
+	}
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return apperr.Wrap(apperr.ErrNotFound, err, resource+" not found")
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return apperr.Wrap(apperr.ErrConflict, err, resource+" already exists")
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return apperr.Wrap(apperr.ErrValidation, err, resource+" refers to a resource that does not exist")
+	case errors.Is(err, context.DeadlineExceeded):
+		return apperr.Wrap(apperr.ErrTimeout, err, "the database took too long to answer")
+	}
+	return err
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"job-portal-api/internal/apperr"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func Test_dbError(t *testing.T) {
+	other := errors.New("connection refused")
+	tests := []struct {
+		name       string
+		err        error
+		wantKind   error
+		wantDetail string
+	}{
+		{name: "no error"},
+		{name: "record not found", err: gorm.ErrRecordNotFound, wantKind: apperr.ErrNotFound, wantDetail: "job not found"},
+		{name: "unique violation", err: fmt.Errorf("insert: %w", gorm.ErrDuplicatedKey), wantKind: apperr.ErrConflict, wantDetail: "job already exists"},
+		{name: "foreign key violation", err: gorm.ErrForeignKeyViolated, wantKind: apperr.ErrValidation, wantDetail: "job refers to a resource that does not exist"},
+		{name: "already translated", err: apperr.Wrap(apperr.ErrConflict, nil, "application 3 changed stage"), wantKind: apperr.ErrConflict, wantDetail: "application 3 changed stage"},
+		{name: "deadline", err: fmt.Errorf("query: %w", context.DeadlineExceeded), wantKind: apperr.ErrTimeout, wantDetail: "the database took too long to answer"},
+		{name: "cancelled", err: context.Canceled, wantDetail: "context canceled"},
+		{name: "unknown error", err: other, wantDetail: "connection refused"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dbError(tt.err, "job")
+			if tt.err == nil {
+				if err != nil {
+					t.Fatalf("dbError() = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("dbError() = %v, does not wrap %v", err, tt.err)
+			}
+			if kind := apperr.Kind(err); kind != tt.wantKind {
+				t.Errorf("apperr.Kind() = %v, want %v", kind, tt.wantKind)
+			}
+			if detail := apperr.Detail(err); detail != tt.wantDetail {
+				t.Errorf("apperr.Detail() = %q, want %q", detail, tt.wantDetail)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"job-portal-api/internal/models"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Facets of a job listing, used to leave the filter of a facet out of its own counts.
+const (
+	facetNone           = ""
+	facetCompany        = "company"
+	facetCountry        = "country"
+	facetRemotePolicy   = "remote_policy"
+	facetEmploymentType = "employment_type"
+	facetSeniority      = "seniority"
+	facetSkills         = "skills"
+)
+
+// jobFilter applies every filter of q to a query on jobs except the one of the skip facet.
+// All values are bound as parameters.
+func jobFilter(q models.JobQuery, skip string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Scopes(activeCompany)
+		if len(q.CompanyIDs) > 0 && skip != facetCompany {
+			db = db.Where("jobs.company_id IN ?", q.CompanyIDs)
+		}
+		if q.City != "" {
+			db = db.Where("lower(jobs.city) = ?", strings.ToLower(q.City))
+		}
+		if q.Country != "" && skip != facetCountry {
+			db = db.Where("jobs.country = ?", q.Country)
+		}
+		if len(q.RemotePolicies) > 0 && skip != facetRemotePolicy {
+			db = db.Where("jobs.remote_policy IN ?", q.RemotePolicies)
+		}
+		if len(q.EmploymentTypes) > 0 && skip != facetEmploymentType {
+			db = db.Where("jobs.employment_type IN ?", q.EmploymentTypes)
+		}
+		if len(q.Seniorities) > 0 && skip != facetSeniority {
+			db = db.Where("jobs.seniority IN ?", q.Seniorities)
+		}
+		if q.Status != "" {
+			db = db.Where("jobs.status = ?", q.Status)
+		}
+		if q.SalaryCurrency != "" {
+			db = db.Where("jobs.salary_currency = ?", q.SalaryCurrency)
+		}
+		// A range overlaps the one asked for, a job without a maximum pays at least its minimum.
+		if q.SalaryMin > 0 {
+			db = db.Where(jobSalary+" >= ?", q.SalaryMin)
+		}
+		if q.SalaryMax > 0 {
+			db = db.Where("jobs.salary_min > 0 AND jobs.salary_min <= ?", q.SalaryMax)
+		}
+		if q.PostedSince != nil {
+			db = db.Where("jobs.created_at >= ?", *q.PostedSince)
+		}
+		if len(q.Skills) > 0 && skip != facetSkills {
+			skills := make([]string, 0, len(q.Skills))
+			for _, s := range q.Skills {
+				skills = append(skills, strings.ToLower(s))
+			}
+			db = db.Where(`jobs.id IN (SELECT job_id FROM job_skills WHERE lower(name) IN ?
+				GROUP BY job_id HAVING count(DISTINCT lower(name)) = ?)`, skills, len(uniq(skills)))
+		}
+		return db
+	}
+}
+
+func uniq(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+// maxSkillFacets is the number of skills the skills facet lists, the most asked for first.
+const maxSkillFacets = 20
+
+func (r *Repo) JobFacets(ctx context.Context, q models.JobQuery) (models.JobFacets, error) {
+	db := r.DB.WithContext(ctx)
+	var facets models.JobFacets
+
+	count := func(skip, column string, dst *[]models.FacetCount) error {
+		return db.Model(&models.Job{}).Scopes(jobFilter(q, skip)).
+			Select(column + " AS value, count(*) AS count").
+			Where(column + " <> ''").
+			Group(column).Order("count DESC, value").
+			Scan(dst).Error
+	}
+	err := count(facetCountry, "jobs.country", &facets.Countries)
+	if err != nil {
+		return models.JobFacets{}, dbError(err, "job")
+	}
+	err = count(facetRemotePolicy, "jobs.remote_policy", &facets.RemotePolicies)
+	if err != nil {
+		return models.JobFacets{}, dbError(err, "job")
+	}
+	err = count(facetEmploymentType, "jobs.employment_type", &facets.EmploymentTypes)
+	if err != nil {
+		return models.JobFacets{}, dbError(err, "job")
+	}
+	err = count(facetSeniority, "jobs.seniority", &facets.Seniorities)
+	if err != nil {
+		return models.JobFacets{}, dbError(err, "job")
+	}
+
+	var companies []struct {
+		ID    uint
+		Name  string
+		Count int64
+	}
+	err = db.Model(&models.Job{}).Scopes(jobFilter(q, facetCompany)).
+		Select("companies.id AS id, companies.company_name AS name, count(*) AS count").
+		Group("companies.id, companies.company_name").Order("count DESC, name").
+		Scan(&companies).Error
+	if err != nil {
+		return models.JobFacets{}, dbError(err, "job")
+	}
+	facets.Companies = make([]models.FacetCount, 0, len(companies))
+	for _, c := range companies {
+		facets.Companies = append(facets.Companies, models.FacetCount{
+			Value: strconv.FormatUint(uint64(c.ID), 10),
+			Label: c.Name,
+			Count: c.Count,
+		})
+	}
+
+	err = db.Model(&models.Job{}).Scopes(jobFilter(q, facetSkills)).
+		Joins("JOIN job_skills ON job_skills.job_id = jobs.id").
+		Select("lower(job_skills.name) AS value, count(DISTINCT jobs.id) AS count").
+		Group("lower(job_skills.name)").Order("count DESC, value").
+		Limit(maxSkillFacets).
+		Scan(&facets.Skills).Error
+	if err != nil {
+		return models.JobFacets{}, dbError(err, "job")
+	}
+	return facets, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"job-portal-api/internal/models"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRun returns a postgres gorm.DB that builds statements without running them.
+func dryRun(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("opening dry run db: %v", err)
+	}
+	return db
+}
+
+func Test_jobFilter(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	q := models.JobQuery{
+		CompanyIDs:     []uint{1, 2},
+		City:           "Pune",
+		Country:        "IN",
+		RemotePolicies: []string{"remote", "hybrid"},
+		SalaryMin:      50000,
+		SalaryCurrency: "INR",
+		PostedSince:    &since,
+		Skills:         []string{"Go", "go", "Kafka"},
+	}
+	tests := []struct {
+		name     string
+		skip     string
+		wantSQL  string
+		wantVars []interface{}
+	}{
+		{
+			name: "every filter",
+			skip: facetNone,
+			wantSQL: `SELECT "jobs"."id","jobs"."created_at","jobs"."updated_at","jobs"."deleted_at","jobs"."title","jobs"."description","jobs"."company_id","jobs"."status","jobs"."salary_min","jobs"."salary_max","jobs"."salary_currency","jobs"."salary_period","jobs"."city","jobs"."country","jobs"."remote_policy","jobs"."employment_type","jobs"."seniority","jobs"."expires_at" FROM "jobs" JOIN companies ON companies.id = jobs.company_id AND companies.deleted_at IS NULL ` +
+				`WHERE jobs.company_id IN ($1,$2) AND lower(jobs.city) = $3 AND jobs.country = $4 AND jobs.remote_policy IN ($5,$6) AND jobs.salary_currency = $7 ` +
+				`AND (CASE WHEN jobs.salary_max > 0 THEN jobs.salary_max ELSE jobs.salary_min END) >= $8 AND jobs.created_at >= $9 ` +
+				`AND jobs.id IN (SELECT job_id FROM job_skills WHERE lower(name) IN ($10,$11,$12)
+				GROUP BY job_id HAVING count(DISTINCT lower(name)) = $13) AND "jobs"."deleted_at" IS NULL`,
+			wantVars: []interface{}{uint(1), uint(2), "pune", "IN", "remote", "hybrid", "INR", 50000, since, "go", "go", "kafka", 2},
+		},
+		{
+			name:     "facet leaves its own filter out",
+			skip:     facetRemotePolicy,
+			wantVars: []interface{}{uint(1), uint(2), "pune", "IN", "INR", 50000, since, "go", "go", "kafka", 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var jobs []models.Job
+			stmt := dryRun(t).Scopes(jobFilter(q, tt.skip)).Find(&jobs).Statement
+			if tt.wantSQL != "" && stmt.SQL.String() != tt.wantSQL {
+				t.Errorf("SQL = %s\nwant  %s", stmt.SQL.String(), tt.wantSQL)
+			}
+			if len(stmt.Vars) != len(tt.wantVars) {
+				t.Fatalf("Vars = %v, want %v", stmt.Vars, tt.wantVars)
+			}
+			for i := range tt.wantVars {
+				if stmt.Vars[i] != tt.wantVars[i] {
+					t.Errorf("Vars[%d] = %#v, want %#v", i, stmt.Vars[i], tt.wantVars[i])
+				}
+			}
+		})
+	}
+}
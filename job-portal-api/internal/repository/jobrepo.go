@@ -2,86 +2,296 @@ package repository
 
 import (
 	"context"
-	"errors"
-	"github.com/rs/zerolog/log"
+	"fmt"
+	"gorm.io/gorm"
+	"job-portal-api/internal/apperr"
 	"job-portal-api/internal/models"
+	"time"
 )
 
+// activeCompany limits a query on jobs to the ones whose company has not been deleted.
+func activeCompany(db *gorm.DB) *gorm.DB {
+	return db.Joins("JOIN companies ON companies.id = jobs.company_id AND companies.deleted_at IS NULL")
+}
+
 func (r *Repo) ViewJobDetailsById(ctx context.Context, jid uint64) (models.Job, error) {
 	var job models.Job
-	result := r.DB.First(&job, jid)
+	result := r.DB.WithContext(ctx).Scopes(activeCompany).Preload("Skills").First(&job, "jobs.id = ?", jid)
+
+	if result.Error != nil {
+		return models.Job{}, dbError(result.Error, "job")
+	}
+	return job, nil
+}
+
+// UpdateJob applies changes, keyed by column name, to a job that has not been deleted.
+// When skills is not nil it replaces the skills of the job.
+func (r *Repo) UpdateJob(ctx context.Context, jid uint64, changes map[string]interface{}, skills []models.JobSkill) (models.Job, error) {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.Job{}).Where("id = ?", jid)
+		var result *gorm.DB
+		if len(changes) > 0 {
+			result = query.Updates(changes)
+		} else {
+			result = query.Update("updated_at", time.Now())
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if skills == nil {
+			return nil
+		}
+		err := tx.Where("job_id = ?", jid).Delete(&models.JobSkill{}).Error
+		if err != nil {
+			return err
+		}
+		if len(skills) == 0 {
+			return nil
+		}
+		for i := range skills {
+			skills[i].ID = 0
+			skills[i].JobID = uint(jid)
+		}
+		return tx.Create(&skills).Error
+	})
+	if err != nil {
+		return models.Job{}, dbError(err, "job")
+	}
+	return r.ViewJobDetailsById(ctx, jid)
+}
 
+func (r *Repo) DeleteJob(ctx context.Context, jid uint64) error {
+	result := r.DB.WithContext(ctx).Delete(&models.Job{}, jid)
 	if result.Error != nil {
-		return models.Job{}, result.Error
+		return dbError(result.Error, "job")
+	}
+	if result.RowsAffected == 0 {
+		return dbError(gorm.ErrRecordNotFound, "job")
+	}
+	return nil
+}
+
+// RestoreJob undoes the soft delete of a job. A job of a deleted company fails with
+// apperr.ErrConflict and stays deleted, the company has to be restored first.
+func (r *Repo) RestoreJob(ctx context.Context, jid uint64) (models.Job, error) {
+	var job models.Job
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&job, jid).Error
+		if err != nil {
+			return err
+		}
+		var companies int64
+		err = tx.Model(&models.Companies{}).Where("id = ?", job.CompanyID).Count(&companies).Error
+		if err != nil {
+			return err
+		}
+		if companies == 0 {
+			return apperr.Wrap(apperr.ErrConflict, nil, fmt.Sprintf("company %d of the job is deleted, restore the company first", job.CompanyID))
+		}
+		result := tx.Unscoped().Model(&models.Job{}).
+			Where("id = ? AND deleted_at IS NOT NULL", jid).
+			Update("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		job, err = (&Repo{DB: tx}).ViewJobDetailsById(ctx, jid)
+		return err
+	})
+	if err != nil {
+		return models.Job{}, dbError(err, "job")
 	}
 	return job, nil
 }
 
-func (r *Repo) ViewJobByCompanyId(ctx context.Context, id uint) ([]models.Job, error) {
+func (r *Repo) ViewJobByCompanyId(ctx context.Context, id uint, p models.PageRequest) ([]models.Job, models.PageInfo, error) {
 	var jobs []models.Job
-	result := r.DB.Where("company_id = ?", id).Find(&jobs)
+	result := r.DB.WithContext(ctx).Scopes(activeCompany, paginate(p, jobColumns)).Preload("Skills").Where("jobs.company_id = ?", id).Find(&jobs)
 
 	if result.Error != nil {
-		return nil, result.Error
+		return nil, models.PageInfo{}, dbError(result.Error, "job")
 	}
 
-	return jobs, nil
+	jobs, info := pageOf(jobs, p, jobCursor)
+	return jobs, info, nil
 }
 
 func (r *Repo) CreateJob(ctx context.Context, jobData models.Job) (models.Job, error) {
-	result := r.DB.Create(&jobData)
+	result := r.DB.WithContext(ctx).Create(&jobData)
 
 	if result.Error != nil {
-		return models.Job{}, result.Error
+		return models.Job{}, dbError(result.Error, "job")
 	}
 	return jobData, nil
 }
 
-func (r *Repo) FindAllJobs(ctx context.Context) ([]models.Job, error) {
+// FindAllJobs returns a page of the jobs of active companies matching q.
+func (r *Repo) FindAllJobs(ctx context.Context, q models.JobQuery, p models.PageRequest) ([]models.Job, models.PageInfo, error) {
 	var jobs []models.Job
-	result := r.DB.Find(&jobs)
+	result := r.DB.WithContext(ctx).Scopes(jobFilter(q, facetNone), paginate(p, jobColumns)).Preload("Skills").Find(&jobs)
 	if result.Error != nil {
-		return nil, result.Error
+		return nil, models.PageInfo{}, dbError(result.Error, "job")
 	}
-	return jobs, nil
+	jobs, info := pageOf(jobs, p, jobCursor)
+	return jobs, info, nil
 
 }
 
-func (r *Repo) FindJob(ctx context.Context, cid uint64) ([]models.Job, error) {
-	var jobData []models.Job
-	result := r.DB.Where("cid = ?", cid).Find(&jobData)
-	if result.Error != nil {
-		log.Info().Err(result.Error).Send()
-		return nil, errors.New("could not find the company")
-	}
-	return jobData, nil
-}
 func (r *Repo) CreateCompany(ctx context.Context, companyData models.Companies) (models.Companies, error) {
 	tx := r.DB.WithContext(ctx).Create(&companyData)
 	// If there's an error with the database transaction.
 	if tx.Error != nil {
 		// Return an empty 'Inventory' struct and the error.
-		return models.Companies{}, tx.Error
+		return models.Companies{}, dbError(tx.Error, "company")
 	}
 	return companyData, nil
 }
 
-func (r *Repo) ViewCompanies(ctx context.Context) ([]models.Companies, error) {
+// ViewCompanies returns a page of the companies that have not been deleted.
+func (r *Repo) ViewCompanies(ctx context.Context, p models.PageRequest) ([]models.Companies, models.PageInfo, error) {
 	var comp = make([]models.Companies, 0, 10)
-	result := r.DB.Find(&comp)
+	result := r.DB.WithContext(ctx).Scopes(paginate(p, companyColumns)).Find(&comp)
 	if result.Error != nil {
-		return []models.Companies{}, result.Error
+		return []models.Companies{}, models.PageInfo{}, dbError(result.Error, "company")
 	}
 
-	return comp, nil
+	comp, info := pageOf(comp, p, companyCursor)
+	return comp, info, nil
 }
 
 func (r *Repo) ViewCompanyById(ctx context.Context, cid uint) ([]models.Companies, error) {
 	var company []models.Companies
-	result := r.DB.Where("id = ?", cid).First(&company)
+	result := r.DB.WithContext(ctx).Where("id = ?", cid).First(&company)
+
+	if result.Error != nil {
+		return []models.Companies{}, dbError(result.Error, "company")
+	}
+	return company, nil
+}
 
+func (r *Repo) AddCompanyMember(ctx context.Context, member models.CompanyMember) (models.CompanyMember, error) {
+	result := r.DB.WithContext(ctx).Create(&member)
 	if result.Error != nil {
-		return []models.Companies{}, result.Error
+		return models.CompanyMember{}, dbError(result.Error, "company member")
+	}
+	return member, nil
+}
+
+func (r *Repo) ViewCompanyMember(ctx context.Context, cid uint, uid uint) (models.CompanyMember, error) {
+	var member models.CompanyMember
+	result := r.DB.WithContext(ctx).Where("company_id = ? AND user_id = ?", cid, uid).First(&member)
+	if result.Error != nil {
+		return models.CompanyMember{}, dbError(result.Error, "company member")
+	}
+	return member, nil
+}
+
+// TransferCompanyOwnership makes uid the owner of a company. The previous owners stay on
+// as recruiters.
+func (r *Repo) TransferCompanyOwnership(ctx context.Context, cid uint, uid uint) (models.Companies, error) {
+	var company models.Companies
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.First(&company, cid)
+		if result.Error != nil {
+			return result.Error
+		}
+		result = tx.Model(&models.CompanyMember{}).
+			Where("company_id = ? AND role = ? AND user_id <> ?", cid, models.CompanyRoleOwner, uid).
+			Update("role", models.CompanyRoleRecruiter)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// Members that were removed keep their row, which holds the unique (company, user) pair.
+		var member models.CompanyMember
+		result = tx.Unscoped().Where("company_id = ? AND user_id = ?", cid, uid).Limit(1).Find(&member)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			member = models.CompanyMember{CompanyID: cid, UserID: uid, Role: models.CompanyRoleOwner}
+			result = tx.Create(&member)
+		} else {
+			result = tx.Unscoped().Model(&member).Updates(map[string]interface{}{"role": models.CompanyRoleOwner, "deleted_at": nil})
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+
+		company.UserId = uid
+		return tx.Model(&company).Update("user_id", uid).Error
+	})
+	if err != nil {
+		return models.Companies{}, dbError(err, "company")
+	}
+	return company, nil
+}
+
+// UpdateCompany applies changes, keyed by column name, to a company that has not been deleted.
+func (r *Repo) UpdateCompany(ctx context.Context, cid uint, changes map[string]interface{}) (models.Companies, error) {
+	result := r.DB.WithContext(ctx).Model(&models.Companies{}).Where("id = ?", cid).Updates(changes)
+	if result.Error != nil {
+		return models.Companies{}, dbError(result.Error, "company")
+	}
+	if result.RowsAffected == 0 {
+		return models.Companies{}, dbError(gorm.ErrRecordNotFound, "company")
+	}
+	var company models.Companies
+	result = r.DB.WithContext(ctx).First(&company, cid)
+	if result.Error != nil {
+		return models.Companies{}, dbError(result.Error, "company")
+	}
+	return company, nil
+}
+
+// DeleteCompany soft deletes a company together with all of its jobs. The jobs get the
+// same deleted_at as the company so RestoreCompany can tell them apart from jobs that
+// were deleted on their own.
+func (r *Repo) DeleteCompany(ctx context.Context, cid uint) error {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.Companies{}).Where("id = ?", cid).Update("deleted_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Model(&models.Job{}).Where("company_id = ?", cid).Update("deleted_at", now).Error
+	})
+	return dbError(err, "company")
+}
+
+// RestoreCompany undoes the soft delete of a company and, when restoreJobs is set, of the
+// jobs that were deleted along with it.
+func (r *Repo) RestoreCompany(ctx context.Context, cid uint, restoreJobs bool) (models.Companies, error) {
+	var company models.Companies
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", cid).First(&company)
+		if result.Error != nil {
+			return result.Error
+		}
+		deletedAt := company.DeletedAt.Time
+
+		result = tx.Unscoped().Model(&company).Update("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		if !restoreJobs {
+			return nil
+		}
+		return tx.Unscoped().Model(&models.Job{}).
+			Where("company_id = ? AND deleted_at = ?", cid, deletedAt).
+			Update("deleted_at", nil).Error
+	})
+	if err != nil {
+		return models.Companies{}, dbError(err, "company")
 	}
+	company.DeletedAt = gorm.DeletedAt{}
 	return company, nil
 }
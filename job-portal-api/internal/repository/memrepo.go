@@ -0,0 +1,1274 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/models"
+	"maps"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// MemoryRepo keeps every table in memory. It implements Repos with the semantics of Repo:
+// ids are assigned in order, deletes are soft, the unique and foreign key constraints of
+// the schema hold and break with the same errors. It lets the api run without a database
+// in tests and demos.
+//
+// MemoryRepo is safe for concurrent use. Every method holds the lock for its whole run,
+// so the methods Repo runs in a transaction are atomic here too.
+type MemoryRepo struct {
+	mu sync.RWMutex
+	// lastID holds the last id given out in every table.
+	lastID map[string]uint
+
+	users        map[uint]models.User
+	tokens       map[uint]models.RefreshToken
+	revoked      map[string]models.RevokedToken
+	companies    map[uint]models.Companies
+	members      map[uint]models.CompanyMember
+	jobs         map[uint]models.Job
+	skills       map[uint][]models.JobSkill
+	applications map[uint]models.Application
+	stages       map[uint]models.PipelineStage
+	changes      map[uint]models.ApplicationStageChange
+}
+
+func NewMemoryRepository() *MemoryRepo {
+	return &MemoryRepo{
+		lastID:       map[string]uint{},
+		users:        map[uint]models.User{},
+		tokens:       map[uint]models.RefreshToken{},
+		revoked:      map[string]models.RevokedToken{},
+		companies:    map[uint]models.Companies{},
+		members:      map[uint]models.CompanyMember{},
+		jobs:         map[uint]models.Job{},
+		skills:       map[uint][]models.JobSkill{},
+		applications: map[uint]models.Application{},
+		stages:       map[uint]models.PipelineStage{},
+		changes:      map[uint]models.ApplicationStageChange{},
+	}
+}
+
+// WithTx runs fn on a copy of the tables and keeps the copy when fn returns nil. The lock
+// is held until then, other callers wait for the unit of work like for any other method.
+// Called on the copy it nests, like a savepoint.
+func (m *MemoryRepo) WithTx(ctx context.Context, fn func(tx Repos) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tx := m.snapshot()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	m.restore(tx)
+	return nil
+}
+
+// snapshot copies the tables. Stored rows are replaced, never changed in place, so the
+// rows themselves can be shared.
+func (m *MemoryRepo) snapshot() *MemoryRepo {
+	return &MemoryRepo{
+		lastID:       maps.Clone(m.lastID),
+		users:        maps.Clone(m.users),
+		tokens:       maps.Clone(m.tokens),
+		revoked:      maps.Clone(m.revoked),
+		companies:    maps.Clone(m.companies),
+		members:      maps.Clone(m.members),
+		jobs:         maps.Clone(m.jobs),
+		skills:       maps.Clone(m.skills),
+		applications: maps.Clone(m.applications),
+		stages:       maps.Clone(m.stages),
+		changes:      maps.Clone(m.changes),
+	}
+}
+
+// restore replaces the tables with the ones of a snapshot.
+func (m *MemoryRepo) restore(s *MemoryRepo) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	m.lastID, m.users, m.tokens, m.revoked = s.lastID, s.users, s.tokens, s.revoked
+	m.companies, m.members, m.jobs, m.skills = s.companies, s.members, s.jobs, s.skills
+	m.applications, m.stages, m.changes = s.applications, s.stages, s.changes
+}
+
+// clock holds the last time now returned.
+var clock struct {
+	sync.Mutex
+	last time.Time
+}
+
+// now returns the current time at the precision postgres stores. Every call returns a later
+// time than the one before, so that rows deleted apart, like a job and later its company,
+// are never taken for rows deleted together.
+func now() time.Time {
+	clock.Lock()
+	defer clock.Unlock()
+	t := time.Now().Truncate(time.Microsecond)
+	if !t.After(clock.last) {
+		t = clock.last.Add(time.Microsecond)
+	}
+	clock.last = t
+	return t
+}
+
+// assignID gives a row without an id the next one of its table. A row that brings its own
+// id must not collide with another one, like with a primary key.
+func assignID[T any](m *MemoryRepo, table string, rows map[uint]T, id *uint) error {
+	if *id == 0 {
+		m.lastID[table]++
+		*id = m.lastID[table]
+		return nil
+	}
+	if _, ok := rows[*id]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	m.lastID[table] = max(m.lastID[table], *id)
+	return nil
+}
+
+func stamp(model *gorm.Model) {
+	t := now()
+	if model.CreatedAt.IsZero() {
+		model.CreatedAt = t
+	}
+	if model.UpdatedAt.IsZero() {
+		model.UpdatedAt = t
+	}
+}
+
+func softDelete(model *gorm.Model, at time.Time) {
+	model.DeletedAt = gorm.DeletedAt{Time: at, Valid: true}
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+// sorted returns the rows of a table in the order of their ids.
+func sorted[T any](rows map[uint]T, keep func(T) bool) []T {
+	ids := make([]uint, 0, len(rows))
+	for id, row := range rows {
+		if keep(row) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	list := make([]T, 0, len(ids))
+	for _, id := range ids {
+		list = append(list, rows[id])
+	}
+	return list
+}
+
+var memorySchemas sync.Map
+
+// setColumns applies changes, keyed by column name, to the model dst points to the way
+// the Updates of gorm does.
+func setColumns(ctx context.Context, dst interface{}, changes map[string]interface{}) error {
+	s, err := schema.Parse(dst, &memorySchemas, schema.NamingStrategy{})
+	if err != nil {
+		return err
+	}
+	v := reflect.ValueOf(dst).Elem()
+	for column, value := range changes {
+		field := s.LookUpField(column)
+		if field == nil {
+			return fmt.Errorf("%s has no column %q", s.Table, column)
+		}
+		err = field.Set(ctx, v, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pageSlice orders rows by p and keeps a page of the ones on the side of the cursor p asks
+// for, the way paginate and pageOf do in the database.
+func pageSlice[T any](rows []T, p models.PageRequest, sc sortColumns, cursor func(T) models.Cursor) ([]T, models.PageInfo, error) {
+	_, desc, _, err := sc.key(p)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+	if p.Cursor != nil && p.Cursor.Backward {
+		desc = !desc
+	}
+	byTime := p.Sort == "" || p.Sort == models.SortNewest || p.Sort == models.SortOldest
+	compare := func(a, b models.Cursor) int {
+		switch {
+		case byTime && a.Time.Before(b.Time), !byTime && a.Value < b.Value:
+			return -1
+		case byTime && a.Time.After(b.Time), !byTime && a.Value > b.Value:
+			return 1
+		case a.ID < b.ID:
+			return -1
+		case a.ID > b.ID:
+			return 1
+		}
+		return 0
+	}
+	after := func(a, b models.Cursor) bool {
+		if desc {
+			return compare(a, b) < 0
+		}
+		return compare(a, b) > 0
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool { return after(cursor(rows[j]), cursor(rows[i])) })
+	if p.Cursor != nil {
+		kept := rows[:0]
+		for _, row := range rows {
+			if after(cursor(row), *p.Cursor) {
+				kept = append(kept, row)
+			}
+		}
+		rows = kept
+	}
+	if len(rows) > pageSize(p)+1 {
+		rows = rows[:pageSize(p)+1]
+	}
+	rows, info := pageOf(rows, p, cursor)
+	return rows, info, nil
+}
+
+func (m *MemoryRepo) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, u := range m.users {
+		if u.Email == user.Email {
+			return models.User{}, apperr.Wrap(apperr.ErrConflict, gorm.ErrDuplicatedKey, "email already registered")
+		}
+	}
+	err := assignID(m, "users", m.users, &user.ID)
+	if err != nil {
+		return models.User{}, dbError(err, "user")
+	}
+	stamp(&user.Model)
+	if user.Role == "" {
+		user.Role = models.RoleCandidate
+	}
+	m.users[user.ID] = user
+	return user, nil
+}
+
+// userByEmail finds a user that has not been deleted.
+func (m *MemoryRepo) userByEmail(email string) (models.User, error) {
+	for _, u := range m.users {
+		if u.Email == email && !u.DeletedAt.Valid {
+			return u, nil
+		}
+	}
+	return models.User{}, gorm.ErrRecordNotFound
+}
+
+func (m *MemoryRepo) user(uid uint) (models.User, error) {
+	u, ok := m.users[uid]
+	if !ok || u.DeletedAt.Valid {
+		return models.User{}, gorm.ErrRecordNotFound
+	}
+	return u, nil
+}
+
+func (m *MemoryRepo) CheckEmail(ctx context.Context, email string, password string) (auth.Claims, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	u, err := m.userByEmail(email)
+	if err != nil {
+		// An unknown email is reported like a wrong password, and takes as long to check, so
+		// logins cannot probe for accounts.
+		_ = bcrypt.CompareHashAndPassword(unknownUserHash, []byte(password))
+		return auth.Claims{}, apperr.Wrap(apperr.ErrUnauthorized, err, "invalid email or password")
+	}
+	err = bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password))
+	if err != nil {
+		return auth.Claims{}, apperr.Wrap(apperr.ErrUnauthorized, err, "invalid email or password")
+	}
+	return m.claims(u), nil
+}
+
+func (m *MemoryRepo) ViewUserClaims(ctx context.Context, uid uint) (auth.Claims, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	u, err := m.user(uid)
+	if err != nil {
+		return auth.Claims{}, dbError(err, "user")
+	}
+	return m.claims(u), nil
+}
+
+// claims builds the access token claims of u like Repo.claims does.
+func (m *MemoryRepo) claims(u models.User) auth.Claims {
+	companyRoles := map[uint]string{}
+	for _, member := range m.members {
+		if member.UserID == u.ID && !member.DeletedAt.Valid {
+			companyRoles[member.CompanyID] = member.Role
+		}
+	}
+	return newClaims(u, companyRoles)
+}
+
+func (m *MemoryRepo) UpdateUserRole(ctx context.Context, uid uint, role string) (models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u, err := m.user(uid)
+	if err != nil {
+		return models.User{}, dbError(err, "user")
+	}
+	u.Role, u.UpdatedAt = role, now()
+	m.users[uid] = u
+	return u, nil
+}
+
+func (m *MemoryRepo) ViewUserByEmail(ctx context.Context, email string) (models.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	u, err := m.userByEmail(email)
+	if err != nil {
+		return models.User{}, dbError(err, "user")
+	}
+	return u, nil
+}
+
+func (m *MemoryRepo) ViewUserById(ctx context.Context, uid uint) (models.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	u, err := m.user(uid)
+	if err != nil {
+		return models.User{}, dbError(err, "user")
+	}
+	return u, nil
+}
+
+// UpdatePassword replaces the password hash of a user and revokes its refresh tokens.
+func (m *MemoryRepo) UpdatePassword(ctx context.Context, uid uint, passwordHash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u, err := m.user(uid)
+	if err != nil {
+		return dbError(err, "user")
+	}
+	t := now()
+	u.PasswordHash, u.UpdatedAt = passwordHash, t
+	m.users[uid] = u
+	for id, token := range m.tokens {
+		if token.UserID == uid && token.RevokedAt == nil && !token.DeletedAt.Valid {
+			token.RevokedAt = cloneTime(&t)
+			m.tokens[id] = token
+		}
+	}
+	return nil
+}
+
+func cloneToken(t models.RefreshToken) models.RefreshToken {
+	t.RevokedAt = cloneTime(t.RevokedAt)
+	return t
+}
+
+func (m *MemoryRepo) CreateRefreshToken(ctx context.Context, token models.RefreshToken) (models.RefreshToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, t := range m.tokens {
+		if t.TokenHash == token.TokenHash {
+			return models.RefreshToken{}, dbError(gorm.ErrDuplicatedKey, "refresh token")
+		}
+	}
+	err := assignID(m, "refresh_tokens", m.tokens, &token.ID)
+	if err != nil {
+		return models.RefreshToken{}, dbError(err, "refresh token")
+	}
+	stamp(&token.Model)
+	token = cloneToken(token)
+	m.tokens[token.ID] = token
+	return cloneToken(token), nil
+}
+
+func (m *MemoryRepo) ViewRefreshToken(ctx context.Context, tokenHash string) (models.RefreshToken, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, t := range m.tokens {
+		if t.TokenHash == tokenHash && !t.DeletedAt.Valid {
+			return cloneToken(t), nil
+		}
+	}
+	return models.RefreshToken{}, dbError(gorm.ErrRecordNotFound, "refresh token")
+}
+
+// RevokeRefreshToken marks the token as used. It reports false when the token had already
+// been revoked.
+func (m *MemoryRepo) RevokeRefreshToken(ctx context.Context, id uint) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.tokens[id]
+	if !ok || t.DeletedAt.Valid || t.RevokedAt != nil {
+		return false, nil
+	}
+	revokedAt := now()
+	t.RevokedAt, t.UpdatedAt = &revokedAt, revokedAt
+	m.tokens[id] = t
+	return true, nil
+}
+
+func (m *MemoryRepo) RevokeTokenFamily(ctx context.Context, familyID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	revokedAt := now()
+	for id, t := range m.tokens {
+		if t.FamilyID == familyID && t.RevokedAt == nil && !t.DeletedAt.Valid {
+			t.RevokedAt, t.UpdatedAt = cloneTime(&revokedAt), revokedAt
+			m.tokens[id] = t
+		}
+	}
+	return nil
+}
+
+func (m *MemoryRepo) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.revoked[jti]; ok {
+		return dbError(gorm.ErrDuplicatedKey, "access token")
+	}
+	m.revoked[jti] = models.RevokedToken{JTI: jti, ExpiresAt: expiresAt}
+
+	// Tokens past their expiry are rejected anyway, so there is no need to remember them.
+	t := time.Now()
+	for id, r := range m.revoked {
+		if r.ExpiresAt.Before(t) {
+			delete(m.revoked, id)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryRepo) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.revoked[jti]
+	return ok, nil
+}
+
+func (m *MemoryRepo) company(cid uint) (models.Companies, error) {
+	c, ok := m.companies[cid]
+	if !ok || c.DeletedAt.Valid {
+		return models.Companies{}, gorm.ErrRecordNotFound
+	}
+	return c, nil
+}
+
+// CreateCompany stores a company together with its members and jobs.
+func (m *MemoryRepo) CreateCompany(ctx context.Context, company models.Companies) (models.Companies, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// The checks run before anything is stored, so a company is stored whole or not at all.
+	users := map[uint]bool{}
+	for _, member := range company.Members {
+		if users[member.UserID] {
+			return models.Companies{}, dbError(gorm.ErrDuplicatedKey, "company")
+		}
+		users[member.UserID] = true
+	}
+	for _, job := range company.Jobs {
+		err := checkSkills(job.Skills)
+		if err != nil {
+			return models.Companies{}, dbError(err, "company")
+		}
+	}
+	err := assignID(m, "companies", m.companies, &company.ID)
+	if err != nil {
+		return models.Companies{}, dbError(err, "company")
+	}
+	stamp(&company.Model)
+	members, jobs := company.Members, company.Jobs
+	company.Members, company.Jobs = nil, nil
+	m.companies[company.ID] = company
+
+	for _, member := range members {
+		member.CompanyID = company.ID
+		member, err = m.addMember(member)
+		if err != nil {
+			return models.Companies{}, dbError(err, "company")
+		}
+		company.Members = append(company.Members, member)
+	}
+	for _, job := range jobs {
+		job.CompanyID = company.ID
+		job, err = m.createJob(job)
+		if err != nil {
+			return models.Companies{}, dbError(err, "company")
+		}
+		company.Jobs = append(company.Jobs, job)
+	}
+	return company, nil
+}
+
+// ViewCompanies returns a page of the companies that have not been deleted.
+func (m *MemoryRepo) ViewCompanies(ctx context.Context, p models.PageRequest) ([]models.Companies, models.PageInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	companies := sorted(m.companies, func(c models.Companies) bool { return !c.DeletedAt.Valid })
+	companies, info, err := pageSlice(companies, p, companyColumns, companyCursor)
+	if err != nil {
+		return []models.Companies{}, models.PageInfo{}, dbError(err, "company")
+	}
+	return companies, info, nil
+}
+
+func (m *MemoryRepo) ViewCompanyById(ctx context.Context, cid uint) ([]models.Companies, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	c, err := m.company(cid)
+	if err != nil {
+		return []models.Companies{}, dbError(err, "company")
+	}
+	return []models.Companies{c}, nil
+}
+
+// UpdateCompany applies changes, keyed by column name, to a company that has not been deleted.
+func (m *MemoryRepo) UpdateCompany(ctx context.Context, cid uint, changes map[string]interface{}) (models.Companies, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, err := m.company(cid)
+	if err != nil {
+		return models.Companies{}, dbError(err, "company")
+	}
+	err = setColumns(ctx, &c, changes)
+	if err != nil {
+		return models.Companies{}, dbError(err, "company")
+	}
+	c.UpdatedAt = now()
+	m.companies[cid] = c
+	return c, nil
+}
+
+// DeleteCompany soft deletes a company together with all of its jobs, all with the same
+// deleted_at like Repo does.
+func (m *MemoryRepo) DeleteCompany(ctx context.Context, cid uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, err := m.company(cid)
+	if err != nil {
+		return dbError(err, "company")
+	}
+	t := now()
+	softDelete(&c.Model, t)
+	m.companies[cid] = c
+	for id, j := range m.jobs {
+		if j.CompanyID == cid && !j.DeletedAt.Valid {
+			softDelete(&j.Model, t)
+			m.jobs[id] = j
+		}
+	}
+	return nil
+}
+
+// RestoreCompany undoes the soft delete of a company and, when restoreJobs is set, of the
+// jobs that were deleted along with it.
+func (m *MemoryRepo) RestoreCompany(ctx context.Context, cid uint, restoreJobs bool) (models.Companies, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.companies[cid]
+	if !ok || !c.DeletedAt.Valid {
+		return models.Companies{}, dbError(gorm.ErrRecordNotFound, "company")
+	}
+	deletedAt := c.DeletedAt.Time
+	c.DeletedAt = gorm.DeletedAt{}
+	m.companies[cid] = c
+	if !restoreJobs {
+		return c, nil
+	}
+	for id, j := range m.jobs {
+		if j.CompanyID == cid && j.DeletedAt.Valid && j.DeletedAt.Time.Equal(deletedAt) {
+			j.DeletedAt = gorm.DeletedAt{}
+			m.jobs[id] = j
+		}
+	}
+	return c, nil
+}
+
+func (m *MemoryRepo) AddCompanyMember(ctx context.Context, member models.CompanyMember) (models.CompanyMember, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	member, err := m.addMember(member)
+	if err != nil {
+		return models.CompanyMember{}, dbError(err, "company member")
+	}
+	return member, nil
+}
+
+// addMember checks the constraints of company_members and stores member. Like in the
+// database, deleted rows still hold their (company, user) pair and deleted companies can
+// still be referred to.
+func (m *MemoryRepo) addMember(member models.CompanyMember) (models.CompanyMember, error) {
+	if _, ok := m.companies[member.CompanyID]; !ok {
+		return models.CompanyMember{}, gorm.ErrForeignKeyViolated
+	}
+	if _, ok := m.users[member.UserID]; !ok {
+		return models.CompanyMember{}, gorm.ErrForeignKeyViolated
+	}
+	for _, other := range m.members {
+		if other.CompanyID == member.CompanyID && other.UserID == member.UserID {
+			return models.CompanyMember{}, gorm.ErrDuplicatedKey
+		}
+	}
+	err := assignID(m, "company_members", m.members, &member.ID)
+	if err != nil {
+		return models.CompanyMember{}, err
+	}
+	stamp(&member.Model)
+	m.members[member.ID] = member
+	return member, nil
+}
+
+func (m *MemoryRepo) ViewCompanyMember(ctx context.Context, cid uint, uid uint) (models.CompanyMember, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, member := range m.members {
+		if member.CompanyID == cid && member.UserID == uid && !member.DeletedAt.Valid {
+			return member, nil
+		}
+	}
+	return models.CompanyMember{}, dbError(gorm.ErrRecordNotFound, "company member")
+}
+
+// TransferCompanyOwnership makes uid the owner of a company. The previous owners stay on
+// as recruiters.
+func (m *MemoryRepo) TransferCompanyOwnership(ctx context.Context, cid uint, uid uint) (models.Companies, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, err := m.company(cid)
+	if err != nil {
+		return models.Companies{}, dbError(err, "company")
+	}
+	t := now()
+	found := false
+	for id, member := range m.members {
+		if member.CompanyID != cid {
+			continue
+		}
+		switch {
+		case member.UserID == uid:
+			// Members that were removed keep their row, it is brought back.
+			member.Role, member.DeletedAt, member.UpdatedAt = models.CompanyRoleOwner, gorm.DeletedAt{}, t
+			found = true
+		case member.Role == models.CompanyRoleOwner && !member.DeletedAt.Valid:
+			member.Role, member.UpdatedAt = models.CompanyRoleRecruiter, t
+		default:
+			continue
+		}
+		m.members[id] = member
+	}
+	if !found {
+		_, err = m.addMember(models.CompanyMember{CompanyID: cid, UserID: uid, Role: models.CompanyRoleOwner})
+		if err != nil {
+			return models.Companies{}, dbError(err, "company")
+		}
+	}
+	c.UserId, c.UpdatedAt = uid, t
+	m.companies[cid] = c
+	return c, nil
+}
+
+// checkSkills enforces the unique (job, name) pair of job_skills on the skills of one job.
+func checkSkills(skills []models.JobSkill) error {
+	names := make(map[string]bool, len(skills))
+	for _, s := range skills {
+		if names[s.Name] {
+			return gorm.ErrDuplicatedKey
+		}
+		names[s.Name] = true
+	}
+	return nil
+}
+
+// createJob checks the constraints of jobs and stores job with its skills.
+func (m *MemoryRepo) createJob(job models.Job) (models.Job, error) {
+	if _, ok := m.companies[job.CompanyID]; !ok {
+		return models.Job{}, gorm.ErrForeignKeyViolated
+	}
+	err := checkSkills(job.Skills)
+	if err != nil {
+		return models.Job{}, err
+	}
+	err = assignID(m, "jobs", m.jobs, &job.ID)
+	if err != nil {
+		return models.Job{}, err
+	}
+	stamp(&job.Model)
+	if job.Status == "" {
+		job.Status = models.JobStatusOpen
+	}
+	if job.RemotePolicy == "" {
+		job.RemotePolicy = models.RemotePolicyOnsite
+	}
+	if job.EmploymentType == "" {
+		job.EmploymentType = models.EmploymentFullTime
+	}
+	m.setSkills(job.ID, job.Skills)
+	job.Skills = nil
+	job.ExpiresAt = cloneTime(job.ExpiresAt)
+	m.jobs[job.ID] = job
+	return m.withSkills(job), nil
+}
+
+// setSkills replaces the skills of a job, giving the new ones their ids.
+func (m *MemoryRepo) setSkills(jid uint, skills []models.JobSkill) {
+	stored := make([]models.JobSkill, 0, len(skills))
+	for _, s := range skills {
+		m.lastID["job_skills"]++
+		s.ID, s.JobID = m.lastID["job_skills"], jid
+		stored = append(stored, s)
+	}
+	m.skills[jid] = stored
+}
+
+// withSkills returns a copy of a stored job holding its skills, the copy shares nothing
+// with the store.
+func (m *MemoryRepo) withSkills(j models.Job) models.Job {
+	j.ExpiresAt = cloneTime(j.ExpiresAt)
+	j.Skills = nil
+	if skills := m.skills[j.ID]; len(skills) > 0 {
+		j.Skills = append([]models.JobSkill(nil), skills...)
+	}
+	return j
+}
+
+// activeJob finds a job that has not been deleted and whose company has not been deleted.
+func (m *MemoryRepo) activeJob(jid uint64) (models.Job, error) {
+	j, ok := m.jobs[uint(jid)]
+	if !ok || j.DeletedAt.Valid {
+		return models.Job{}, gorm.ErrRecordNotFound
+	}
+	if _, err := m.company(j.CompanyID); err != nil {
+		return models.Job{}, err
+	}
+	return j, nil
+}
+
+// activeJobs returns the jobs of active companies keep accepts, in the order of their ids.
+func (m *MemoryRepo) activeJobs(keep func(models.Job) bool) []models.Job {
+	return sorted(m.jobs, func(j models.Job) bool {
+		if j.DeletedAt.Valid {
+			return false
+		}
+		_, err := m.company(j.CompanyID)
+		return err == nil && keep(j)
+	})
+}
+
+func (m *MemoryRepo) CreateJob(ctx context.Context, job models.Job) (models.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, err := m.createJob(job)
+	if err != nil {
+		return models.Job{}, dbError(err, "job")
+	}
+	return job, nil
+}
+
+// UpdateJob applies changes, keyed by column name, to a job that has not been deleted.
+// When skills is not nil it replaces the skills of the job.
+func (m *MemoryRepo) UpdateJob(ctx context.Context, jid uint64, changes map[string]interface{}, skills []models.JobSkill) (models.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[uint(jid)]
+	if !ok || j.DeletedAt.Valid {
+		return models.Job{}, dbError(gorm.ErrRecordNotFound, "job")
+	}
+	err := checkSkills(skills)
+	if err != nil {
+		return models.Job{}, dbError(err, "job")
+	}
+	j.ExpiresAt = cloneTime(j.ExpiresAt)
+	err = setColumns(ctx, &j, changes)
+	if err != nil {
+		return models.Job{}, dbError(err, "job")
+	}
+	j.UpdatedAt = now()
+	m.jobs[j.ID] = j
+	if skills != nil {
+		m.setSkills(j.ID, skills)
+	}
+
+	j, err = m.activeJob(jid)
+	if err != nil {
+		return models.Job{}, dbError(err, "job")
+	}
+	return m.withSkills(j), nil
+}
+
+func (m *MemoryRepo) DeleteJob(ctx context.Context, jid uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[uint(jid)]
+	if !ok || j.DeletedAt.Valid {
+		return dbError(gorm.ErrRecordNotFound, "job")
+	}
+	softDelete(&j.Model, now())
+	m.jobs[j.ID] = j
+	return nil
+}
+
+// RestoreJob undoes the soft delete of a job. A job of a deleted company fails with
+// apperr.ErrConflict and stays deleted, the company has to be restored first.
+func (m *MemoryRepo) RestoreJob(ctx context.Context, jid uint64) (models.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[uint(jid)]
+	if !ok || !j.DeletedAt.Valid {
+		return models.Job{}, dbError(gorm.ErrRecordNotFound, "job")
+	}
+	if _, err := m.company(j.CompanyID); err != nil {
+		return models.Job{}, apperr.Wrap(apperr.ErrConflict, nil, fmt.Sprintf("company %d of the job is deleted, restore the company first", j.CompanyID))
+	}
+	j.DeletedAt = gorm.DeletedAt{}
+	m.jobs[j.ID] = j
+	j, err := m.activeJob(jid)
+	if err != nil {
+		return models.Job{}, dbError(err, "job")
+	}
+	return m.withSkills(j), nil
+}
+
+// FindAllJobs returns a page of the jobs of active companies matching q.
+func (m *MemoryRepo) FindAllJobs(ctx context.Context, q models.JobQuery, p models.PageRequest) ([]models.Job, models.PageInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	jobs := m.activeJobs(func(j models.Job) bool { return m.jobMatches(j, q, facetNone) })
+	return m.jobPage(jobs, p)
+}
+
+func (m *MemoryRepo) ViewJobByCompanyId(ctx context.Context, id uint, p models.PageRequest) ([]models.Job, models.PageInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	jobs := m.activeJobs(func(j models.Job) bool { return j.CompanyID == id })
+	return m.jobPage(jobs, p)
+}
+
+func (m *MemoryRepo) jobPage(jobs []models.Job, p models.PageRequest) ([]models.Job, models.PageInfo, error) {
+	jobs, info, err := pageSlice(jobs, p, jobColumns, jobCursor)
+	if err != nil {
+		return nil, models.PageInfo{}, dbError(err, "job")
+	}
+	for i := range jobs {
+		jobs[i] = m.withSkills(jobs[i])
+	}
+	return jobs, info, nil
+}
+
+func (m *MemoryRepo) ViewJobDetailsById(ctx context.Context, jid uint64) (models.Job, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	j, err := m.activeJob(jid)
+	if err != nil {
+		return models.Job{}, dbError(err, "job")
+	}
+	return m.withSkills(j), nil
+}
+
+// jobMatches tells whether j passes every filter of q except the one of the skip facet,
+// see jobFilter.
+func (m *MemoryRepo) jobMatches(j models.Job, q models.JobQuery, skip string) bool {
+	in := func(values []string, v string) bool {
+		for _, value := range values {
+			if value == v {
+				return true
+			}
+		}
+		return false
+	}
+	salary := j.SalaryMin
+	if j.SalaryMax > 0 {
+		salary = j.SalaryMax
+	}
+	switch {
+	case len(q.CompanyIDs) > 0 && skip != facetCompany && !inIDs(q.CompanyIDs, j.CompanyID),
+		q.City != "" && strings.ToLower(j.City) != strings.ToLower(q.City),
+		q.Country != "" && skip != facetCountry && j.Country != q.Country,
+		len(q.RemotePolicies) > 0 && skip != facetRemotePolicy && !in(q.RemotePolicies, j.RemotePolicy),
+		len(q.EmploymentTypes) > 0 && skip != facetEmploymentType && !in(q.EmploymentTypes, j.EmploymentType),
+		len(q.Seniorities) > 0 && skip != facetSeniority && !in(q.Seniorities, j.Seniority),
+		q.Status != "" && j.Status != q.Status,
+		q.SalaryCurrency != "" && j.SalaryCurrency != q.SalaryCurrency,
+		q.SalaryMin > 0 && salary < q.SalaryMin,
+		q.SalaryMax > 0 && (j.SalaryMin <= 0 || j.SalaryMin > q.SalaryMax),
+		q.PostedSince != nil && j.CreatedAt.Before(*q.PostedSince):
+		return false
+	}
+	if len(q.Skills) == 0 || skip == facetSkills {
+		return true
+	}
+	has := map[string]bool{}
+	for _, s := range m.skills[j.ID] {
+		has[strings.ToLower(s.Name)] = true
+	}
+	for _, s := range q.Skills {
+		if !has[strings.ToLower(s)] {
+			return false
+		}
+	}
+	return true
+}
+
+func inIDs(ids []uint, id uint) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MemoryRepo) JobFacets(ctx context.Context, q models.JobQuery) (models.JobFacets, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// count tallies the values of a facet over the jobs matching every filter but its own.
+	count := func(skip string, values func(j models.Job) []string) []models.FacetCount {
+		counts := map[string]int64{}
+		for _, j := range m.activeJobs(func(j models.Job) bool { return m.jobMatches(j, q, skip) }) {
+			for _, v := range values(j) {
+				if v != "" {
+					counts[v]++
+				}
+			}
+		}
+		facets := make([]models.FacetCount, 0, len(counts))
+		for v, n := range counts {
+			facets = append(facets, models.FacetCount{Value: v, Count: n})
+		}
+		sort.Slice(facets, func(i, k int) bool {
+			if facets[i].Count != facets[k].Count {
+				return facets[i].Count > facets[k].Count
+			}
+			return facets[i].Value < facets[k].Value
+		})
+		return facets
+	}
+	one := func(field func(j models.Job) string) func(j models.Job) []string {
+		return func(j models.Job) []string { return []string{field(j)} }
+	}
+
+	var facets models.JobFacets
+	facets.Countries = count(facetCountry, one(func(j models.Job) string { return j.Country }))
+	facets.RemotePolicies = count(facetRemotePolicy, one(func(j models.Job) string { return j.RemotePolicy }))
+	facets.EmploymentTypes = count(facetEmploymentType, one(func(j models.Job) string { return j.EmploymentType }))
+	facets.Seniorities = count(facetSeniority, one(func(j models.Job) string { return j.Seniority }))
+
+	facets.Companies = count(facetCompany, one(func(j models.Job) string {
+		return strconv.FormatUint(uint64(j.CompanyID), 10)
+	}))
+	for i, c := range facets.Companies {
+		id, _ := strconv.ParseUint(c.Value, 10, 64)
+		facets.Companies[i].Label = m.companies[uint(id)].CompanyName
+	}
+	sort.SliceStable(facets.Companies, func(i, k int) bool {
+		a, b := facets.Companies[i], facets.Companies[k]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Label < b.Label
+	})
+
+	facets.Skills = count(facetSkills, func(j models.Job) []string {
+		names := uniq(nil)
+		for _, s := range m.skills[j.ID] {
+			names[strings.ToLower(s.Name)] = true
+		}
+		list := make([]string, 0, len(names))
+		for name := range names {
+			list = append(list, name)
+		}
+		return list
+	})
+	if len(facets.Skills) > maxSkillFacets {
+		facets.Skills = facets.Skills[:maxSkillFacets]
+	}
+	return facets, nil
+}
+
+// SearchJobs returns a page of the open jobs of active companies matching q, see
+// parseTextQuery for how it differs from the search of Repo.
+func (m *MemoryRepo) SearchJobs(ctx context.Context, q string, p models.PageRequest) ([]models.JobSearchResult, models.PageInfo, error) {
+	query := parseTextQuery(q)
+	if len(query) == 0 {
+		return []models.JobSearchResult{}, models.PageInfo{}, nil
+	}
+	if p.Sort == "" {
+		p.Sort = models.SortRelevance
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	t := time.Now()
+	var hits []models.JobSearchResult
+	for _, j := range m.activeJobs(func(j models.Job) bool {
+		return j.Status == models.JobStatusOpen && (j.ExpiresAt == nil || j.ExpiresAt.After(t))
+	}) {
+		rank, ok := query.match(m.searchFields(j))
+		if ok {
+			hits = append(hits, models.JobSearchResult{Job: j, Rank: rank})
+		}
+	}
+	hits, info, err := pageSlice(hits, p, hitColumns, func(h models.JobSearchResult) models.Cursor {
+		c := jobCursor(h.Job)
+		if p.Sort == models.SortRelevance {
+			c.Value = h.Rank
+		}
+		return c
+	})
+	if err != nil {
+		return nil, models.PageInfo{}, dbError(err, "job")
+	}
+	results := make([]models.JobSearchResult, 0, len(hits))
+	for _, h := range hits {
+		h.Job = m.withSkills(h.Job)
+		h.TitleHighlight = query.highlight(h.Job.Title, 0)
+		h.Snippet = query.highlight(h.Job.Description, snippetWords)
+		results = append(results, h)
+	}
+	return results, info, nil
+}
+
+func (m *MemoryRepo) searchFields(j models.Job) []textField {
+	return jobFields(j, m.companies[j.CompanyID].CompanyName, m.skills[j.ID])
+}
+
+func cloneApplication(a models.Application) models.Application {
+	a.Job = nil
+	return a
+}
+
+func (m *MemoryRepo) application(id uint) (models.Application, error) {
+	a, ok := m.applications[id]
+	if !ok || a.DeletedAt.Valid {
+		return models.Application{}, gorm.ErrRecordNotFound
+	}
+	return a, nil
+}
+
+// withJob returns a copy of an application holding its job, unless the job was deleted.
+func (m *MemoryRepo) withJob(a models.Application) models.Application {
+	a = cloneApplication(a)
+	if j, ok := m.jobs[a.JobID]; ok && !j.DeletedAt.Valid {
+		j.ExpiresAt = cloneTime(j.ExpiresAt)
+		a.Job = &j
+	}
+	return a
+}
+
+func (m *MemoryRepo) CreateApplication(ctx context.Context, app models.Application) (models.Application, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.jobs[app.JobID]; !ok {
+		return models.Application{}, dbError(gorm.ErrForeignKeyViolated, "application")
+	}
+	for _, other := range m.applications {
+		if other.JobID == app.JobID && other.UserID == app.UserID {
+			return models.Application{}, dbError(gorm.ErrDuplicatedKey, "application")
+		}
+	}
+	err := assignID(m, "applications", m.applications, &app.ID)
+	if err != nil {
+		return models.Application{}, dbError(err, "application")
+	}
+	stamp(&app.Model)
+	if app.Status == "" {
+		app.Status = models.ApplicationSubmitted
+	}
+	m.applications[app.ID] = cloneApplication(app)
+	return app, nil
+}
+
+func (m *MemoryRepo) ViewApplication(ctx context.Context, id uint) (models.Application, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	a, err := m.application(id)
+	if err != nil {
+		return models.Application{}, dbError(err, "application")
+	}
+	return a, nil
+}
+
+// ViewApplicationByJobAndUser finds the application of a user to a job, whatever its status.
+func (m *MemoryRepo) ViewApplicationByJobAndUser(ctx context.Context, jid uint64, uid uint) (models.Application, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, a := range m.applications {
+		if a.JobID == uint(jid) && a.UserID == uid && !a.DeletedAt.Valid {
+			return a, nil
+		}
+	}
+	return models.Application{}, dbError(gorm.ErrRecordNotFound, "application")
+}
+
+// ListApplicationsByUser returns a page of the applications of a user together with the
+// jobs they were made to.
+func (m *MemoryRepo) ListApplicationsByUser(ctx context.Context, uid uint, p models.PageRequest) ([]models.Application, models.PageInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	apps := sorted(m.applications, func(a models.Application) bool { return a.UserID == uid && !a.DeletedAt.Valid })
+	apps, info, err := pageSlice(apps, p, applicationColumns, applicationCursor)
+	if err != nil {
+		return nil, models.PageInfo{}, dbError(err, "application")
+	}
+	for i := range apps {
+		apps[i] = m.withJob(apps[i])
+	}
+	return apps, info, nil
+}
+
+// ListApplicationsByJob returns a page of the applications made to a job.
+func (m *MemoryRepo) ListApplicationsByJob(ctx context.Context, jid uint64, p models.PageRequest) ([]models.Application, models.PageInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	apps := sorted(m.applications, func(a models.Application) bool { return a.JobID == uint(jid) && !a.DeletedAt.Valid })
+	apps, info, err := pageSlice(apps, p, applicationColumns, applicationCursor)
+	if err != nil {
+		return nil, models.PageInfo{}, dbError(err, "application")
+	}
+	return apps, info, nil
+}
+
+func (m *MemoryRepo) UpdateApplicationStatus(ctx context.Context, id uint, status string) (models.Application, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, err := m.application(id)
+	if err != nil {
+		return models.Application{}, dbError(err, "application")
+	}
+	a.Status, a.UpdatedAt = status, now()
+	m.applications[id] = a
+	return a, nil
+}
+
+// ListApplicationsByIDs returns the applications with the given ids that exist, together with their jobs.
+func (m *MemoryRepo) ListApplicationsByIDs(ctx context.Context, ids []uint) ([]models.Application, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	apps := sorted(m.applications, func(a models.Application) bool { return inIDs(ids, a.ID) && !a.DeletedAt.Valid })
+	for i := range apps {
+		apps[i] = m.withJob(apps[i])
+	}
+	return apps, nil
+}
+
+// MoveApplications applies every stage change and records it, or none of them. A change
+// whose application is no longer in its from stage fails with apperr.ErrConflict.
+func (m *MemoryRepo) MoveApplications(ctx context.Context, changes []models.ApplicationStageChange) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	moved := map[uint]models.Application{}
+	for _, ch := range changes {
+		a, ok := moved[ch.ApplicationID]
+		if !ok {
+			var err error
+			a, err = m.application(ch.ApplicationID)
+			if err != nil || a.Stage != ch.FromStage {
+				return apperr.Wrap(apperr.ErrConflict, nil, fmt.Sprintf("application %d changed stage", ch.ApplicationID))
+			}
+		} else if a.Stage != ch.FromStage {
+			return apperr.Wrap(apperr.ErrConflict, nil, fmt.Sprintf("application %d changed stage", ch.ApplicationID))
+		}
+		a.Stage, a.Status = ch.ToStage, ch.Status
+		moved[a.ID] = a
+	}
+
+	t := now()
+	for id, a := range moved {
+		a.UpdatedAt = t
+		m.applications[id] = a
+	}
+	for _, ch := range changes {
+		_ = assignID(m, "application_stage_changes", m.changes, &ch.ID)
+		if ch.CreatedAt.IsZero() {
+			ch.CreatedAt = t
+		}
+		m.changes[ch.ID] = ch
+	}
+	return nil
+}
+
+// ListStageChanges returns the history of an application, oldest first.
+func (m *MemoryRepo) ListStageChanges(ctx context.Context, aid uint) ([]models.ApplicationStageChange, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	changes := sorted(m.changes, func(ch models.ApplicationStageChange) bool { return ch.ApplicationID == aid })
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].CreatedAt.Before(changes[j].CreatedAt) })
+	return changes, nil
+}
+
+func cloneStage(s models.PipelineStage) models.PipelineStage {
+	if s.Next != nil {
+		s.Next = append([]string{}, s.Next...)
+	}
+	return s
+}
+
+// ViewPipeline returns the stages of a company in order, none when it uses the default pipeline.
+func (m *MemoryRepo) ViewPipeline(ctx context.Context, cid uint) ([]models.PipelineStage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	stages := sorted(m.stages, func(s models.PipelineStage) bool { return s.CompanyID == cid && !s.DeletedAt.Valid })
+	sort.SliceStable(stages, func(i, j int) bool { return stages[i].Position < stages[j].Position })
+	for i := range stages {
+		stages[i] = cloneStage(stages[i])
+	}
+	return stages, nil
+}
+
+func (m *MemoryRepo) ReplacePipeline(ctx context.Context, cid uint, stages []models.PipelineStage) ([]models.PipelineStage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := map[string]bool{}
+	for _, s := range stages {
+		if names[s.Name] {
+			return nil, dbError(gorm.ErrDuplicatedKey, "pipeline")
+		}
+		names[s.Name] = true
+	}
+	// The old stages are removed for good so their names can be used again.
+	for id, s := range m.stages {
+		if s.CompanyID == cid {
+			delete(m.stages, id)
+		}
+	}
+	for i := range stages {
+		stages[i].CompanyID = cid
+		_ = assignID(m, "pipeline_stages", m.stages, &stages[i].ID)
+		stamp(&stages[i].Model)
+		m.stages[stages[i].ID] = cloneStage(stages[i])
+	}
+	return stages, nil
+}
+
+// CountOpenApplicationsOutside counts the applications to the jobs of a company that are
+// still being considered and sit in a stage that is not in stages.
+func (m *MemoryRepo) CountOpenApplicationsOutside(ctx context.Context, cid uint, stages []string) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	in := uniq(stages)
+	var n int64
+	for _, a := range m.applications {
+		j, ok := m.jobs[a.JobID]
+		if a.DeletedAt.Valid || !ok || j.CompanyID != cid {
+			continue
+		}
+		open := a.Status == models.ApplicationSubmitted || a.Status == models.ApplicationInReview
+		if open && a.Stage != "" && !in[a.Stage] {
+			n++
+		}
+	}
+	return n, nil
+}
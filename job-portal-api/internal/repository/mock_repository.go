@@ -10,157 +10,1118 @@ package repository
 
 import (
 	context "context"
+	auth "job-portal-api/internal/auth"
 	models "job-portal-api/internal/models"
 	reflect "reflect"
+	time "time"
 
-	jwt "github.com/golang-jwt/jwt/v5"
 	gomock "go.uber.org/mock/gomock"
 )
 
-// MockUserRepo is a mock of UserRepo interface.
-type MockUserRepo struct {
+// MockUserStore is a mock of UserStore interface.
+type MockUserStore struct {
 	ctrl     *gomock.Controller
-	recorder *MockUserRepoMockRecorder
+	recorder *MockUserStoreMockRecorder
 }
 
-// MockUserRepoMockRecorder is the mock recorder for MockUserRepo.
-type MockUserRepoMockRecorder struct {
-	mock *MockUserRepo
+// MockUserStoreMockRecorder is the mock recorder for MockUserStore.
+type MockUserStoreMockRecorder struct {
+	mock *MockUserStore
 }
 
-// NewMockUserRepo creates a new mock instance.
-func NewMockUserRepo(ctrl *gomock.Controller) *MockUserRepo {
-	mock := &MockUserRepo{ctrl: ctrl}
-	mock.recorder = &MockUserRepoMockRecorder{mock}
+// NewMockUserStore creates a new mock instance.
+func NewMockUserStore(ctrl *gomock.Controller) *MockUserStore {
+	mock := &MockUserStore{ctrl: ctrl}
+	mock.recorder = &MockUserStoreMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockUserRepo) EXPECT() *MockUserRepoMockRecorder {
+func (m *MockUserStore) EXPECT() *MockUserStoreMockRecorder {
 	return m.recorder
 }
 
-// AutoMigrate mocks base method.
-func (m *MockUserRepo) AutoMigrate() error {
+// CheckEmail mocks base method.
+func (m *MockUserStore) CheckEmail(ctx context.Context, email, password string) (auth.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckEmail", ctx, email, password)
+	ret0, _ := ret[0].(auth.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckEmail indicates an expected call of CheckEmail.
+func (mr *MockUserStoreMockRecorder) CheckEmail(ctx, email, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckEmail", reflect.TypeOf((*MockUserStore)(nil).CheckEmail), ctx, email, password)
+}
+
+// CreateRefreshToken mocks base method.
+func (m *MockUserStore) CreateRefreshToken(ctx context.Context, token models.RefreshToken) (models.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefreshToken", ctx, token)
+	ret0, _ := ret[0].(models.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRefreshToken indicates an expected call of CreateRefreshToken.
+func (mr *MockUserStoreMockRecorder) CreateRefreshToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefreshToken", reflect.TypeOf((*MockUserStore)(nil).CreateRefreshToken), ctx, token)
+}
+
+// CreateUser mocks base method.
+func (m *MockUserStore) CreateUser(ctx context.Context, userData models.User) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, userData)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockUserStoreMockRecorder) CreateUser(ctx, userData any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserStore)(nil).CreateUser), ctx, userData)
+}
+
+// IsAccessTokenRevoked mocks base method.
+func (m *MockUserStore) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAccessTokenRevoked", ctx, jti)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsAccessTokenRevoked indicates an expected call of IsAccessTokenRevoked.
+func (mr *MockUserStoreMockRecorder) IsAccessTokenRevoked(ctx, jti any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAccessTokenRevoked", reflect.TypeOf((*MockUserStore)(nil).IsAccessTokenRevoked), ctx, jti)
+}
+
+// RevokeAccessToken mocks base method.
+func (m *MockUserStore) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAccessToken", ctx, jti, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAccessToken indicates an expected call of RevokeAccessToken.
+func (mr *MockUserStoreMockRecorder) RevokeAccessToken(ctx, jti, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAccessToken", reflect.TypeOf((*MockUserStore)(nil).RevokeAccessToken), ctx, jti, expiresAt)
+}
+
+// RevokeRefreshToken mocks base method.
+func (m *MockUserStore) RevokeRefreshToken(ctx context.Context, id uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshToken", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeRefreshToken indicates an expected call of RevokeRefreshToken.
+func (mr *MockUserStoreMockRecorder) RevokeRefreshToken(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshToken", reflect.TypeOf((*MockUserStore)(nil).RevokeRefreshToken), ctx, id)
+}
+
+// RevokeTokenFamily mocks base method.
+func (m *MockUserStore) RevokeTokenFamily(ctx context.Context, familyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeTokenFamily", ctx, familyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeTokenFamily indicates an expected call of RevokeTokenFamily.
+func (mr *MockUserStoreMockRecorder) RevokeTokenFamily(ctx, familyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeTokenFamily", reflect.TypeOf((*MockUserStore)(nil).RevokeTokenFamily), ctx, familyID)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockUserStore) UpdatePassword(ctx context.Context, uid uint, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, uid, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockUserStoreMockRecorder) UpdatePassword(ctx, uid, passwordHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserStore)(nil).UpdatePassword), ctx, uid, passwordHash)
+}
+
+// UpdateUserRole mocks base method.
+func (m *MockUserStore) UpdateUserRole(ctx context.Context, uid uint, role string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", ctx, uid, role)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockUserStoreMockRecorder) UpdateUserRole(ctx, uid, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockUserStore)(nil).UpdateUserRole), ctx, uid, role)
+}
+
+// ViewRefreshToken mocks base method.
+func (m *MockUserStore) ViewRefreshToken(ctx context.Context, tokenHash string) (models.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewRefreshToken", ctx, tokenHash)
+	ret0, _ := ret[0].(models.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewRefreshToken indicates an expected call of ViewRefreshToken.
+func (mr *MockUserStoreMockRecorder) ViewRefreshToken(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewRefreshToken", reflect.TypeOf((*MockUserStore)(nil).ViewRefreshToken), ctx, tokenHash)
+}
+
+// ViewUserByEmail mocks base method.
+func (m *MockUserStore) ViewUserByEmail(ctx context.Context, email string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewUserByEmail", ctx, email)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewUserByEmail indicates an expected call of ViewUserByEmail.
+func (mr *MockUserStoreMockRecorder) ViewUserByEmail(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewUserByEmail", reflect.TypeOf((*MockUserStore)(nil).ViewUserByEmail), ctx, email)
+}
+
+// ViewUserById mocks base method.
+func (m *MockUserStore) ViewUserById(ctx context.Context, uid uint) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewUserById", ctx, uid)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewUserById indicates an expected call of ViewUserById.
+func (mr *MockUserStoreMockRecorder) ViewUserById(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewUserById", reflect.TypeOf((*MockUserStore)(nil).ViewUserById), ctx, uid)
+}
+
+// ViewUserClaims mocks base method.
+func (m *MockUserStore) ViewUserClaims(ctx context.Context, uid uint) (auth.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewUserClaims", ctx, uid)
+	ret0, _ := ret[0].(auth.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewUserClaims indicates an expected call of ViewUserClaims.
+func (mr *MockUserStoreMockRecorder) ViewUserClaims(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewUserClaims", reflect.TypeOf((*MockUserStore)(nil).ViewUserClaims), ctx, uid)
+}
+
+// MockCompanyStore is a mock of CompanyStore interface.
+type MockCompanyStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockCompanyStoreMockRecorder
+}
+
+// MockCompanyStoreMockRecorder is the mock recorder for MockCompanyStore.
+type MockCompanyStoreMockRecorder struct {
+	mock *MockCompanyStore
+}
+
+// NewMockCompanyStore creates a new mock instance.
+func NewMockCompanyStore(ctrl *gomock.Controller) *MockCompanyStore {
+	mock := &MockCompanyStore{ctrl: ctrl}
+	mock.recorder = &MockCompanyStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCompanyStore) EXPECT() *MockCompanyStoreMockRecorder {
+	return m.recorder
+}
+
+// AddCompanyMember mocks base method.
+func (m *MockCompanyStore) AddCompanyMember(ctx context.Context, member models.CompanyMember) (models.CompanyMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCompanyMember", ctx, member)
+	ret0, _ := ret[0].(models.CompanyMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCompanyMember indicates an expected call of AddCompanyMember.
+func (mr *MockCompanyStoreMockRecorder) AddCompanyMember(ctx, member any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCompanyMember", reflect.TypeOf((*MockCompanyStore)(nil).AddCompanyMember), ctx, member)
+}
+
+// CreateCompany mocks base method.
+func (m *MockCompanyStore) CreateCompany(ctx context.Context, companyData models.Companies) (models.Companies, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCompany", ctx, companyData)
+	ret0, _ := ret[0].(models.Companies)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCompany indicates an expected call of CreateCompany.
+func (mr *MockCompanyStoreMockRecorder) CreateCompany(ctx, companyData any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCompany", reflect.TypeOf((*MockCompanyStore)(nil).CreateCompany), ctx, companyData)
+}
+
+// DeleteCompany mocks base method.
+func (m *MockCompanyStore) DeleteCompany(ctx context.Context, cid uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCompany", ctx, cid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCompany indicates an expected call of DeleteCompany.
+func (mr *MockCompanyStoreMockRecorder) DeleteCompany(ctx, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCompany", reflect.TypeOf((*MockCompanyStore)(nil).DeleteCompany), ctx, cid)
+}
+
+// RestoreCompany mocks base method.
+func (m *MockCompanyStore) RestoreCompany(ctx context.Context, cid uint, restoreJobs bool) (models.Companies, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreCompany", ctx, cid, restoreJobs)
+	ret0, _ := ret[0].(models.Companies)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreCompany indicates an expected call of RestoreCompany.
+func (mr *MockCompanyStoreMockRecorder) RestoreCompany(ctx, cid, restoreJobs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreCompany", reflect.TypeOf((*MockCompanyStore)(nil).RestoreCompany), ctx, cid, restoreJobs)
+}
+
+// TransferCompanyOwnership mocks base method.
+func (m *MockCompanyStore) TransferCompanyOwnership(ctx context.Context, cid, uid uint) (models.Companies, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferCompanyOwnership", ctx, cid, uid)
+	ret0, _ := ret[0].(models.Companies)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferCompanyOwnership indicates an expected call of TransferCompanyOwnership.
+func (mr *MockCompanyStoreMockRecorder) TransferCompanyOwnership(ctx, cid, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferCompanyOwnership", reflect.TypeOf((*MockCompanyStore)(nil).TransferCompanyOwnership), ctx, cid, uid)
+}
+
+// UpdateCompany mocks base method.
+func (m *MockCompanyStore) UpdateCompany(ctx context.Context, cid uint, changes map[string]any) (models.Companies, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCompany", ctx, cid, changes)
+	ret0, _ := ret[0].(models.Companies)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCompany indicates an expected call of UpdateCompany.
+func (mr *MockCompanyStoreMockRecorder) UpdateCompany(ctx, cid, changes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCompany", reflect.TypeOf((*MockCompanyStore)(nil).UpdateCompany), ctx, cid, changes)
+}
+
+// ViewCompanies mocks base method.
+func (m *MockCompanyStore) ViewCompanies(ctx context.Context, p models.PageRequest) ([]models.Companies, models.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewCompanies", ctx, p)
+	ret0, _ := ret[0].([]models.Companies)
+	ret1, _ := ret[1].(models.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ViewCompanies indicates an expected call of ViewCompanies.
+func (mr *MockCompanyStoreMockRecorder) ViewCompanies(ctx, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCompanies", reflect.TypeOf((*MockCompanyStore)(nil).ViewCompanies), ctx, p)
+}
+
+// ViewCompanyById mocks base method.
+func (m *MockCompanyStore) ViewCompanyById(ctx context.Context, cid uint) ([]models.Companies, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewCompanyById", ctx, cid)
+	ret0, _ := ret[0].([]models.Companies)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewCompanyById indicates an expected call of ViewCompanyById.
+func (mr *MockCompanyStoreMockRecorder) ViewCompanyById(ctx, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCompanyById", reflect.TypeOf((*MockCompanyStore)(nil).ViewCompanyById), ctx, cid)
+}
+
+// ViewCompanyMember mocks base method.
+func (m *MockCompanyStore) ViewCompanyMember(ctx context.Context, cid, uid uint) (models.CompanyMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewCompanyMember", ctx, cid, uid)
+	ret0, _ := ret[0].(models.CompanyMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewCompanyMember indicates an expected call of ViewCompanyMember.
+func (mr *MockCompanyStoreMockRecorder) ViewCompanyMember(ctx, cid, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCompanyMember", reflect.TypeOf((*MockCompanyStore)(nil).ViewCompanyMember), ctx, cid, uid)
+}
+
+// MockJobStore is a mock of JobStore interface.
+type MockJobStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobStoreMockRecorder
+}
+
+// MockJobStoreMockRecorder is the mock recorder for MockJobStore.
+type MockJobStoreMockRecorder struct {
+	mock *MockJobStore
+}
+
+// NewMockJobStore creates a new mock instance.
+func NewMockJobStore(ctrl *gomock.Controller) *MockJobStore {
+	mock := &MockJobStore{ctrl: ctrl}
+	mock.recorder = &MockJobStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobStore) EXPECT() *MockJobStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateJob mocks base method.
+func (m *MockJobStore) CreateJob(ctx context.Context, jobData models.Job) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJob", ctx, jobData)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateJob indicates an expected call of CreateJob.
+func (mr *MockJobStoreMockRecorder) CreateJob(ctx, jobData any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJob", reflect.TypeOf((*MockJobStore)(nil).CreateJob), ctx, jobData)
+}
+
+// DeleteJob mocks base method.
+func (m *MockJobStore) DeleteJob(ctx context.Context, jid uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteJob", ctx, jid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteJob indicates an expected call of DeleteJob.
+func (mr *MockJobStoreMockRecorder) DeleteJob(ctx, jid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteJob", reflect.TypeOf((*MockJobStore)(nil).DeleteJob), ctx, jid)
+}
+
+// FindAllJobs mocks base method.
+func (m *MockJobStore) FindAllJobs(ctx context.Context, q models.JobQuery, p models.PageRequest) ([]models.Job, models.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllJobs", ctx, q, p)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(models.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindAllJobs indicates an expected call of FindAllJobs.
+func (mr *MockJobStoreMockRecorder) FindAllJobs(ctx, q, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllJobs", reflect.TypeOf((*MockJobStore)(nil).FindAllJobs), ctx, q, p)
+}
+
+// JobFacets mocks base method.
+func (m *MockJobStore) JobFacets(ctx context.Context, q models.JobQuery) (models.JobFacets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JobFacets", ctx, q)
+	ret0, _ := ret[0].(models.JobFacets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JobFacets indicates an expected call of JobFacets.
+func (mr *MockJobStoreMockRecorder) JobFacets(ctx, q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JobFacets", reflect.TypeOf((*MockJobStore)(nil).JobFacets), ctx, q)
+}
+
+// RestoreJob mocks base method.
+func (m *MockJobStore) RestoreJob(ctx context.Context, jid uint64) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreJob", ctx, jid)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreJob indicates an expected call of RestoreJob.
+func (mr *MockJobStoreMockRecorder) RestoreJob(ctx, jid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreJob", reflect.TypeOf((*MockJobStore)(nil).RestoreJob), ctx, jid)
+}
+
+// SearchJobs mocks base method.
+func (m *MockJobStore) SearchJobs(ctx context.Context, q string, p models.PageRequest) ([]models.JobSearchResult, models.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchJobs", ctx, q, p)
+	ret0, _ := ret[0].([]models.JobSearchResult)
+	ret1, _ := ret[1].(models.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchJobs indicates an expected call of SearchJobs.
+func (mr *MockJobStoreMockRecorder) SearchJobs(ctx, q, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchJobs", reflect.TypeOf((*MockJobStore)(nil).SearchJobs), ctx, q, p)
+}
+
+// UpdateJob mocks base method.
+func (m *MockJobStore) UpdateJob(ctx context.Context, jid uint64, changes map[string]any, skills []models.JobSkill) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJob", ctx, jid, changes, skills)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateJob indicates an expected call of UpdateJob.
+func (mr *MockJobStoreMockRecorder) UpdateJob(ctx, jid, changes, skills any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJob", reflect.TypeOf((*MockJobStore)(nil).UpdateJob), ctx, jid, changes, skills)
+}
+
+// ViewJobByCompanyId mocks base method.
+func (m *MockJobStore) ViewJobByCompanyId(ctx context.Context, id uint, p models.PageRequest) ([]models.Job, models.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewJobByCompanyId", ctx, id, p)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(models.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ViewJobByCompanyId indicates an expected call of ViewJobByCompanyId.
+func (mr *MockJobStoreMockRecorder) ViewJobByCompanyId(ctx, id, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewJobByCompanyId", reflect.TypeOf((*MockJobStore)(nil).ViewJobByCompanyId), ctx, id, p)
+}
+
+// ViewJobDetailsById mocks base method.
+func (m *MockJobStore) ViewJobDetailsById(ctx context.Context, jid uint64) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewJobDetailsById", ctx, jid)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewJobDetailsById indicates an expected call of ViewJobDetailsById.
+func (mr *MockJobStoreMockRecorder) ViewJobDetailsById(ctx, jid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewJobDetailsById", reflect.TypeOf((*MockJobStore)(nil).ViewJobDetailsById), ctx, jid)
+}
+
+// MockUnitOfWork is a mock of UnitOfWork interface.
+type MockUnitOfWork struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnitOfWorkMockRecorder
+}
+
+// MockUnitOfWorkMockRecorder is the mock recorder for MockUnitOfWork.
+type MockUnitOfWorkMockRecorder struct {
+	mock *MockUnitOfWork
+}
+
+// NewMockUnitOfWork creates a new mock instance.
+func NewMockUnitOfWork(ctrl *gomock.Controller) *MockUnitOfWork {
+	mock := &MockUnitOfWork{ctrl: ctrl}
+	mock.recorder = &MockUnitOfWorkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnitOfWork) EXPECT() *MockUnitOfWorkMockRecorder {
+	return m.recorder
+}
+
+// WithTx mocks base method.
+func (m *MockUnitOfWork) WithTx(ctx context.Context, fn func(Repos) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockUnitOfWorkMockRecorder) WithTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockUnitOfWork)(nil).WithTx), ctx, fn)
+}
+
+// MockRepos is a mock of Repos interface.
+type MockRepos struct {
+	ctrl     *gomock.Controller
+	recorder *MockReposMockRecorder
+}
+
+// MockReposMockRecorder is the mock recorder for MockRepos.
+type MockReposMockRecorder struct {
+	mock *MockRepos
+}
+
+// NewMockRepos creates a new mock instance.
+func NewMockRepos(ctrl *gomock.Controller) *MockRepos {
+	mock := &MockRepos{ctrl: ctrl}
+	mock.recorder = &MockReposMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepos) EXPECT() *MockReposMockRecorder {
+	return m.recorder
+}
+
+// AddCompanyMember mocks base method.
+func (m *MockRepos) AddCompanyMember(ctx context.Context, member models.CompanyMember) (models.CompanyMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCompanyMember", ctx, member)
+	ret0, _ := ret[0].(models.CompanyMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCompanyMember indicates an expected call of AddCompanyMember.
+func (mr *MockReposMockRecorder) AddCompanyMember(ctx, member any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCompanyMember", reflect.TypeOf((*MockRepos)(nil).AddCompanyMember), ctx, member)
+}
+
+// CheckEmail mocks base method.
+func (m *MockRepos) CheckEmail(ctx context.Context, email, password string) (auth.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckEmail", ctx, email, password)
+	ret0, _ := ret[0].(auth.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckEmail indicates an expected call of CheckEmail.
+func (mr *MockReposMockRecorder) CheckEmail(ctx, email, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckEmail", reflect.TypeOf((*MockRepos)(nil).CheckEmail), ctx, email, password)
+}
+
+// CountOpenApplicationsOutside mocks base method.
+func (m *MockRepos) CountOpenApplicationsOutside(ctx context.Context, cid uint, stages []string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOpenApplicationsOutside", ctx, cid, stages)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOpenApplicationsOutside indicates an expected call of CountOpenApplicationsOutside.
+func (mr *MockReposMockRecorder) CountOpenApplicationsOutside(ctx, cid, stages any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOpenApplicationsOutside", reflect.TypeOf((*MockRepos)(nil).CountOpenApplicationsOutside), ctx, cid, stages)
+}
+
+// CreateApplication mocks base method.
+func (m *MockRepos) CreateApplication(ctx context.Context, app models.Application) (models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateApplication", ctx, app)
+	ret0, _ := ret[0].(models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateApplication indicates an expected call of CreateApplication.
+func (mr *MockReposMockRecorder) CreateApplication(ctx, app any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApplication", reflect.TypeOf((*MockRepos)(nil).CreateApplication), ctx, app)
+}
+
+// CreateCompany mocks base method.
+func (m *MockRepos) CreateCompany(ctx context.Context, companyData models.Companies) (models.Companies, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCompany", ctx, companyData)
+	ret0, _ := ret[0].(models.Companies)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCompany indicates an expected call of CreateCompany.
+func (mr *MockReposMockRecorder) CreateCompany(ctx, companyData any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCompany", reflect.TypeOf((*MockRepos)(nil).CreateCompany), ctx, companyData)
+}
+
+// CreateJob mocks base method.
+func (m *MockRepos) CreateJob(ctx context.Context, jobData models.Job) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJob", ctx, jobData)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateJob indicates an expected call of CreateJob.
+func (mr *MockReposMockRecorder) CreateJob(ctx, jobData any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJob", reflect.TypeOf((*MockRepos)(nil).CreateJob), ctx, jobData)
+}
+
+// CreateRefreshToken mocks base method.
+func (m *MockRepos) CreateRefreshToken(ctx context.Context, token models.RefreshToken) (models.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefreshToken", ctx, token)
+	ret0, _ := ret[0].(models.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRefreshToken indicates an expected call of CreateRefreshToken.
+func (mr *MockReposMockRecorder) CreateRefreshToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefreshToken", reflect.TypeOf((*MockRepos)(nil).CreateRefreshToken), ctx, token)
+}
+
+// CreateUser mocks base method.
+func (m *MockRepos) CreateUser(ctx context.Context, userData models.User) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, userData)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockReposMockRecorder) CreateUser(ctx, userData any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockRepos)(nil).CreateUser), ctx, userData)
+}
+
+// DeleteCompany mocks base method.
+func (m *MockRepos) DeleteCompany(ctx context.Context, cid uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCompany", ctx, cid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCompany indicates an expected call of DeleteCompany.
+func (mr *MockReposMockRecorder) DeleteCompany(ctx, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCompany", reflect.TypeOf((*MockRepos)(nil).DeleteCompany), ctx, cid)
+}
+
+// DeleteJob mocks base method.
+func (m *MockRepos) DeleteJob(ctx context.Context, jid uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteJob", ctx, jid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteJob indicates an expected call of DeleteJob.
+func (mr *MockReposMockRecorder) DeleteJob(ctx, jid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteJob", reflect.TypeOf((*MockRepos)(nil).DeleteJob), ctx, jid)
+}
+
+// FindAllJobs mocks base method.
+func (m *MockRepos) FindAllJobs(ctx context.Context, q models.JobQuery, p models.PageRequest) ([]models.Job, models.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllJobs", ctx, q, p)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(models.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindAllJobs indicates an expected call of FindAllJobs.
+func (mr *MockReposMockRecorder) FindAllJobs(ctx, q, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllJobs", reflect.TypeOf((*MockRepos)(nil).FindAllJobs), ctx, q, p)
+}
+
+// IsAccessTokenRevoked mocks base method.
+func (m *MockRepos) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAccessTokenRevoked", ctx, jti)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsAccessTokenRevoked indicates an expected call of IsAccessTokenRevoked.
+func (mr *MockReposMockRecorder) IsAccessTokenRevoked(ctx, jti any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAccessTokenRevoked", reflect.TypeOf((*MockRepos)(nil).IsAccessTokenRevoked), ctx, jti)
+}
+
+// JobFacets mocks base method.
+func (m *MockRepos) JobFacets(ctx context.Context, q models.JobQuery) (models.JobFacets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JobFacets", ctx, q)
+	ret0, _ := ret[0].(models.JobFacets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JobFacets indicates an expected call of JobFacets.
+func (mr *MockReposMockRecorder) JobFacets(ctx, q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JobFacets", reflect.TypeOf((*MockRepos)(nil).JobFacets), ctx, q)
+}
+
+// ListApplicationsByIDs mocks base method.
+func (m *MockRepos) ListApplicationsByIDs(ctx context.Context, ids []uint) ([]models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApplicationsByIDs", ctx, ids)
+	ret0, _ := ret[0].([]models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApplicationsByIDs indicates an expected call of ListApplicationsByIDs.
+func (mr *MockReposMockRecorder) ListApplicationsByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApplicationsByIDs", reflect.TypeOf((*MockRepos)(nil).ListApplicationsByIDs), ctx, ids)
+}
+
+// ListApplicationsByJob mocks base method.
+func (m *MockRepos) ListApplicationsByJob(ctx context.Context, jid uint64, p models.PageRequest) ([]models.Application, models.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApplicationsByJob", ctx, jid, p)
+	ret0, _ := ret[0].([]models.Application)
+	ret1, _ := ret[1].(models.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListApplicationsByJob indicates an expected call of ListApplicationsByJob.
+func (mr *MockReposMockRecorder) ListApplicationsByJob(ctx, jid, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApplicationsByJob", reflect.TypeOf((*MockRepos)(nil).ListApplicationsByJob), ctx, jid, p)
+}
+
+// ListApplicationsByUser mocks base method.
+func (m *MockRepos) ListApplicationsByUser(ctx context.Context, uid uint, p models.PageRequest) ([]models.Application, models.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApplicationsByUser", ctx, uid, p)
+	ret0, _ := ret[0].([]models.Application)
+	ret1, _ := ret[1].(models.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListApplicationsByUser indicates an expected call of ListApplicationsByUser.
+func (mr *MockReposMockRecorder) ListApplicationsByUser(ctx, uid, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApplicationsByUser", reflect.TypeOf((*MockRepos)(nil).ListApplicationsByUser), ctx, uid, p)
+}
+
+// ListStageChanges mocks base method.
+func (m *MockRepos) ListStageChanges(ctx context.Context, aid uint) ([]models.ApplicationStageChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStageChanges", ctx, aid)
+	ret0, _ := ret[0].([]models.ApplicationStageChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStageChanges indicates an expected call of ListStageChanges.
+func (mr *MockReposMockRecorder) ListStageChanges(ctx, aid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStageChanges", reflect.TypeOf((*MockRepos)(nil).ListStageChanges), ctx, aid)
+}
+
+// MoveApplications mocks base method.
+func (m *MockRepos) MoveApplications(ctx context.Context, changes []models.ApplicationStageChange) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AutoMigrate")
+	ret := m.ctrl.Call(m, "MoveApplications", ctx, changes)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// AutoMigrate indicates an expected call of AutoMigrate.
-func (mr *MockUserRepoMockRecorder) AutoMigrate() *gomock.Call {
+// MoveApplications indicates an expected call of MoveApplications.
+func (mr *MockReposMockRecorder) MoveApplications(ctx, changes any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AutoMigrate", reflect.TypeOf((*MockUserRepo)(nil).AutoMigrate))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveApplications", reflect.TypeOf((*MockRepos)(nil).MoveApplications), ctx, changes)
 }
 
-// CheckEmail mocks base method.
-func (m *MockUserRepo) CheckEmail(ctx context.Context, email, password string) (jwt.RegisteredClaims, error) {
+// ReplacePipeline mocks base method.
+func (m *MockRepos) ReplacePipeline(ctx context.Context, cid uint, stages []models.PipelineStage) ([]models.PipelineStage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckEmail", ctx, email, password)
-	ret0, _ := ret[0].(jwt.RegisteredClaims)
+	ret := m.ctrl.Call(m, "ReplacePipeline", ctx, cid, stages)
+	ret0, _ := ret[0].([]models.PipelineStage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CheckEmail indicates an expected call of CheckEmail.
-func (mr *MockUserRepoMockRecorder) CheckEmail(ctx, email, password any) *gomock.Call {
+// ReplacePipeline indicates an expected call of ReplacePipeline.
+func (mr *MockReposMockRecorder) ReplacePipeline(ctx, cid, stages any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckEmail", reflect.TypeOf((*MockUserRepo)(nil).CheckEmail), ctx, email, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplacePipeline", reflect.TypeOf((*MockRepos)(nil).ReplacePipeline), ctx, cid, stages)
 }
 
-// CreateCompany mocks base method.
-func (m *MockUserRepo) CreateCompany(ctx context.Context, companyData models.Companies) (models.Companies, error) {
+// RestoreCompany mocks base method.
+func (m *MockRepos) RestoreCompany(ctx context.Context, cid uint, restoreJobs bool) (models.Companies, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateCompany", ctx, companyData)
+	ret := m.ctrl.Call(m, "RestoreCompany", ctx, cid, restoreJobs)
 	ret0, _ := ret[0].(models.Companies)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateCompany indicates an expected call of CreateCompany.
-func (mr *MockUserRepoMockRecorder) CreateCompany(ctx, companyData any) *gomock.Call {
+// RestoreCompany indicates an expected call of RestoreCompany.
+func (mr *MockReposMockRecorder) RestoreCompany(ctx, cid, restoreJobs any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCompany", reflect.TypeOf((*MockUserRepo)(nil).CreateCompany), ctx, companyData)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreCompany", reflect.TypeOf((*MockRepos)(nil).RestoreCompany), ctx, cid, restoreJobs)
 }
 
-// CreateJob mocks base method.
-func (m *MockUserRepo) CreateJob(ctx context.Context, jobData models.Job) (models.Job, error) {
+// RestoreJob mocks base method.
+func (m *MockRepos) RestoreJob(ctx context.Context, jid uint64) (models.Job, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateJob", ctx, jobData)
+	ret := m.ctrl.Call(m, "RestoreJob", ctx, jid)
 	ret0, _ := ret[0].(models.Job)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateJob indicates an expected call of CreateJob.
-func (mr *MockUserRepoMockRecorder) CreateJob(ctx, jobData any) *gomock.Call {
+// RestoreJob indicates an expected call of RestoreJob.
+func (mr *MockReposMockRecorder) RestoreJob(ctx, jid any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJob", reflect.TypeOf((*MockUserRepo)(nil).CreateJob), ctx, jobData)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreJob", reflect.TypeOf((*MockRepos)(nil).RestoreJob), ctx, jid)
 }
 
-// CreateUser mocks base method.
-func (m *MockUserRepo) CreateUser(ctx context.Context, userData models.User) (models.User, error) {
+// RevokeAccessToken mocks base method.
+func (m *MockRepos) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", ctx, userData)
+	ret := m.ctrl.Call(m, "RevokeAccessToken", ctx, jti, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAccessToken indicates an expected call of RevokeAccessToken.
+func (mr *MockReposMockRecorder) RevokeAccessToken(ctx, jti, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAccessToken", reflect.TypeOf((*MockRepos)(nil).RevokeAccessToken), ctx, jti, expiresAt)
+}
+
+// RevokeRefreshToken mocks base method.
+func (m *MockRepos) RevokeRefreshToken(ctx context.Context, id uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshToken", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeRefreshToken indicates an expected call of RevokeRefreshToken.
+func (mr *MockReposMockRecorder) RevokeRefreshToken(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshToken", reflect.TypeOf((*MockRepos)(nil).RevokeRefreshToken), ctx, id)
+}
+
+// RevokeTokenFamily mocks base method.
+func (m *MockRepos) RevokeTokenFamily(ctx context.Context, familyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeTokenFamily", ctx, familyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeTokenFamily indicates an expected call of RevokeTokenFamily.
+func (mr *MockReposMockRecorder) RevokeTokenFamily(ctx, familyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeTokenFamily", reflect.TypeOf((*MockRepos)(nil).RevokeTokenFamily), ctx, familyID)
+}
+
+// SearchJobs mocks base method.
+func (m *MockRepos) SearchJobs(ctx context.Context, q string, p models.PageRequest) ([]models.JobSearchResult, models.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchJobs", ctx, q, p)
+	ret0, _ := ret[0].([]models.JobSearchResult)
+	ret1, _ := ret[1].(models.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchJobs indicates an expected call of SearchJobs.
+func (mr *MockReposMockRecorder) SearchJobs(ctx, q, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchJobs", reflect.TypeOf((*MockRepos)(nil).SearchJobs), ctx, q, p)
+}
+
+// TransferCompanyOwnership mocks base method.
+func (m *MockRepos) TransferCompanyOwnership(ctx context.Context, cid, uid uint) (models.Companies, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferCompanyOwnership", ctx, cid, uid)
+	ret0, _ := ret[0].(models.Companies)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferCompanyOwnership indicates an expected call of TransferCompanyOwnership.
+func (mr *MockReposMockRecorder) TransferCompanyOwnership(ctx, cid, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferCompanyOwnership", reflect.TypeOf((*MockRepos)(nil).TransferCompanyOwnership), ctx, cid, uid)
+}
+
+// UpdateApplicationStatus mocks base method.
+func (m *MockRepos) UpdateApplicationStatus(ctx context.Context, id uint, status string) (models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateApplicationStatus", ctx, id, status)
+	ret0, _ := ret[0].(models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateApplicationStatus indicates an expected call of UpdateApplicationStatus.
+func (mr *MockReposMockRecorder) UpdateApplicationStatus(ctx, id, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApplicationStatus", reflect.TypeOf((*MockRepos)(nil).UpdateApplicationStatus), ctx, id, status)
+}
+
+// UpdateCompany mocks base method.
+func (m *MockRepos) UpdateCompany(ctx context.Context, cid uint, changes map[string]any) (models.Companies, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCompany", ctx, cid, changes)
+	ret0, _ := ret[0].(models.Companies)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCompany indicates an expected call of UpdateCompany.
+func (mr *MockReposMockRecorder) UpdateCompany(ctx, cid, changes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCompany", reflect.TypeOf((*MockRepos)(nil).UpdateCompany), ctx, cid, changes)
+}
+
+// UpdateJob mocks base method.
+func (m *MockRepos) UpdateJob(ctx context.Context, jid uint64, changes map[string]any, skills []models.JobSkill) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJob", ctx, jid, changes, skills)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateJob indicates an expected call of UpdateJob.
+func (mr *MockReposMockRecorder) UpdateJob(ctx, jid, changes, skills any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJob", reflect.TypeOf((*MockRepos)(nil).UpdateJob), ctx, jid, changes, skills)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockRepos) UpdatePassword(ctx context.Context, uid uint, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, uid, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockReposMockRecorder) UpdatePassword(ctx, uid, passwordHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockRepos)(nil).UpdatePassword), ctx, uid, passwordHash)
+}
+
+// UpdateUserRole mocks base method.
+func (m *MockRepos) UpdateUserRole(ctx context.Context, uid uint, role string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", ctx, uid, role)
 	ret0, _ := ret[0].(models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockUserRepoMockRecorder) CreateUser(ctx, userData any) *gomock.Call {
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockReposMockRecorder) UpdateUserRole(ctx, uid, role any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserRepo)(nil).CreateUser), ctx, userData)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockRepos)(nil).UpdateUserRole), ctx, uid, role)
 }
 
-// FindAllJobs mocks base method.
-func (m *MockUserRepo) FindAllJobs(ctx context.Context) ([]models.Job, error) {
+// ViewApplication mocks base method.
+func (m *MockRepos) ViewApplication(ctx context.Context, id uint) (models.Application, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAllJobs", ctx)
-	ret0, _ := ret[0].([]models.Job)
+	ret := m.ctrl.Call(m, "ViewApplication", ctx, id)
+	ret0, _ := ret[0].(models.Application)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindAllJobs indicates an expected call of FindAllJobs.
-func (mr *MockUserRepoMockRecorder) FindAllJobs(ctx any) *gomock.Call {
+// ViewApplication indicates an expected call of ViewApplication.
+func (mr *MockReposMockRecorder) ViewApplication(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllJobs", reflect.TypeOf((*MockUserRepo)(nil).FindAllJobs), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewApplication", reflect.TypeOf((*MockRepos)(nil).ViewApplication), ctx, id)
 }
 
-// FindJob mocks base method.
-func (m *MockUserRepo) FindJob(ctx context.Context, cid uint64) ([]models.Job, error) {
+// ViewApplicationByJobAndUser mocks base method.
+func (m *MockRepos) ViewApplicationByJobAndUser(ctx context.Context, jid uint64, uid uint) (models.Application, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindJob", ctx, cid)
-	ret0, _ := ret[0].([]models.Job)
+	ret := m.ctrl.Call(m, "ViewApplicationByJobAndUser", ctx, jid, uid)
+	ret0, _ := ret[0].(models.Application)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindJob indicates an expected call of FindJob.
-func (mr *MockUserRepoMockRecorder) FindJob(ctx, cid any) *gomock.Call {
+// ViewApplicationByJobAndUser indicates an expected call of ViewApplicationByJobAndUser.
+func (mr *MockReposMockRecorder) ViewApplicationByJobAndUser(ctx, jid, uid any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindJob", reflect.TypeOf((*MockUserRepo)(nil).FindJob), ctx, cid)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewApplicationByJobAndUser", reflect.TypeOf((*MockRepos)(nil).ViewApplicationByJobAndUser), ctx, jid, uid)
 }
 
 // ViewCompanies mocks base method.
-func (m *MockUserRepo) ViewCompanies(ctx context.Context) ([]models.Companies, error) {
+func (m *MockRepos) ViewCompanies(ctx context.Context, p models.PageRequest) ([]models.Companies, models.PageInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ViewCompanies", ctx)
+	ret := m.ctrl.Call(m, "ViewCompanies", ctx, p)
 	ret0, _ := ret[0].([]models.Companies)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(models.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // ViewCompanies indicates an expected call of ViewCompanies.
-func (mr *MockUserRepoMockRecorder) ViewCompanies(ctx any) *gomock.Call {
+func (mr *MockReposMockRecorder) ViewCompanies(ctx, p any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCompanies", reflect.TypeOf((*MockUserRepo)(nil).ViewCompanies), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCompanies", reflect.TypeOf((*MockRepos)(nil).ViewCompanies), ctx, p)
 }
 
 // ViewCompanyById mocks base method.
-func (m *MockUserRepo) ViewCompanyById(ctx context.Context, cid uint) ([]models.Companies, error) {
+func (m *MockRepos) ViewCompanyById(ctx context.Context, cid uint) ([]models.Companies, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ViewCompanyById", ctx, cid)
 	ret0, _ := ret[0].([]models.Companies)
@@ -169,28 +1130,44 @@ func (m *MockUserRepo) ViewCompanyById(ctx context.Context, cid uint) ([]models.
 }
 
 // ViewCompanyById indicates an expected call of ViewCompanyById.
-func (mr *MockUserRepoMockRecorder) ViewCompanyById(ctx, cid any) *gomock.Call {
+func (mr *MockReposMockRecorder) ViewCompanyById(ctx, cid any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCompanyById", reflect.TypeOf((*MockUserRepo)(nil).ViewCompanyById), ctx, cid)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCompanyById", reflect.TypeOf((*MockRepos)(nil).ViewCompanyById), ctx, cid)
 }
 
-// ViewJobByCompanyId mocks base method.
-func (m *MockUserRepo) ViewJobByCompanyId(ctx context.Context, id uint) ([]models.Job, error) {
+// ViewCompanyMember mocks base method.
+func (m *MockRepos) ViewCompanyMember(ctx context.Context, cid, uid uint) (models.CompanyMember, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ViewJobByCompanyId", ctx, id)
-	ret0, _ := ret[0].([]models.Job)
+	ret := m.ctrl.Call(m, "ViewCompanyMember", ctx, cid, uid)
+	ret0, _ := ret[0].(models.CompanyMember)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// ViewCompanyMember indicates an expected call of ViewCompanyMember.
+func (mr *MockReposMockRecorder) ViewCompanyMember(ctx, cid, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCompanyMember", reflect.TypeOf((*MockRepos)(nil).ViewCompanyMember), ctx, cid, uid)
+}
+
+// ViewJobByCompanyId mocks base method.
+func (m *MockRepos) ViewJobByCompanyId(ctx context.Context, id uint, p models.PageRequest) ([]models.Job, models.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewJobByCompanyId", ctx, id, p)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(models.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // ViewJobByCompanyId indicates an expected call of ViewJobByCompanyId.
-func (mr *MockUserRepoMockRecorder) ViewJobByCompanyId(ctx, id any) *gomock.Call {
+func (mr *MockReposMockRecorder) ViewJobByCompanyId(ctx, id, p any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewJobByCompanyId", reflect.TypeOf((*MockUserRepo)(nil).ViewJobByCompanyId), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewJobByCompanyId", reflect.TypeOf((*MockRepos)(nil).ViewJobByCompanyId), ctx, id, p)
 }
 
 // ViewJobDetailsById mocks base method.
-func (m *MockUserRepo) ViewJobDetailsById(ctx context.Context, jid uint64) (models.Job, error) {
+func (m *MockRepos) ViewJobDetailsById(ctx context.Context, jid uint64) (models.Job, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ViewJobDetailsById", ctx, jid)
 	ret0, _ := ret[0].(models.Job)
@@ -199,7 +1176,300 @@ func (m *MockUserRepo) ViewJobDetailsById(ctx context.Context, jid uint64) (mode
 }
 
 // ViewJobDetailsById indicates an expected call of ViewJobDetailsById.
-func (mr *MockUserRepoMockRecorder) ViewJobDetailsById(ctx, jid any) *gomock.Call {
+func (mr *MockReposMockRecorder) ViewJobDetailsById(ctx, jid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewJobDetailsById", reflect.TypeOf((*MockRepos)(nil).ViewJobDetailsById), ctx, jid)
+}
+
+// ViewPipeline mocks base method.
+func (m *MockRepos) ViewPipeline(ctx context.Context, cid uint) ([]models.PipelineStage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewPipeline", ctx, cid)
+	ret0, _ := ret[0].([]models.PipelineStage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewPipeline indicates an expected call of ViewPipeline.
+func (mr *MockReposMockRecorder) ViewPipeline(ctx, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewPipeline", reflect.TypeOf((*MockRepos)(nil).ViewPipeline), ctx, cid)
+}
+
+// ViewRefreshToken mocks base method.
+func (m *MockRepos) ViewRefreshToken(ctx context.Context, tokenHash string) (models.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewRefreshToken", ctx, tokenHash)
+	ret0, _ := ret[0].(models.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewRefreshToken indicates an expected call of ViewRefreshToken.
+func (mr *MockReposMockRecorder) ViewRefreshToken(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewRefreshToken", reflect.TypeOf((*MockRepos)(nil).ViewRefreshToken), ctx, tokenHash)
+}
+
+// ViewUserByEmail mocks base method.
+func (m *MockRepos) ViewUserByEmail(ctx context.Context, email string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewUserByEmail", ctx, email)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewUserByEmail indicates an expected call of ViewUserByEmail.
+func (mr *MockReposMockRecorder) ViewUserByEmail(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewUserByEmail", reflect.TypeOf((*MockRepos)(nil).ViewUserByEmail), ctx, email)
+}
+
+// ViewUserById mocks base method.
+func (m *MockRepos) ViewUserById(ctx context.Context, uid uint) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewUserById", ctx, uid)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewUserById indicates an expected call of ViewUserById.
+func (mr *MockReposMockRecorder) ViewUserById(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewUserById", reflect.TypeOf((*MockRepos)(nil).ViewUserById), ctx, uid)
+}
+
+// ViewUserClaims mocks base method.
+func (m *MockRepos) ViewUserClaims(ctx context.Context, uid uint) (auth.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewUserClaims", ctx, uid)
+	ret0, _ := ret[0].(auth.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewUserClaims indicates an expected call of ViewUserClaims.
+func (mr *MockReposMockRecorder) ViewUserClaims(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewUserClaims", reflect.TypeOf((*MockRepos)(nil).ViewUserClaims), ctx, uid)
+}
+
+// WithTx mocks base method.
+func (m *MockRepos) WithTx(ctx context.Context, fn func(Repos) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockReposMockRecorder) WithTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockRepos)(nil).WithTx), ctx, fn)
+}
+
+// MockApplicationRepo is a mock of ApplicationRepo interface.
+type MockApplicationRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockApplicationRepoMockRecorder
+}
+
+// MockApplicationRepoMockRecorder is the mock recorder for MockApplicationRepo.
+type MockApplicationRepoMockRecorder struct {
+	mock *MockApplicationRepo
+}
+
+// NewMockApplicationRepo creates a new mock instance.
+func NewMockApplicationRepo(ctrl *gomock.Controller) *MockApplicationRepo {
+	mock := &MockApplicationRepo{ctrl: ctrl}
+	mock.recorder = &MockApplicationRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApplicationRepo) EXPECT() *MockApplicationRepoMockRecorder {
+	return m.recorder
+}
+
+// CountOpenApplicationsOutside mocks base method.
+func (m *MockApplicationRepo) CountOpenApplicationsOutside(ctx context.Context, cid uint, stages []string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOpenApplicationsOutside", ctx, cid, stages)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOpenApplicationsOutside indicates an expected call of CountOpenApplicationsOutside.
+func (mr *MockApplicationRepoMockRecorder) CountOpenApplicationsOutside(ctx, cid, stages any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOpenApplicationsOutside", reflect.TypeOf((*MockApplicationRepo)(nil).CountOpenApplicationsOutside), ctx, cid, stages)
+}
+
+// CreateApplication mocks base method.
+func (m *MockApplicationRepo) CreateApplication(ctx context.Context, app models.Application) (models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateApplication", ctx, app)
+	ret0, _ := ret[0].(models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateApplication indicates an expected call of CreateApplication.
+func (mr *MockApplicationRepoMockRecorder) CreateApplication(ctx, app any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApplication", reflect.TypeOf((*MockApplicationRepo)(nil).CreateApplication), ctx, app)
+}
+
+// ListApplicationsByIDs mocks base method.
+func (m *MockApplicationRepo) ListApplicationsByIDs(ctx context.Context, ids []uint) ([]models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApplicationsByIDs", ctx, ids)
+	ret0, _ := ret[0].([]models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApplicationsByIDs indicates an expected call of ListApplicationsByIDs.
+func (mr *MockApplicationRepoMockRecorder) ListApplicationsByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApplicationsByIDs", reflect.TypeOf((*MockApplicationRepo)(nil).ListApplicationsByIDs), ctx, ids)
+}
+
+// ListApplicationsByJob mocks base method.
+func (m *MockApplicationRepo) ListApplicationsByJob(ctx context.Context, jid uint64, p models.PageRequest) ([]models.Application, models.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApplicationsByJob", ctx, jid, p)
+	ret0, _ := ret[0].([]models.Application)
+	ret1, _ := ret[1].(models.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListApplicationsByJob indicates an expected call of ListApplicationsByJob.
+func (mr *MockApplicationRepoMockRecorder) ListApplicationsByJob(ctx, jid, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApplicationsByJob", reflect.TypeOf((*MockApplicationRepo)(nil).ListApplicationsByJob), ctx, jid, p)
+}
+
+// ListApplicationsByUser mocks base method.
+func (m *MockApplicationRepo) ListApplicationsByUser(ctx context.Context, uid uint, p models.PageRequest) ([]models.Application, models.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApplicationsByUser", ctx, uid, p)
+	ret0, _ := ret[0].([]models.Application)
+	ret1, _ := ret[1].(models.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListApplicationsByUser indicates an expected call of ListApplicationsByUser.
+func (mr *MockApplicationRepoMockRecorder) ListApplicationsByUser(ctx, uid, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApplicationsByUser", reflect.TypeOf((*MockApplicationRepo)(nil).ListApplicationsByUser), ctx, uid, p)
+}
+
+// ListStageChanges mocks base method.
+func (m *MockApplicationRepo) ListStageChanges(ctx context.Context, aid uint) ([]models.ApplicationStageChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStageChanges", ctx, aid)
+	ret0, _ := ret[0].([]models.ApplicationStageChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStageChanges indicates an expected call of ListStageChanges.
+func (mr *MockApplicationRepoMockRecorder) ListStageChanges(ctx, aid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStageChanges", reflect.TypeOf((*MockApplicationRepo)(nil).ListStageChanges), ctx, aid)
+}
+
+// MoveApplications mocks base method.
+func (m *MockApplicationRepo) MoveApplications(ctx context.Context, changes []models.ApplicationStageChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveApplications", ctx, changes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveApplications indicates an expected call of MoveApplications.
+func (mr *MockApplicationRepoMockRecorder) MoveApplications(ctx, changes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveApplications", reflect.TypeOf((*MockApplicationRepo)(nil).MoveApplications), ctx, changes)
+}
+
+// ReplacePipeline mocks base method.
+func (m *MockApplicationRepo) ReplacePipeline(ctx context.Context, cid uint, stages []models.PipelineStage) ([]models.PipelineStage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplacePipeline", ctx, cid, stages)
+	ret0, _ := ret[0].([]models.PipelineStage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplacePipeline indicates an expected call of ReplacePipeline.
+func (mr *MockApplicationRepoMockRecorder) ReplacePipeline(ctx, cid, stages any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplacePipeline", reflect.TypeOf((*MockApplicationRepo)(nil).ReplacePipeline), ctx, cid, stages)
+}
+
+// UpdateApplicationStatus mocks base method.
+func (m *MockApplicationRepo) UpdateApplicationStatus(ctx context.Context, id uint, status string) (models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateApplicationStatus", ctx, id, status)
+	ret0, _ := ret[0].(models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateApplicationStatus indicates an expected call of UpdateApplicationStatus.
+func (mr *MockApplicationRepoMockRecorder) UpdateApplicationStatus(ctx, id, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApplicationStatus", reflect.TypeOf((*MockApplicationRepo)(nil).UpdateApplicationStatus), ctx, id, status)
+}
+
+// ViewApplication mocks base method.
+func (m *MockApplicationRepo) ViewApplication(ctx context.Context, id uint) (models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewApplication", ctx, id)
+	ret0, _ := ret[0].(models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewApplication indicates an expected call of ViewApplication.
+func (mr *MockApplicationRepoMockRecorder) ViewApplication(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewApplication", reflect.TypeOf((*MockApplicationRepo)(nil).ViewApplication), ctx, id)
+}
+
+// ViewApplicationByJobAndUser mocks base method.
+func (m *MockApplicationRepo) ViewApplicationByJobAndUser(ctx context.Context, jid uint64, uid uint) (models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewApplicationByJobAndUser", ctx, jid, uid)
+	ret0, _ := ret[0].(models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewApplicationByJobAndUser indicates an expected call of ViewApplicationByJobAndUser.
+func (mr *MockApplicationRepoMockRecorder) ViewApplicationByJobAndUser(ctx, jid, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewApplicationByJobAndUser", reflect.TypeOf((*MockApplicationRepo)(nil).ViewApplicationByJobAndUser), ctx, jid, uid)
+}
+
+// ViewPipeline mocks base method.
+func (m *MockApplicationRepo) ViewPipeline(ctx context.Context, cid uint) ([]models.PipelineStage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewPipeline", ctx, cid)
+	ret0, _ := ret[0].([]models.PipelineStage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewPipeline indicates an expected call of ViewPipeline.
+func (mr *MockApplicationRepoMockRecorder) ViewPipeline(ctx, cid any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewJobDetailsById", reflect.TypeOf((*MockUserRepo)(nil).ViewJobDetailsById), ctx, jid)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewPipeline", reflect.TypeOf((*MockApplicationRepo)(nil).ViewPipeline), ctx, cid)
 }
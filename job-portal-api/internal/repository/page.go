@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"fmt"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/pagination"
+
+	"gorm.io/gorm"
+)
+
+// jobSalary is the top of the salary range of a job, a job without a maximum pays its minimum.
+const jobSalary = "(CASE WHEN jobs.salary_max > 0 THEN jobs.salary_max ELSE jobs.salary_min END)"
+
+// sortColumns names the columns of a list the sort orders use. A list leaves the columns
+// of the orders it does not support empty.
+type sortColumns struct {
+	id      string
+	created string
+	salary  string
+	rank    string
+}
+
+var (
+	companyColumns     = sortColumns{id: "companies.id", created: "companies.created_at"}
+	jobColumns         = sortColumns{id: "jobs.id", created: "jobs.created_at", salary: jobSalary}
+	applicationColumns = sortColumns{id: "applications.id", created: "applications.created_at"}
+)
+
+// key returns the column p is sorted on, whether it is sorted in descending order and the
+// value of the column at the cursor.
+func (sc sortColumns) key(p models.PageRequest) (string, bool, interface{}, error) {
+	var c models.Cursor
+	if p.Cursor != nil {
+		c = *p.Cursor
+	}
+	switch {
+	case p.Sort == models.SortNewest || p.Sort == "":
+		return sc.created, true, c.Time, nil
+	case p.Sort == models.SortOldest:
+		return sc.created, false, c.Time, nil
+	case p.Sort == models.SortSalary && sc.salary != "":
+		return sc.salary, true, c.Value, nil
+	case p.Sort == models.SortRelevance && sc.rank != "":
+		return sc.rank, true, c.Value, nil
+	}
+	return "", false, nil, fmt.Errorf("list cannot be sorted by %q", p.Sort)
+}
+
+// paginate orders a query by p and keeps the rows on the side of the cursor p asks for.
+// It fetches one row more than the limit so pageOf can tell whether the list goes on.
+func paginate(p models.PageRequest, sc sortColumns) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		column, desc, value, err := sc.key(p)
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		// Rows before the cursor are read in the reverse order, closest first.
+		if p.Cursor != nil && p.Cursor.Backward {
+			desc = !desc
+		}
+		op, dir := ">", "ASC"
+		if desc {
+			op, dir = "<", "DESC"
+		}
+		if p.Cursor != nil {
+			db = db.Where(fmt.Sprintf("(%s, %s) %s (?, ?)", column, sc.id, op), value, p.Cursor.ID)
+		}
+		return db.Order(fmt.Sprintf("%s %s, %s %s", column, dir, sc.id, dir)).Limit(pageSize(p) + 1)
+	}
+}
+
+// pageSize is the limit of p. Requests from clients get theirs from the Paginator, the
+// ones made in the code without a limit get the default of the pagination package.
+func pageSize(p models.PageRequest) int {
+	if p.Limit <= 0 {
+		return pagination.DefaultSize
+	}
+	return p.Limit
+}
+
+// pageOf trims the rows fetched by paginate to the page and returns the cursors of the
+// pages around it, cursor returns the position of a row.
+func pageOf[T any](rows []T, p models.PageRequest, cursor func(T) models.Cursor) ([]T, models.PageInfo) {
+	backward := p.Cursor != nil && p.Cursor.Backward
+	more := len(rows) > pageSize(p)
+	if more {
+		rows = rows[:pageSize(p)]
+	}
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	var info models.PageInfo
+	if len(rows) == 0 {
+		return rows, info
+	}
+	first, last := cursor(rows[0]), cursor(rows[len(rows)-1])
+	first.Sort, last.Sort = p.Sort, p.Sort
+	first.Backward = true
+	// The page a cursor came from is always on the other side of it.
+	if backward {
+		if more {
+			info.Prev = &first
+		}
+		info.Next = &last
+	} else {
+		if more {
+			info.Next = &last
+		}
+		if p.Cursor != nil {
+			info.Prev = &first
+		}
+	}
+	return rows, info
+}
+
+func companyCursor(c models.Companies) models.Cursor {
+	return models.Cursor{ID: c.ID, Time: c.CreatedAt}
+}
+
+func jobCursor(j models.Job) models.Cursor {
+	salary := j.SalaryMin
+	if j.SalaryMax > 0 {
+		salary = j.SalaryMax
+	}
+	return models.Cursor{ID: j.ID, Time: j.CreatedAt, Value: float64(salary)}
+}
+
+func applicationCursor(a models.Application) models.Cursor {
+	return models.Cursor{ID: a.ID, Time: a.CreatedAt}
+}
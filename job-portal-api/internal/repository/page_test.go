@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"job-portal-api/internal/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_paginate(t *testing.T) {
+	at := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		p        models.PageRequest
+		wantSQL  string
+		wantVars []interface{}
+	}{
+		{
+			name:    "first page",
+			p:       models.PageRequest{Limit: 2, Sort: models.SortNewest},
+			wantSQL: `SELECT * FROM "companies" WHERE "companies"."deleted_at" IS NULL ORDER BY companies.created_at DESC, companies.id DESC LIMIT 3`,
+		},
+		{
+			name:     "after a cursor",
+			p:        models.PageRequest{Limit: 2, Sort: models.SortOldest, Cursor: &models.Cursor{ID: 4, Time: at}},
+			wantSQL:  `SELECT * FROM "companies" WHERE (companies.created_at, companies.id) > ($1, $2) AND "companies"."deleted_at" IS NULL ORDER BY companies.created_at ASC, companies.id ASC LIMIT 3`,
+			wantVars: []interface{}{at, uint(4)},
+		},
+		{
+			name:     "before a cursor",
+			p:        models.PageRequest{Limit: 2, Sort: models.SortNewest, Cursor: &models.Cursor{ID: 4, Time: at, Backward: true}},
+			wantSQL:  `SELECT * FROM "companies" WHERE (companies.created_at, companies.id) > ($1, $2) AND "companies"."deleted_at" IS NULL ORDER BY companies.created_at ASC, companies.id ASC LIMIT 3`,
+			wantVars: []interface{}{at, uint(4)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var companies []models.Companies
+			stmt := dryRun(t).Scopes(paginate(tt.p, companyColumns)).Find(&companies).Statement
+			if stmt.SQL.String() != tt.wantSQL {
+				t.Errorf("SQL = %s\nwant  %s", stmt.SQL.String(), tt.wantSQL)
+			}
+			if len(stmt.Vars) != len(tt.wantVars) || (len(tt.wantVars) > 0 && !reflect.DeepEqual(stmt.Vars, tt.wantVars)) {
+				t.Errorf("Vars = %v, want %v", stmt.Vars, tt.wantVars)
+			}
+		})
+	}
+
+	var companies []models.Companies
+	err := dryRun(t).Scopes(paginate(models.PageRequest{Sort: models.SortSalary}, companyColumns)).Find(&companies).Error
+	if err == nil {
+		t.Error("sorting companies by salary did not fail")
+	}
+}
+
+func Test_pageOf(t *testing.T) {
+	cursor := func(id uint) models.Cursor { return models.Cursor{ID: id} }
+	at := func(id uint, backward bool) *models.Cursor {
+		return &models.Cursor{Sort: models.SortNewest, ID: id, Backward: backward}
+	}
+	tests := []struct {
+		name     string
+		rows     []uint
+		p        models.PageRequest
+		wantRows []uint
+		wantInfo models.PageInfo
+	}{
+		{
+			name:     "only page",
+			rows:     []uint{9, 8},
+			p:        models.PageRequest{Limit: 2, Sort: models.SortNewest},
+			wantRows: []uint{9, 8},
+		},
+		{
+			name:     "first page",
+			rows:     []uint{9, 8, 7},
+			p:        models.PageRequest{Limit: 2, Sort: models.SortNewest},
+			wantRows: []uint{9, 8},
+			wantInfo: models.PageInfo{Next: at(8, false)},
+		},
+		{
+			name:     "middle page",
+			rows:     []uint{7, 6, 5},
+			p:        models.PageRequest{Limit: 2, Sort: models.SortNewest, Cursor: at(8, false)},
+			wantRows: []uint{7, 6},
+			wantInfo: models.PageInfo{Next: at(6, false), Prev: at(7, true)},
+		},
+		{
+			name:     "last page",
+			rows:     []uint{5},
+			p:        models.PageRequest{Limit: 2, Sort: models.SortNewest, Cursor: at(6, false)},
+			wantRows: []uint{5},
+			wantInfo: models.PageInfo{Prev: at(5, true)},
+		},
+		{
+			name:     "back to a middle page",
+			rows:     []uint{6, 7, 8},
+			p:        models.PageRequest{Limit: 2, Sort: models.SortNewest, Cursor: at(5, true)},
+			wantRows: []uint{7, 6},
+			wantInfo: models.PageInfo{Next: at(6, false), Prev: at(7, true)},
+		},
+		{
+			name:     "back to the first page",
+			rows:     []uint{8, 9},
+			p:        models.PageRequest{Limit: 2, Sort: models.SortNewest, Cursor: at(7, true)},
+			wantRows: []uint{9, 8},
+			wantInfo: models.PageInfo{Next: at(8, false)},
+		},
+		{
+			name:     "empty",
+			p:        models.PageRequest{Limit: 2, Sort: models.SortNewest, Cursor: at(1, false)},
+			wantRows: []uint{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := append([]uint{}, tt.rows...)
+			got, info := pageOf(rows, tt.p, cursor)
+			if !reflect.DeepEqual(got, tt.wantRows) {
+				t.Errorf("pageOf() rows = %v, want %v", got, tt.wantRows)
+			}
+			if !reflect.DeepEqual(info, tt.wantInfo) {
+				t.Errorf("pageOf() info = %+v, want %+v", info, tt.wantInfo)
+			}
+		})
+	}
+}
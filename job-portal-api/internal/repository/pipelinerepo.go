@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/models"
+)
+
+// ViewPipeline returns the stages of a company in order, none when it uses the default pipeline.
+func (r *Repo) ViewPipeline(ctx context.Context, cid uint) ([]models.PipelineStage, error) {
+	var stages []models.PipelineStage
+	result := r.DB.WithContext(ctx).Where("company_id = ?", cid).Order("position").Find(&stages)
+	if result.Error != nil {
+		return nil, dbError(result.Error, "pipeline")
+	}
+	return stages, nil
+}
+
+func (r *Repo) ReplacePipeline(ctx context.Context, cid uint, stages []models.PipelineStage) ([]models.PipelineStage, error) {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The old stages are removed for good so their names can be used again.
+		err := tx.Unscoped().Where("company_id = ?", cid).Delete(&models.PipelineStage{}).Error
+		if err != nil {
+			return err
+		}
+		for i := range stages {
+			stages[i].CompanyID = cid
+		}
+		return tx.Create(&stages).Error
+	})
+	if err != nil {
+		return nil, dbError(err, "pipeline")
+	}
+	return stages, nil
+}
+
+// CountOpenApplicationsOutside counts the applications to the jobs of a company that are
+// still being considered and sit in a stage that is not in stages.
+func (r *Repo) CountOpenApplicationsOutside(ctx context.Context, cid uint, stages []string) (int64, error) {
+	var n int64
+	result := r.DB.WithContext(ctx).Model(&models.Application{}).
+		Joins("JOIN jobs ON jobs.id = applications.job_id").
+		Where("jobs.company_id = ?", cid).
+		Where("applications.status IN ?", []string{models.ApplicationSubmitted, models.ApplicationInReview}).
+		Where("applications.stage <> '' AND applications.stage NOT IN ?", stages).
+		Count(&n)
+	if result.Error != nil {
+		return 0, dbError(result.Error, "application")
+	}
+	return n, nil
+}
+
+// ListApplicationsByIDs returns the applications with the given ids that exist, together with their jobs.
+func (r *Repo) ListApplicationsByIDs(ctx context.Context, ids []uint) ([]models.Application, error) {
+	var apps []models.Application
+	result := r.DB.WithContext(ctx).Preload("Job").Where("id IN ?", ids).Find(&apps)
+	if result.Error != nil {
+		return nil, dbError(result.Error, "application")
+	}
+	return apps, nil
+}
+
+// MoveApplications applies every stage change and records it, or none of them. A change
+// whose application is no longer in its from stage fails with apperr.ErrConflict.
+func (r *Repo) MoveApplications(ctx context.Context, changes []models.ApplicationStageChange) error {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, ch := range changes {
+			result := tx.Model(&models.Application{}).
+				Where("id = ? AND stage = ?", ch.ApplicationID, ch.FromStage).
+				Updates(map[string]interface{}{"stage": ch.ToStage, "status": ch.Status})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return apperr.Wrap(apperr.ErrConflict, nil, fmt.Sprintf("application %d changed stage", ch.ApplicationID))
+			}
+		}
+		return tx.Create(&changes).Error
+	})
+	return dbError(err, "application")
+}
+
+// ListStageChanges returns the history of an application, oldest first.
+func (r *Repo) ListStageChanges(ctx context.Context, aid uint) ([]models.ApplicationStageChange, error) {
+	var changes []models.ApplicationStageChange
+	result := r.DB.WithContext(ctx).Where("application_id = ?", aid).Order("created_at, id").Find(&changes)
+	if result.Error != nil {
+		return nil, dbError(result.Error, "stage change")
+	}
+	return changes, nil
+}
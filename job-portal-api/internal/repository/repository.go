@@ -3,9 +3,10 @@ package repository
 import (
 	"context"
 	"errors"
-	"github.com/golang-jwt/jwt/v5"
 	"gorm.io/gorm"
+	"job-portal-api/internal/auth"
 	"job-portal-api/internal/models"
+	"time"
 )
 
 type Repo struct {
@@ -13,23 +14,86 @@ type Repo struct {
 }
 
 //go:generate mockgen -source=repository.go -destination=mock_repository.go -package=repository
-type UserRepo interface {
+
+// UserStore stores the users and their sessions.
+type UserStore interface {
 	CreateUser(ctx context.Context, userData models.User) (models.User, error)
-	CheckEmail(ctx context.Context, email string, password string) (jwt.RegisteredClaims, error)
+	CheckEmail(ctx context.Context, email string, password string) (auth.Claims, error)
+	UpdateUserRole(ctx context.Context, uid uint, role string) (models.User, error)
+	ViewUserByEmail(ctx context.Context, email string) (models.User, error)
+	ViewUserById(ctx context.Context, uid uint) (models.User, error)
+	UpdatePassword(ctx context.Context, uid uint, passwordHash string) error
+	ViewUserClaims(ctx context.Context, uid uint) (auth.Claims, error)
+
+	CreateRefreshToken(ctx context.Context, token models.RefreshToken) (models.RefreshToken, error)
+	ViewRefreshToken(ctx context.Context, tokenHash string) (models.RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, id uint) (bool, error)
+	RevokeTokenFamily(ctx context.Context, familyID string) error
+	RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
 
+// CompanyStore stores the companies and their members.
+type CompanyStore interface {
 	CreateCompany(ctx context.Context, companyData models.Companies) (models.Companies, error)
-	ViewCompanies(ctx context.Context) ([]models.Companies, error)
+	ViewCompanies(ctx context.Context, p models.PageRequest) ([]models.Companies, models.PageInfo, error)
 	ViewCompanyById(ctx context.Context, cid uint) ([]models.Companies, error)
+	UpdateCompany(ctx context.Context, cid uint, changes map[string]interface{}) (models.Companies, error)
+	DeleteCompany(ctx context.Context, cid uint) error
+	RestoreCompany(ctx context.Context, cid uint, restoreJobs bool) (models.Companies, error)
+	AddCompanyMember(ctx context.Context, member models.CompanyMember) (models.CompanyMember, error)
+	ViewCompanyMember(ctx context.Context, cid uint, uid uint) (models.CompanyMember, error)
+	TransferCompanyOwnership(ctx context.Context, cid uint, uid uint) (models.Companies, error)
+}
 
+// JobStore stores the jobs companies post, and searches them.
+type JobStore interface {
 	CreateJob(ctx context.Context, jobData models.Job) (models.Job, error)
-	FindJob(ctx context.Context, cid uint64) ([]models.Job, error)
-	FindAllJobs(ctx context.Context) ([]models.Job, error)
+	UpdateJob(ctx context.Context, jid uint64, changes map[string]interface{}, skills []models.JobSkill) (models.Job, error)
+	DeleteJob(ctx context.Context, jid uint64) error
+	RestoreJob(ctx context.Context, jid uint64) (models.Job, error)
+	FindAllJobs(ctx context.Context, q models.JobQuery, p models.PageRequest) ([]models.Job, models.PageInfo, error)
+	JobFacets(ctx context.Context, q models.JobQuery) (models.JobFacets, error)
 	ViewJobDetailsById(ctx context.Context, jid uint64) (models.Job, error)
-	ViewJobByCompanyId(ctx context.Context, id uint) ([]models.Job, error)
-	AutoMigrate() error
+	ViewJobByCompanyId(ctx context.Context, id uint, p models.PageRequest) ([]models.Job, models.PageInfo, error)
+	SearchJobs(ctx context.Context, q string, p models.PageRequest) ([]models.JobSearchResult, models.PageInfo, error)
+}
+
+// UnitOfWork runs writes to several stores as a whole.
+type UnitOfWork interface {
+	// WithTx runs fn as one unit of work on the repositories it gives fn: the writes of fn
+	// are kept when it returns nil and undone when it returns an error. fn must use only
+	// the repositories it is given, and only until it returns.
+	WithTx(ctx context.Context, fn func(tx Repos) error) error
+}
+
+// Repos are all the repositories of the api, Repo and MemoryRepo implement them.
+type Repos interface {
+	UserStore
+	CompanyStore
+	JobStore
+	ApplicationRepo
+	UnitOfWork
+}
+
+// ApplicationRepo stores the applications candidates make to jobs.
+type ApplicationRepo interface {
+	CreateApplication(ctx context.Context, app models.Application) (models.Application, error)
+	ViewApplication(ctx context.Context, id uint) (models.Application, error)
+	ViewApplicationByJobAndUser(ctx context.Context, jid uint64, uid uint) (models.Application, error)
+	ListApplicationsByUser(ctx context.Context, uid uint, p models.PageRequest) ([]models.Application, models.PageInfo, error)
+	ListApplicationsByJob(ctx context.Context, jid uint64, p models.PageRequest) ([]models.Application, models.PageInfo, error)
+	UpdateApplicationStatus(ctx context.Context, id uint, status string) (models.Application, error)
+	ListApplicationsByIDs(ctx context.Context, ids []uint) ([]models.Application, error)
+	MoveApplications(ctx context.Context, changes []models.ApplicationStageChange) error
+	ListStageChanges(ctx context.Context, aid uint) ([]models.ApplicationStageChange, error)
+
+	ViewPipeline(ctx context.Context, cid uint) ([]models.PipelineStage, error)
+	ReplacePipeline(ctx context.Context, cid uint, stages []models.PipelineStage) ([]models.PipelineStage, error)
+	CountOpenApplicationsOutside(ctx context.Context, cid uint, stages []string) (int64, error)
 }
 
-func NewRepository(db *gorm.DB) (UserRepo, error) {
+func NewRepository(db *gorm.DB) (Repos, error) {
 	if db == nil {
 		return nil, errors.New("db cannot be null")
 	}
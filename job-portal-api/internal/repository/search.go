@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"context"
+	"html"
+	"job-portal-api/internal/database"
+	"job-portal-api/internal/models"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ts_headline marks the matches between startSel and stopSel rather than in <mark>, so the
+// headline can be escaped for html before the marks become tags. They are private use
+// characters, removed from the text beforehand so a job cannot put marks of its own.
+const (
+	startSel = "\uE000"
+	stopSel  = "\uE001"
+)
+
+// Options passed to ts_headline for the titles and the snippets of the descriptions.
+const (
+	titleHeadlineOptions = "StartSel=" + startSel + ", StopSel=" + stopSel + ", HighlightAll=true"
+	headlineOptions      = "StartSel=" + startSel + ", StopSel=" + stopSel + ", MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=\" … \""
+)
+
+// marks turns the marks of an escaped headline into <mark> tags.
+var marks = strings.NewReplacer(startSel, "<mark>", stopSel, "</mark>")
+
+// markHeadline escapes a headline of ts_headline for html and turns its marks into <mark>
+// tags, the only markup it keeps.
+func markHeadline(headline string) string {
+	return marks.Replace(html.EscapeString(headline))
+}
+
+// hitColumns are the sort columns of the matches of a search.
+var hitColumns = sortColumns{id: "hits.id", created: "hits.created_at", salary: "hits.salary", rank: "hits.rank"}
+
+type searchHit struct {
+	ID        uint
+	CreatedAt time.Time
+	Salary    int
+	Rank      float64
+}
+
+// SearchJobs returns a page of the open jobs of active companies matching q, see tsQuery
+// for the syntax. They are sorted by relevance unless p asks for another order.
+func (r *Repo) SearchJobs(ctx context.Context, q string, p models.PageRequest) ([]models.JobSearchResult, models.PageInfo, error) {
+	if r.DB.Dialector.Name() != database.DialectPostgres {
+		return r.scanJobs(ctx, q, p)
+	}
+	query := tsQuery(q)
+	if query == "" {
+		return []models.JobSearchResult{}, models.PageInfo{}, nil
+	}
+	if p.Sort == "" {
+		p.Sort = models.SortRelevance
+	}
+
+	matches := r.DB.WithContext(ctx).Raw(`
+		SELECT jobs.id, jobs.created_at, `+jobSalary+` AS salary, ts_rank_cd(jobs.search_vector, q) AS rank
+		FROM jobs
+		JOIN companies ON companies.id = jobs.company_id AND companies.deleted_at IS NULL,
+			to_tsquery('english', ?) AS q
+		WHERE jobs.deleted_at IS NULL
+			AND jobs.status = ?
+			AND (jobs.expires_at IS NULL OR jobs.expires_at > now())
+			AND jobs.search_vector @@ q`, query, models.JobStatusOpen)
+	var hits []searchHit
+	result := r.DB.WithContext(ctx).Table("(?) AS hits", matches).Scopes(paginate(p, hitColumns)).Scan(&hits)
+	if result.Error != nil {
+		return nil, models.PageInfo{}, dbError(result.Error, "job")
+	}
+	hits, info := pageOf(hits, p, func(h searchHit) models.Cursor {
+		c := models.Cursor{ID: h.ID, Time: h.CreatedAt, Value: float64(h.Salary)}
+		if p.Sort == models.SortRelevance {
+			c.Value = h.Rank
+		}
+		return c
+	})
+	if len(hits) == 0 {
+		return []models.JobSearchResult{}, info, nil
+	}
+
+	ids := make([]uint, 0, len(hits))
+	for _, h := range hits {
+		ids = append(ids, h.ID)
+	}
+	// Headlines are only worked out for the page, ts_headline is slow.
+	var headlines []struct {
+		ID             uint
+		TitleHighlight string
+		Snippet        string
+	}
+	result = r.DB.WithContext(ctx).Raw(`
+		SELECT jobs.id,
+			ts_headline('english', translate(jobs.title, ?, ''), q, ?) AS title_highlight,
+			ts_headline('english', translate(jobs.description, ?, ''), q, ?) AS snippet
+		FROM jobs, to_tsquery('english', ?) AS q
+		WHERE jobs.id IN ?`, startSel+stopSel, titleHeadlineOptions, startSel+stopSel, headlineOptions, query, ids).Scan(&headlines)
+	if result.Error != nil {
+		return nil, models.PageInfo{}, dbError(result.Error, "job")
+	}
+	var jobs []models.Job
+	result = r.DB.WithContext(ctx).Preload("Skills").Where("id IN ?", ids).Find(&jobs)
+	if result.Error != nil {
+		return nil, models.PageInfo{}, dbError(result.Error, "job")
+	}
+	byID := make(map[uint]models.JobSearchResult, len(jobs))
+	for _, j := range jobs {
+		byID[j.ID] = models.JobSearchResult{Job: j}
+	}
+	for _, h := range headlines {
+		res, ok := byID[h.ID]
+		if !ok {
+			continue
+		}
+		res.TitleHighlight, res.Snippet = markHeadline(h.TitleHighlight), markHeadline(h.Snippet)
+		byID[h.ID] = res
+	}
+
+	results := make([]models.JobSearchResult, 0, len(hits))
+	for _, h := range hits {
+		res, ok := byID[h.ID]
+		if !ok {
+			// Deleted between the queries.
+			continue
+		}
+		res.Rank = h.Rank
+		results = append(results, res)
+	}
+	return results, info, nil
+}
+
+// scanJobs is SearchJobs for the databases without full text search. Every open job is
+// read and matched in Go like the in-memory repository does, which is fine for the number
+// of jobs a single node holds.
+func (r *Repo) scanJobs(ctx context.Context, q string, p models.PageRequest) ([]models.JobSearchResult, models.PageInfo, error) {
+	query := parseTextQuery(q)
+	if len(query) == 0 {
+		return []models.JobSearchResult{}, models.PageInfo{}, nil
+	}
+	if p.Sort == "" {
+		p.Sort = models.SortRelevance
+	}
+
+	var jobs []models.Job
+	result := r.DB.WithContext(ctx).Scopes(activeCompany).Preload("Skills").
+		Where("jobs.status = ? AND (jobs.expires_at IS NULL OR jobs.expires_at > ?)", models.JobStatusOpen, time.Now()).
+		Find(&jobs)
+	if result.Error != nil {
+		return nil, models.PageInfo{}, dbError(result.Error, "job")
+	}
+	companies, err := r.companyNames(ctx, jobs)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	var hits []models.JobSearchResult
+	for _, j := range jobs {
+		rank, ok := query.match(jobFields(j, companies[j.CompanyID], j.Skills))
+		if ok {
+			hits = append(hits, models.JobSearchResult{Job: j, Rank: rank})
+		}
+	}
+	hits, info, err := pageSlice(hits, p, hitColumns, func(h models.JobSearchResult) models.Cursor {
+		c := jobCursor(h.Job)
+		if p.Sort == models.SortRelevance {
+			c.Value = h.Rank
+		}
+		return c
+	})
+	if err != nil {
+		return nil, models.PageInfo{}, dbError(err, "job")
+	}
+	results := make([]models.JobSearchResult, 0, len(hits))
+	for _, h := range hits {
+		h.TitleHighlight = query.highlight(h.Job.Title, 0)
+		h.Snippet = query.highlight(h.Job.Description, snippetWords)
+		results = append(results, h)
+	}
+	return results, info, nil
+}
+
+// companyNames returns the names of the companies of jobs by id.
+func (r *Repo) companyNames(ctx context.Context, jobs []models.Job) (map[uint]string, error) {
+	ids := make([]uint, 0, len(jobs))
+	for _, j := range jobs {
+		ids = append(ids, j.CompanyID)
+	}
+	var companies []models.Companies
+	result := r.DB.WithContext(ctx).Select("id", "company_name").Where("id IN ?", ids).Find(&companies)
+	if result.Error != nil {
+		return nil, dbError(result.Error, "company")
+	}
+	names := make(map[uint]string, len(companies))
+	for _, c := range companies {
+		names[c.ID] = c.CompanyName
+	}
+	return names, nil
+}
+
+// tsQuery turns a search typed by a user into a to_tsquery expression. Words must all
+// match, "quoted words" must match as a phrase, a trailing * matches any word starting
+// with the prefix, a leading - excludes a word and OR between two terms matches either.
+// Everything that is not a letter or a digit separates words, so the result is always
+// valid tsquery syntax.
+func tsQuery(q string) string {
+	var terms []string
+	or := false
+	for _, tok := range splitQuery(q) {
+		if !tok.quoted && tok.text == "OR" {
+			or = len(terms) > 0
+			continue
+		}
+		term := tok.term()
+		if term == "" {
+			continue
+		}
+		if or {
+			terms[len(terms)-1] = "(" + terms[len(terms)-1] + " | " + term + ")"
+			or = false
+			continue
+		}
+		terms = append(terms, term)
+	}
+	return strings.Join(terms, " & ")
+}
+
+type queryToken struct {
+	text   string
+	quoted bool
+}
+
+// splitQuery splits q on spaces, keeping "quoted phrases" together. An unterminated quote
+// runs to the end of q.
+func splitQuery(q string) []queryToken {
+	var toks []queryToken
+	var cur strings.Builder
+	quoted := false
+	flush := func() {
+		if cur.Len() > 0 || quoted {
+			toks = append(toks, queryToken{text: cur.String(), quoted: quoted})
+		}
+		cur.Reset()
+	}
+	for _, r := range q {
+		switch {
+		case r == '"':
+			flush()
+			quoted = !quoted
+		case unicode.IsSpace(r) && !quoted:
+			flush()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	flush()
+	return toks
+}
+
+func (t queryToken) term() string {
+	if t.quoted {
+		words := lexemes(t.text)
+		if len(words) == 0 {
+			return ""
+		}
+		return "(" + strings.Join(words, " <-> ") + ")"
+	}
+
+	text := t.text
+	negate := strings.HasPrefix(text, "-")
+	prefix := strings.HasSuffix(text, "*")
+	words := lexemes(text)
+	if len(words) == 0 {
+		return ""
+	}
+	// Words joined by punctuation, like node.js, are matched as a phrase.
+	term := strings.Join(words, " <-> ")
+	if prefix {
+		term += ":*"
+	}
+	if len(words) > 1 {
+		term = "(" + term + ")"
+	}
+	if negate {
+		term = "!" + term
+	}
+	return term
+}
+
+// lexemes returns the runs of letters and digits of s.
+func lexemes(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
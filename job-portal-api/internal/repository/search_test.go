@@ -0,0 +1,36 @@
+package repository
+
+import "testing"
+
+func Test_tsQuery(t *testing.T) {
+	tests := []struct {
+		q    string
+		want string
+	}{
+		{q: "golang backend", want: "golang & backend"},
+		{q: `"site reliability" engineer`, want: "(site <-> reliability) & engineer"},
+		{q: "kube*", want: "kube:*"},
+		{q: "java -script", want: "java & !script"},
+		{q: "rust OR go remote", want: "(rust | go) & remote"},
+		{q: "node.js", want: "(node <-> js)"},
+		{q: "c++ & | ! :*", want: "c"},
+		{q: `"unterminated phrase`, want: "(unterminated <-> phrase)"},
+		{q: `OR "" ***`, want: ""},
+		{q: "Café Zürich", want: "café & zürich"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.q, func(t *testing.T) {
+			if got := tsQuery(tt.q); got != tt.want {
+				t.Errorf("tsQuery(%q) = %q, want %q", tt.q, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_markHeadline(t *testing.T) {
+	got := markHeadline("run <script>alert(1)</script> in " + startSel + "go" + stopSel + " & rust")
+	want := "run &lt;script&gt;alert(1)&lt;/script&gt; in <mark>go</mark> &amp; rust"
+	if got != want {
+		t.Errorf("markHeadline() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"html"
+	"job-portal-api/internal/models"
+	"strings"
+	"unicode"
+)
+
+// Weights of the fields of a job in a search, the ones postgres gives to the A, B and C
+// parts of jobs.search_vector.
+const (
+	weightTitle       = 1.0
+	weightCompany     = 0.4
+	weightDescription = 0.2
+)
+
+// snippetWords is the number of words of the description a search result shows.
+const snippetWords = 30
+
+// textQuery is a search parsed the way tsQuery reads it, for the stores that match
+// documents in Go instead of with the full text search of postgres. Every group must
+// match, a group matches when any of its terms does. Words are compared whole and
+// without stemming, so it finds fewer jobs than postgres does.
+type textQuery [][]textTerm
+
+// textTerm is a word, or a phrase of consecutive words, the last of which may be a prefix.
+type textTerm struct {
+	words  []string
+	prefix bool
+	negate bool
+}
+
+// textField is a part of a searched document split into lexemes.
+type textField struct {
+	words  []string
+	weight float64
+}
+
+// jobFields splits a job into the weighted fields jobs.search_vector is built from.
+func jobFields(j models.Job, company string, skills []models.JobSkill) []textField {
+	names := make([]string, 0, len(skills))
+	for _, s := range skills {
+		names = append(names, s.Name)
+	}
+	return []textField{
+		{words: lexemes(j.Title), weight: weightTitle},
+		{words: lexemes(company), weight: weightCompany},
+		{words: lexemes(strings.Join(names, " ")), weight: weightCompany},
+		{words: lexemes(j.Description), weight: weightDescription},
+	}
+}
+
+// stopWords are left out of a search like the english configuration of postgres does.
+var stopWords = uniq([]string{
+	"a", "an", "and", "are", "as", "at", "be", "by", "for", "from", "in", "into", "is", "it",
+	"of", "on", "or", "our", "the", "to", "we", "with", "you", "your",
+})
+
+func parseTextQuery(q string) textQuery {
+	var groups textQuery
+	or := false
+	for _, tok := range splitQuery(q) {
+		if !tok.quoted && tok.text == "OR" {
+			or = len(groups) > 0
+			continue
+		}
+		term, ok := tok.textTerm()
+		if !ok {
+			continue
+		}
+		if or {
+			groups[len(groups)-1] = append(groups[len(groups)-1], term)
+			or = false
+			continue
+		}
+		groups = append(groups, []textTerm{term})
+	}
+	return groups
+}
+
+func (t queryToken) textTerm() (textTerm, bool) {
+	words := lexemes(t.text)
+	if len(words) == 0 || len(words) == 1 && stopWords[words[0]] {
+		return textTerm{}, false
+	}
+	if t.quoted {
+		return textTerm{words: words}, true
+	}
+	return textTerm{
+		words:  words,
+		prefix: strings.HasSuffix(t.text, "*"),
+		negate: strings.HasPrefix(t.text, "-"),
+	}, true
+}
+
+// match reports whether the fields of a document match q and ranks them. Every occurrence
+// of a term that is not negated adds the weight of its field.
+func (q textQuery) match(doc []textField) (float64, bool) {
+	if len(q) == 0 {
+		return 0, false
+	}
+	rank := 0.0
+	for _, group := range q {
+		matched := false
+		for _, t := range group {
+			n := t.occurrences(doc)
+			if t.negate {
+				matched = matched || n == 0
+				continue
+			}
+			if n > 0 {
+				matched = true
+				rank += n
+			}
+		}
+		if !matched {
+			return 0, false
+		}
+	}
+	return rank, true
+}
+
+func (t textTerm) occurrences(doc []textField) float64 {
+	n := 0.0
+	for _, f := range doc {
+		for i := range f.words {
+			if t.matchesAt(f.words, i) {
+				n += f.weight
+			}
+		}
+	}
+	return n
+}
+
+func (t textTerm) matchesAt(words []string, i int) bool {
+	if i+len(t.words) > len(words) {
+		return false
+	}
+	for k, w := range t.words {
+		got := words[i+k]
+		if got == w || k == len(t.words)-1 && t.prefix && strings.HasPrefix(got, w) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// highlight wraps the words of text matching q in <mark>. The text is written by employers,
+// so it is escaped for html and the <mark> tags are the only markup left. When maxWords is
+// positive only that many words are kept, starting a little before the first match.
+func (q textQuery) highlight(text string, maxWords int) string {
+	spans := wordSpans(text)
+	words := make([]string, len(spans))
+	for i, s := range spans {
+		words[i] = strings.ToLower(text[s[0]:s[1]])
+	}
+	marked := make([]bool, len(spans))
+	first := -1
+	for _, group := range q {
+		for _, t := range group {
+			if t.negate {
+				continue
+			}
+			for i := range words {
+				if !t.matchesAt(words, i) {
+					continue
+				}
+				for k := range t.words {
+					marked[i+k] = true
+				}
+				if first < 0 || i < first {
+					first = i
+				}
+			}
+		}
+	}
+
+	from, to := 0, len(spans)
+	if maxWords > 0 && len(spans) > maxWords {
+		from = max(0, min(first-3, len(spans)-maxWords))
+		to = from + maxWords
+	}
+	if from == to {
+		return html.EscapeString(text)
+	}
+	start, end := spans[from][0], spans[to-1][1]
+	if from == 0 {
+		start = 0
+	}
+	if to == len(spans) {
+		end = len(text)
+	}
+
+	var b strings.Builder
+	pos := start
+	for i := from; i < to; i++ {
+		if !marked[i] {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:spans[i][0]]))
+		b.WriteString("<mark>" + html.EscapeString(text[spans[i][0]:spans[i][1]]) + "</mark>")
+		pos = spans[i][1]
+	}
+	b.WriteString(html.EscapeString(text[pos:end]))
+	return b.String()
+}
+
+// wordSpans returns the byte offsets of the runs of letters and digits of s, the words
+// lexemes returns.
+func wordSpans(s string) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range s {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case word && start < 0:
+			start = i
+		case !word && start >= 0:
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(s)})
+	}
+	return spans
+}
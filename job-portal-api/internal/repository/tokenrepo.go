@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"job-portal-api/internal/models"
+	"time"
+)
+
+func (r *Repo) CreateRefreshToken(ctx context.Context, token models.RefreshToken) (models.RefreshToken, error) {
+	result := r.DB.WithContext(ctx).Create(&token)
+	if result.Error != nil {
+		return models.RefreshToken{}, dbError(result.Error, "refresh token")
+	}
+	return token, nil
+}
+
+func (r *Repo) ViewRefreshToken(ctx context.Context, tokenHash string) (models.RefreshToken, error) {
+	var token models.RefreshToken
+	result := r.DB.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token)
+	if result.Error != nil {
+		return models.RefreshToken{}, dbError(result.Error, "refresh token")
+	}
+	return token, nil
+}
+
+// RevokeRefreshToken marks the token as used. It reports false when the token had already
+// been revoked, which means it is being reused.
+func (r *Repo) RevokeRefreshToken(ctx context.Context, id uint) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return false, dbError(result.Error, "refresh token")
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *Repo) RevokeTokenFamily(ctx context.Context, familyID string) error {
+	result := r.DB.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now())
+	return dbError(result.Error, "refresh token")
+}
+
+func (r *Repo) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	result := r.DB.WithContext(ctx).Create(&models.RevokedToken{JTI: jti, ExpiresAt: expiresAt})
+	if result.Error != nil {
+		return dbError(result.Error, "access token")
+	}
+
+	// Tokens past their expiry are rejected anyway, so there is no need to remember them.
+	result = r.DB.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&models.RevokedToken{})
+	return dbError(result.Error, "access token")
+}
+
+func (r *Repo) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var count int64
+	result := r.DB.WithContext(ctx).Model(&models.RevokedToken{}).Where("jti = ?", jti).Count(&count)
+	if result.Error != nil {
+		return false, dbError(result.Error, "access token")
+	}
+	return count > 0, nil
+}
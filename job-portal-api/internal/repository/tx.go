@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/database"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// txAttempts is how many times WithTx runs a unit of work that keeps failing to serialize,
+// waiting txBackoff longer before every new attempt.
+const txAttempts = 3
+
+var txBackoff = 20 * time.Millisecond
+
+// WithTx runs fn in a serializable transaction. Called on the repositories of a
+// transaction it runs fn in a savepoint instead, so the writes of a failing fn are undone
+// while the ones of the enclosing unit of work stay.
+//
+// A transaction that fails to serialize with the ones running next to it is run again
+// from the start, fn included, up to txAttempts times. fn must therefore not have effects
+// outside the repositories. Only the outermost transaction is run again, a savepoint is
+// not worth retrying as the whole transaction has failed.
+func (r *Repo) WithTx(ctx context.Context, fn func(tx Repos) error) error {
+	run := func(tx *gorm.DB) error {
+		return fn(&Repo{DB: tx})
+	}
+	if _, ok := r.DB.Statement.ConnPool.(gorm.TxCommitter); ok {
+		return r.DB.WithContext(ctx).Transaction(run)
+	}
+
+	var err error
+	for attempt := 1; attempt <= txAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return dbError(ctx.Err(), "transaction")
+			case <-time.After(time.Duration(attempt-1) * txBackoff):
+			}
+		}
+		err = r.DB.WithContext(ctx).Transaction(run, &sql.TxOptions{Isolation: sql.LevelSerializable})
+		if !database.SerializationFailure(err) {
+			return dbError(err, "transaction")
+		}
+	}
+	return apperr.Wrap(apperr.ErrConflict, err, "the changes conflicted with others made at the same time, try again")
+}
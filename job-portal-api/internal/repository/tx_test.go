@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/models"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepo_WithTxRetriesSerializationFailures(t *testing.T) {
+	defer func(d time.Duration) { txBackoff = d }(txBackoff)
+	txBackoff = time.Millisecond
+	r := newSQLiteRepo(t)
+
+	attempts := 0
+	err := r.WithTx(ctx, func(tx Repos) error {
+		attempts++
+		if _, err := tx.CreateUser(ctx, models.User{Email: "ann@example.com"}); err != nil {
+			return err
+		}
+		if attempts == 1 {
+			return &pgconn.PgError{Code: "40001"}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	_, err = r.ViewUserByEmail(ctx, "ann@example.com")
+	assert.NoError(t, err, "the second attempt is kept")
+
+	attempts = 0
+	err = r.WithTx(ctx, func(tx Repos) error {
+		attempts++
+		return &pgconn.PgError{Code: "40P01"}
+	})
+	assert.ErrorIs(t, err, apperr.ErrConflict)
+	assert.Equal(t, txAttempts, attempts)
+}
+
+// TestRepo_WithTxRetriesStaleReads has a unit of work write after another one committed
+// since it read, which sqlite refuses like postgres refuses to serialize it.
+func TestRepo_WithTxRetriesStaleReads(t *testing.T) {
+	defer func(d time.Duration) { txBackoff = d }(txBackoff)
+	txBackoff = time.Millisecond
+	r := newSQLiteRepo(t)
+
+	read, committed := make(chan struct{}), make(chan error)
+	go func() {
+		<-read
+		committed <- r.WithTx(ctx, func(tx Repos) error {
+			_, err := tx.CreateUser(ctx, models.User{Email: "ann@example.com"})
+			return err
+		})
+	}()
+
+	attempts := 0
+	err := r.WithTx(ctx, func(tx Repos) error {
+		attempts++
+		_, err := tx.ViewUserByEmail(ctx, "ann@example.com")
+		if attempts == 1 {
+			assert.ErrorIs(t, err, apperr.ErrNotFound)
+			close(read)
+			require.NoError(t, <-committed)
+		}
+		_, err = tx.CreateUser(ctx, models.User{Email: "bob@example.com"})
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+}
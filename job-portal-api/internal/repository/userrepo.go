@@ -4,7 +4,11 @@ import (
 	"context"
 	"errors"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/auth"
 	"job-portal-api/internal/models"
 	"strconv"
 	"time"
@@ -12,33 +16,128 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// unknownUserHash is a bcrypt hash of the default cost that matches no password. Logins
+// with an unknown email are checked against it, so they take as long as a wrong password.
+var unknownUserHash = []byte("$2a$10$aLOy0b.ZMkTm0vAFv3Fsz.f6WX61eLGQzJtTqkkzY/6HcVQvU5H3m")
+
 func (r *Repo) CreateUser(ctx context.Context, UserDetails models.User) (models.User, error) {
-	result := r.DB.Create(&UserDetails)
+	result := r.DB.WithContext(ctx).Create(&UserDetails)
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return models.User{}, apperr.Wrap(apperr.ErrConflict, result.Error, "email already registered")
+	}
 	if result.Error != nil {
 		log.Info().Err(result.Error).Send()
-		return models.User{}, errors.New("could not create the user")
+		return models.User{}, dbError(result.Error, "user")
 	}
 	return UserDetails, nil
 }
-func (r *Repo) CheckEmail(ctx context.Context, email string, password string) (jwt.RegisteredClaims, error) {
+func (r *Repo) CheckEmail(ctx context.Context, email string, password string) (auth.Claims, error) {
 	var u models.User
-	tx := r.DB.Where("email = ?", email).First(&u)
+	tx := r.DB.WithContext(ctx).Where("email = ?", email).First(&u)
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		// An unknown email is reported like a wrong password, and takes as long to check, so
+		// logins cannot probe for accounts.
+		_ = bcrypt.CompareHashAndPassword(unknownUserHash, []byte(password))
+		return auth.Claims{}, apperr.Wrap(apperr.ErrUnauthorized, tx.Error, "invalid email or password")
+	}
 	if tx.Error != nil {
-		return jwt.RegisteredClaims{}, tx.Error
+		return auth.Claims{}, dbError(tx.Error, "user")
 	}
 
 	// We check if the provided password matches the hashed password in the database.
 	err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password))
 	if err != nil {
-		return jwt.RegisteredClaims{}, err
+		return auth.Claims{}, apperr.Wrap(apperr.ErrUnauthorized, err, "invalid email or password")
+	}
+
+	return r.claims(ctx, u)
+}
+
+func (r *Repo) ViewUserClaims(ctx context.Context, uid uint) (auth.Claims, error) {
+	var u models.User
+	tx := r.DB.WithContext(ctx).First(&u, uid)
+	if tx.Error != nil {
+		return auth.Claims{}, dbError(tx.Error, "user")
+	}
+	return r.claims(ctx, u)
+}
+
+// claims builds the access token claims for u, including its role in every company it belongs to.
+func (r *Repo) claims(ctx context.Context, u models.User) (auth.Claims, error) {
+	var members []models.CompanyMember
+	tx := r.DB.WithContext(ctx).Where("user_id = ?", u.ID).Find(&members)
+	if tx.Error != nil {
+		return auth.Claims{}, dbError(tx.Error, "user")
+	}
+	companyRoles := make(map[uint]string, len(members))
+	for _, m := range members {
+		companyRoles[m.CompanyID] = m.Role
+	}
+	return newClaims(u, companyRoles), nil
+}
+
+// newClaims returns the claims of an access token of u, companyRoles maps the companies u
+// belongs to to its role in them.
+func newClaims(u models.User, companyRoles map[uint]string) auth.Claims {
+	return auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    "jobportal project",
+			Subject:   strconv.FormatUint(uint64(u.ID), 10),
+			Audience:  jwt.ClaimStrings{"companies"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+		Role:         u.Role,
+		CompanyRoles: companyRoles,
 	}
-	c := jwt.RegisteredClaims{
-		Issuer:    "jobportal project",
-		Subject:   strconv.FormatUint(uint64(u.ID), 10),
-		Audience:  jwt.ClaimStrings{"companies"},
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
+}
+
+func (r *Repo) UpdateUserRole(ctx context.Context, uid uint, role string) (models.User, error) {
+	var u models.User
+	tx := r.DB.WithContext(ctx).First(&u, uid)
+	if tx.Error != nil {
+		return models.User{}, dbError(tx.Error, "user")
 	}
-	return c, nil
+	tx = r.DB.WithContext(ctx).Model(&u).Update("role", role)
+	if tx.Error != nil {
+		return models.User{}, dbError(tx.Error, "user")
+	}
+	return u, nil
+}
+
+func (r *Repo) ViewUserByEmail(ctx context.Context, email string) (models.User, error) {
+	var u models.User
+	tx := r.DB.WithContext(ctx).Where("email = ?", email).First(&u)
+	if tx.Error != nil {
+		return models.User{}, dbError(tx.Error, "user")
+	}
+	return u, nil
+}
+
+func (r *Repo) ViewUserById(ctx context.Context, uid uint) (models.User, error) {
+	var u models.User
+	tx := r.DB.WithContext(ctx).First(&u, uid)
+	if tx.Error != nil {
+		return models.User{}, dbError(tx.Error, "user")
+	}
+	return u, nil
+}
 
+// UpdatePassword replaces the password hash of a user and revokes its refresh tokens, so
+// sessions opened with the old password end when their access token expires.
+func (r *Repo) UpdatePassword(ctx context.Context, uid uint, passwordHash string) error {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).Where("id = ?", uid).Update("password_hash", passwordHash)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", uid).
+			Update("revoked_at", time.Now()).Error
+	})
+	return dbError(err, "user")
 }
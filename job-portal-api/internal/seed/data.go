@@ -0,0 +1,114 @@
+package seed
+
+// The word lists the synthetic data is drawn from.
+
+var firstNames = []string{
+	"Aarav", "Aisha", "Alex", "Amelia", "Ananya", "Ben", "Carlos", "Chen", "Chloe", "Daniel",
+	"Elena", "Emma", "Farah", "Felix", "Hana", "Hugo", "Isabel", "Ivan", "Jonas", "Julia",
+	"Kenji", "Lars", "Layla", "Leo", "Lucia", "Maya", "Mei", "Noah", "Olivia", "Omar",
+	"Priya", "Rahul", "Sara", "Sofia", "Tariq", "Tom", "Valentina", "Wei", "Yusuf", "Zoe",
+}
+
+var lastNames = []string{
+	"Ahmed", "Andersen", "Bauer", "Brown", "Chen", "Costa", "Dubois", "Fischer", "Garcia", "Gupta",
+	"Hansen", "Ito", "Jansen", "Khan", "Kim", "Kowalski", "Lee", "Lopez", "Martin", "Meyer",
+	"Moreau", "Nakamura", "Nguyen", "Novak", "Okafor", "Patel", "Rossi", "Santos", "Schmidt", "Sharma",
+	"Silva", "Singh", "Smith", "Tanaka", "Wang", "Weber", "Williams", "Wilson", "Yilmaz", "Zhang",
+}
+
+var companyWords = []string{
+	"Acme", "Apex", "Blue", "Bright", "Cedar", "Cloud", "Copper", "Delta", "Ember", "Falcon",
+	"Granite", "Harbor", "Iron", "Juniper", "Lumen", "Maple", "Nimbus", "North", "Orbit", "Pioneer",
+	"Quartz", "River", "Silver", "Summit", "Tidal", "Vertex", "Willow", "Zenith",
+}
+
+var companySuffixes = []string{
+	"Labs", "Systems", "Software", "Analytics", "Health", "Logistics", "Finance", "Robotics",
+	"Media", "Energy", "Works", "Technologies",
+}
+
+var streets = []string{
+	"Main Street", "Market Street", "Station Road", "Park Avenue", "High Street", "Harbour Road",
+	"Mill Lane", "Church Street", "King Street", "Queen Street",
+}
+
+type city struct {
+	name     string
+	country  string
+	currency string
+	// salary is the typical yearly salary of a mid level engineer in currency.
+	salary int
+}
+
+var cities = []city{
+	{"Amsterdam", "NL", "EUR", 70000},
+	{"Austin", "US", "USD", 140000},
+	{"Bangalore", "IN", "INR", 2500000},
+	{"Berlin", "DE", "EUR", 70000},
+	{"Dublin", "IE", "EUR", 75000},
+	{"Lisbon", "PT", "EUR", 45000},
+	{"London", "GB", "GBP", 75000},
+	{"New York", "US", "USD", 160000},
+	{"Paris", "FR", "EUR", 60000},
+	{"San Francisco", "US", "USD", 180000},
+	{"Singapore", "SG", "SGD", 110000},
+	{"Stockholm", "SE", "SEK", 650000},
+	{"Sydney", "AU", "AUD", 140000},
+	{"Tokyo", "JP", "JPY", 9000000},
+	{"Toronto", "CA", "CAD", 120000},
+	{"Warsaw", "PL", "PLN", 250000},
+}
+
+type jobFamily struct {
+	titles []string
+	skills []string
+	blurb  string
+}
+
+var jobFamilies = []jobFamily{
+	{
+		titles: []string{"Backend Engineer", "Platform Engineer", "API Developer"},
+		skills: []string{"go", "postgres", "kubernetes", "grpc", "redis", "kafka", "docker"},
+		blurb:  "You will design and run the services and databases behind our product",
+	},
+	{
+		titles: []string{"Frontend Engineer", "UI Developer", "Web Developer"},
+		skills: []string{"typescript", "react", "css", "accessibility", "graphql", "vite"},
+		blurb:  "You will build the pages our customers use every day and keep them fast",
+	},
+	{
+		titles: []string{"Data Engineer", "Analytics Engineer", "Data Scientist"},
+		skills: []string{"python", "sql", "spark", "airflow", "dbt", "statistics"},
+		blurb:  "You will turn raw events into reliable datasets and the insights built on them",
+	},
+	{
+		titles: []string{"Site Reliability Engineer", "DevOps Engineer", "Cloud Engineer"},
+		skills: []string{"terraform", "aws", "linux", "prometheus", "kubernetes", "bash"},
+		blurb:  "You will keep our infrastructure healthy, observable and cheap to run",
+	},
+	{
+		titles: []string{"Mobile Engineer", "iOS Developer", "Android Developer"},
+		skills: []string{"swift", "kotlin", "flutter", "ci", "testing"},
+		blurb:  "You will ship the apps our users carry with them",
+	},
+	{
+		titles: []string{"Product Designer", "UX Researcher"},
+		skills: []string{"figma", "prototyping", "user research", "design systems"},
+		blurb:  "You will shape how people experience our product from first sketch to launch",
+	},
+}
+
+var perks = []string{
+	"We offer flexible hours and a yearly learning budget.",
+	"The team meets in person once a quarter.",
+	"You will get a laptop of your choice and a home office allowance.",
+	"We work in small teams that own their services end to end.",
+	"Parental leave is six months, fully paid.",
+}
+
+var coverLetters = []string{
+	"I have followed your product for years and would love to help build it.",
+	"My last role was very close to this one and I am ready for the next step.",
+	"I am excited by the problems your team is solving.",
+	"",
+}
@@ -0,0 +1,270 @@
+package seed
+
+import (
+	"fmt"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/services"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Kinds of rows, each draws from its own random stream.
+const (
+	kindUser = iota + 1
+	kindCompany
+	kindJob
+	kindApplication
+	kindFirstJob
+)
+
+const day = 24 * time.Hour
+
+// rng is a splitmix64 generator. It is tiny and cheap to seed, so every row gets its own.
+type rng struct{ state uint64 }
+
+func newRNG(seed int64, kind, i int) *rng {
+	r := &rng{state: uint64(seed)}
+	r.state = r.next() ^ uint64(kind)
+	r.state = r.next() ^ uint64(i)
+	return r
+}
+
+func (r *rng) next() uint64 {
+	r.state += 0x9e3779b97f4a7c15
+	z := r.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// intn returns a number in [0, n).
+func (r *rng) intn(n int) int {
+	return int(r.next() % uint64(n))
+}
+
+// between returns a number in [lo, hi].
+func (r *rng) between(lo, hi int) int {
+	return lo + r.intn(hi-lo+1)
+}
+
+// chance reports true with probability p.
+func (r *rng) chance(p float64) bool {
+	return float64(r.next()>>11)/(1<<53) < p
+}
+
+func (r *rng) pick(xs []string) string {
+	return xs[r.intn(len(xs))]
+}
+
+// weighted picks one of choices with a probability proportional to its weight.
+func (r *rng) weighted(choices []string, weights []int) string {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	n := r.intn(total)
+	for i, w := range weights {
+		if n < w {
+			return choices[i]
+		}
+		n -= w
+	}
+	return choices[len(choices)-1]
+}
+
+// generator derives the rows of a data set from its options. Ids start at 1 in every
+// table: the first Companies users are the employers owning the company with their id,
+// the candidates follow them.
+type generator struct {
+	opts     Options
+	now      time.Time
+	pipeline []models.PipelineStage
+}
+
+func newGenerator(opts Options) generator {
+	now := opts.Now
+	if now.IsZero() {
+		now = Epoch
+	}
+	return generator{opts: opts, now: now, pipeline: services.DefaultPipeline(0)}
+}
+
+func (g generator) users() int {
+	return g.opts.Companies + g.opts.Candidates
+}
+
+func (g generator) rng(kind, i int) *rng {
+	return newRNG(g.opts.Seed, kind, i)
+}
+
+func model(id int, created time.Time) gorm.Model {
+	return gorm.Model{ID: uint(id), CreatedAt: created, UpdatedAt: created}
+}
+
+func (g generator) user(i int) models.User {
+	r := g.rng(kindUser, i)
+	first, last := r.pick(firstNames), r.pick(lastNames)
+	role := models.RoleCandidate
+	if i < g.opts.Companies {
+		role = models.RoleEmployer
+	}
+	created := g.now.Add(-time.Duration(r.between(1, 730)) * day)
+	return models.User{
+		Model: model(i+1, created),
+		Name:  first + " " + last,
+		Email: fmt.Sprintf("%s.%s.%d@example.com", strings.ToLower(first), strings.ToLower(last), i+1),
+		Role:  role,
+	}
+}
+
+func (g generator) company(i int) models.Companies {
+	r := g.rng(kindCompany, i)
+	c := cities[r.intn(len(cities))]
+	created := g.now.Add(-time.Duration(r.between(30, 1500)) * day)
+	return models.Companies{
+		Model:       model(i+1, created),
+		CompanyName: r.pick(companyWords) + " " + r.pick(companySuffixes),
+		FoundedYear: r.between(1950, g.now.Year()-1),
+		Location:    c.name,
+		UserId:      uint(i + 1),
+		Address:     fmt.Sprintf("%d %s, %s", r.between(1, 250), r.pick(streets), c.name),
+	}
+}
+
+func (g generator) member(i int) models.CompanyMember {
+	company := g.company(i)
+	return models.CompanyMember{
+		Model:     model(i+1, company.CreatedAt),
+		CompanyID: company.ID,
+		UserID:    company.UserId,
+		Role:      models.CompanyRoleOwner,
+	}
+}
+
+var seniorityPay = map[string]float64{
+	models.SeniorityIntern: 0.3,
+	models.SeniorityJunior: 0.7,
+	models.SeniorityMid:    1,
+	models.SenioritySenior: 1.35,
+	models.SeniorityLead:   1.6,
+}
+
+func (g generator) job(i int) models.Job {
+	r := g.rng(kindJob, i)
+	companyID := r.intn(g.opts.Companies) + 1
+	family := jobFamilies[r.intn(len(jobFamilies))]
+	title := r.pick(family.titles)
+
+	seniority := r.weighted(
+		[]string{models.SeniorityIntern, models.SeniorityJunior, models.SeniorityMid, models.SenioritySenior, models.SeniorityLead},
+		[]int{5, 20, 35, 30, 10})
+	employment := r.weighted(
+		[]string{models.EmploymentFullTime, models.EmploymentPartTime, models.EmploymentContract},
+		[]int{80, 8, 12})
+	switch seniority {
+	case models.SeniorityIntern:
+		title += " Intern"
+		employment = models.EmploymentInternship
+	case models.SeniorityJunior:
+		title = "Junior " + title
+	case models.SenioritySenior:
+		title = "Senior " + title
+	case models.SeniorityLead:
+		title = "Lead " + title
+	}
+
+	// Most jobs are in the city of their company, some elsewhere.
+	c := cities[r.intn(len(cities))]
+	if r.chance(0.7) {
+		company := g.company(companyID - 1)
+		for _, cc := range cities {
+			if cc.name == company.Location {
+				c = cc
+			}
+		}
+	}
+
+	created := g.now.Add(-time.Duration(r.between(0, 180))*day - time.Duration(r.intn(1440))*time.Minute)
+	job := models.Job{
+		Model:          model(i+1, created),
+		Title:          title,
+		Description:    fmt.Sprintf("%s. %s", family.blurb, r.pick(perks)),
+		CompanyID:      uint(companyID),
+		Status:         models.JobStatusOpen,
+		City:           c.name,
+		Country:        c.country,
+		RemotePolicy:   r.weighted([]string{models.RemotePolicyOnsite, models.RemotePolicyHybrid, models.RemotePolicyRemote}, []int{40, 35, 25}),
+		EmploymentType: employment,
+		Seniority:      seniority,
+	}
+	if r.chance(0.1) {
+		job.Status = models.JobStatusClosed
+	}
+	if r.chance(0.7) {
+		// Salaries are rounded to a thousandth of the typical salary of the city.
+		step := float64(c.salary) / 1000
+		base := float64(c.salary) * seniorityPay[seniority] * (0.85 + 0.3*float64(r.intn(1000))/1000)
+		job.SalaryMin = int(math.Round(base*0.9/step) * step)
+		job.SalaryMax = int(math.Round(base*1.15/step) * step)
+		job.SalaryCurrency = c.currency
+		job.SalaryPeriod = models.SalaryPeriodYear
+	}
+	if r.chance(0.4) {
+		expires := created.Add(time.Duration(r.between(30, 120)) * day)
+		job.ExpiresAt = &expires
+	}
+
+	// Skills are drawn without repeats, the first ones are required.
+	skills := append([]string{}, family.skills...)
+	n := r.between(2, min(5, len(skills)))
+	for k := 0; k < n; k++ {
+		j := k + r.intn(len(skills)-k)
+		skills[k], skills[j] = skills[j], skills[k]
+		job.Skills = append(job.Skills, models.JobSkill{JobID: job.ID, Name: skills[k], Required: k < 2})
+	}
+	return job
+}
+
+// application i is the (i / Candidates)th application of candidate i % Candidates. The
+// applications of a candidate go to consecutive jobs from a random start, so no candidate
+// applies to the same job twice.
+func (g generator) application(i int) models.Application {
+	candidate := i % g.opts.Candidates
+	nth := i / g.opts.Candidates
+	first := g.rng(kindFirstJob, candidate).intn(g.opts.Jobs)
+	job := g.job((first + nth) % g.opts.Jobs)
+	userID := g.opts.Companies + candidate + 1
+
+	r := g.rng(kindApplication, i)
+	// Most applications are still early in the pipeline.
+	stage := g.pipeline[r.intn(len(g.pipeline))]
+	if r.chance(0.5) {
+		stage = g.pipeline[0]
+	}
+	status := models.ApplicationInReview
+	switch {
+	case stage.Outcome == models.OutcomeHired:
+		status = models.ApplicationHired
+	case stage.Outcome == models.OutcomeRejected:
+		status = models.ApplicationRejected
+	case stage.Position == 0:
+		status = models.ApplicationSubmitted
+		if r.chance(0.05) {
+			status = models.ApplicationWithdrawn
+		}
+	}
+
+	created := job.CreatedAt.Add(time.Duration(r.between(1, 30*24)) * time.Hour)
+	return models.Application{
+		Model:       model(i+1, created),
+		JobID:       job.ID,
+		UserID:      uint(userID),
+		CoverLetter: r.pick(coverLetters),
+		ResumeURL:   fmt.Sprintf("https://resumes.example.com/%d.pdf", userID),
+		Status:      status,
+		Stage:       stage.Name,
+	}
+}
@@ -0,0 +1,234 @@
+// Package seed fills a database with plausible synthetic users, companies, jobs and
+// applications for local development and load tests.
+//
+// The data is a pure function of the Options: every row is derived from the seed and its
+// own index, so the same options always give the same rows whatever the batch size, and
+// Generate returns the very rows Seed inserts.
+package seed
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"job-portal-api/internal/database"
+	"job-portal-api/internal/models"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Password is the password of every seeded user.
+const Password = "Passw0rd123"
+
+// Epoch is the default point in time the data is generated around.
+var Epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Options tells how much data to generate and from which seed.
+type Options struct {
+	Seed int64
+	// Companies is also the number of employers, every company has its own owner.
+	Companies    int
+	Candidates   int
+	Jobs         int
+	Applications int
+	// BatchSize is the number of rows sent in a single insert.
+	BatchSize int
+	// Now is the time the data is generated around, Epoch when zero.
+	Now time.Time
+}
+
+// DefaultOptions returns a volume small enough to seed a laptop in a moment.
+func DefaultOptions() Options {
+	return Options{
+		Seed:         1,
+		Companies:    10,
+		Candidates:   50,
+		Jobs:         100,
+		Applications: 200,
+		BatchSize:    1000,
+	}
+}
+
+// Validate reports options that cannot be generated.
+func (o Options) Validate() error {
+	var errs []error
+	if o.Companies < 0 || o.Candidates < 0 || o.Jobs < 0 || o.Applications < 0 {
+		errs = append(errs, errors.New("counts must not be negative"))
+	}
+	if o.BatchSize < 1 {
+		errs = append(errs, errors.New("batch size must be positive"))
+	}
+	if o.Jobs > 0 && o.Companies == 0 {
+		errs = append(errs, errors.New("jobs need at least one company"))
+	}
+	// Every candidate applies to a job at most once.
+	if o.Applications > 0 && int64(o.Applications) > int64(o.Jobs)*int64(o.Candidates) {
+		errs = append(errs, fmt.Errorf("%d applications need more than %d jobs and %d candidates",
+			o.Applications, o.Jobs, o.Candidates))
+	}
+	return errors.Join(errs...)
+}
+
+// Fixtures is a generated data set. Users sign in with Password.
+type Fixtures struct {
+	Users        []models.User          `json:"users"`
+	Companies    []models.Companies     `json:"companies"`
+	Members      []models.CompanyMember `json:"members"`
+	Jobs         []models.Job           `json:"jobs"`
+	Applications []models.Application   `json:"applications"`
+}
+
+// Generate returns the rows Seed would insert for opts.
+func Generate(opts Options) (Fixtures, error) {
+	err := opts.Validate()
+	if err != nil {
+		return Fixtures{}, err
+	}
+	g := newGenerator(opts)
+	return Fixtures{
+		Users:        generate(g.users(), g.user),
+		Companies:    generate(opts.Companies, g.company),
+		Members:      generate(opts.Companies, g.member),
+		Jobs:         generate(opts.Jobs, g.job),
+		Applications: generate(opts.Applications, g.application),
+	}, nil
+}
+
+func generate[T any](n int, gen func(i int) T) []T {
+	rows := make([]T, 0, n)
+	for i := 0; i < n; i++ {
+		rows = append(rows, gen(i))
+	}
+	return rows
+}
+
+// Dump writes the data described by opts to w as the json encoding of Fixtures. Rows are
+// written as they are generated, so large volumes can be dumped without holding them.
+func Dump(w io.Writer, opts Options) error {
+	err := opts.Validate()
+	if err != nil {
+		return err
+	}
+	g := newGenerator(opts)
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	sections := []struct {
+		name string
+		n    int
+		row  func(i int) interface{}
+	}{
+		{"users", g.users(), func(i int) interface{} { return g.user(i) }},
+		{"companies", opts.Companies, func(i int) interface{} { return g.company(i) }},
+		{"members", opts.Companies, func(i int) interface{} { return g.member(i) }},
+		{"jobs", opts.Jobs, func(i int) interface{} { return g.job(i) }},
+		{"applications", opts.Applications, func(i int) interface{} { return g.application(i) }},
+	}
+	for k, sec := range sections {
+		sep := ","
+		if k == 0 {
+			sep = "{"
+		}
+		fmt.Fprintf(bw, "%s\n%q: [", sep, sec.name)
+		for i := 0; i < sec.n; i++ {
+			if i > 0 {
+				bw.WriteByte(',')
+			}
+			err = enc.Encode(sec.row(i))
+			if err != nil {
+				return err
+			}
+		}
+		bw.WriteString("]")
+	}
+	bw.WriteString("\n}\n")
+	return bw.Flush()
+}
+
+// Seed inserts the data described by opts in a single transaction. The rows carry the ids
+// Generate gives them, so the tables must be empty.
+func Seed(ctx context.Context, db *gorm.DB, opts Options) error {
+	err := opts.Validate()
+	if err != nil {
+		return err
+	}
+	g := newGenerator(opts)
+
+	// Hashing is slow on purpose, so every user shares one hash.
+	hash, err := bcrypt.GenerateFromPassword([]byte(Password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("generating password hash: %w", err)
+	}
+	user := func(i int) models.User {
+		u := g.user(i)
+		u.PasswordHash = string(hash)
+		return u
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var users int64
+		err := tx.Model(&models.User{}).Count(&users).Error
+		if err != nil {
+			return err
+		}
+		if users > 0 {
+			return errors.New("seed needs an empty database, users already has rows")
+		}
+
+		err = insert(tx, opts.BatchSize, g.users(), user)
+		if err == nil {
+			err = insert(tx, opts.BatchSize, opts.Companies, g.company)
+		}
+		if err == nil {
+			err = insert(tx, opts.BatchSize, opts.Companies, g.member)
+		}
+		if err == nil {
+			err = insert(tx, opts.BatchSize, opts.Jobs, g.job)
+		}
+		if err == nil {
+			err = insert(tx, opts.BatchSize, opts.Applications, g.application)
+		}
+		if err != nil {
+			return err
+		}
+		return resetSequences(tx, "users", "companies", "company_members", "jobs", "applications")
+	})
+}
+
+// insert generates and inserts n rows, holding a single batch in memory at a time.
+func insert[T any](tx *gorm.DB, batchSize, n int, gen func(i int) T) error {
+	rows := make([]T, 0, min(batchSize, n))
+	for start := 0; start < n; start += batchSize {
+		rows = rows[:0]
+		for i := start; i < min(start+batchSize, n); i++ {
+			rows = append(rows, gen(i))
+		}
+		err := tx.CreateInBatches(&rows, batchSize).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resetSequences moves the id sequences past the ids that were inserted explicitly, so
+// the rows the api creates afterwards do not collide with them. Sqlite keeps track of the
+// largest id by itself.
+func resetSequences(tx *gorm.DB, tables ...string) error {
+	if tx.Dialector.Name() != database.DialectPostgres {
+		return nil
+	}
+	for _, table := range tables {
+		err := tx.Exec(fmt.Sprintf(
+			`SELECT setval(pg_get_serial_sequence('%[1]s', 'id'), COALESCE((SELECT MAX(id) FROM %[1]s), 0) + 1, false)`,
+			table)).Error
+		if err != nil {
+			return fmt.Errorf("resetting the id sequence of %s: %w", table, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package seed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/database"
+	"job-portal-api/internal/models"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/logger"
+)
+
+func TestGenerate_isDeterministic(t *testing.T) {
+	opts := DefaultOptions()
+	a, err := Generate(opts)
+	require.NoError(t, err)
+	b, err := Generate(opts)
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+
+	opts.Seed++
+	c, err := Generate(opts)
+	require.NoError(t, err)
+	assert.NotEqual(t, a.Jobs, c.Jobs)
+}
+
+func TestGenerate_rowsDoNotDependOnVolume(t *testing.T) {
+	small := DefaultOptions()
+	large := small
+	large.Companies *= 2
+	large.Candidates *= 2
+	a, err := Generate(small)
+	require.NoError(t, err)
+	b, err := Generate(large)
+	require.NoError(t, err)
+	assert.Equal(t, a.Companies, b.Companies[:len(a.Companies)])
+}
+
+func TestGenerate_references(t *testing.T) {
+	opts := DefaultOptions()
+	f, err := Generate(opts)
+	require.NoError(t, err)
+	require.Len(t, f.Users, opts.Companies+opts.Candidates)
+	require.Len(t, f.Jobs, opts.Jobs)
+	require.Len(t, f.Applications, opts.Applications)
+
+	users := map[uint]models.User{}
+	emails := map[string]bool{}
+	for _, u := range f.Users {
+		users[u.ID] = u
+		assert.False(t, emails[u.Email], "email %s is used twice", u.Email)
+		emails[u.Email] = true
+	}
+	for i, c := range f.Companies {
+		assert.Equal(t, models.RoleEmployer, users[c.UserId].Role)
+		assert.Equal(t, c.ID, f.Members[i].CompanyID)
+		assert.Equal(t, c.UserId, f.Members[i].UserID)
+	}
+	for _, j := range f.Jobs {
+		assert.True(t, j.CompanyID >= 1 && int(j.CompanyID) <= opts.Companies, "job %d has company %d", j.ID, j.CompanyID)
+		assert.LessOrEqual(t, j.SalaryMin, j.SalaryMax)
+		assert.NotEmpty(t, j.Skills)
+	}
+	applied := map[[2]uint]bool{}
+	for _, a := range f.Applications {
+		assert.Equal(t, models.RoleCandidate, users[a.UserID].Role)
+		assert.True(t, a.JobID >= 1 && int(a.JobID) <= opts.Jobs)
+		pair := [2]uint{a.JobID, a.UserID}
+		assert.False(t, applied[pair], "user %d applies to job %d twice", a.UserID, a.JobID)
+		applied[pair] = true
+	}
+}
+
+func TestOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(o *Options)
+		wantErr bool
+	}{
+		{name: "defaults", change: func(o *Options) {}},
+		{name: "nothing", change: func(o *Options) { *o = Options{BatchSize: 1} }},
+		{name: "no batch size", change: func(o *Options) { o.BatchSize = 0 }, wantErr: true},
+		{name: "jobs without companies", change: func(o *Options) { o.Companies = 0 }, wantErr: true},
+		{name: "negative count", change: func(o *Options) { o.Candidates = -1 }, wantErr: true},
+		{name: "more applications than pairs", change: func(o *Options) { o.Jobs, o.Candidates = 2, 3 }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := DefaultOptions()
+			tt.change(&o)
+			err := o.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDump(t *testing.T) {
+	opts := Options{Seed: 7, Companies: 2, Candidates: 3, Jobs: 4, Applications: 5, BatchSize: 1}
+	want, err := Generate(opts)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, Dump(&buf, opts))
+	var got Fixtures
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	// Round tripping drops the skill job ids and the monotonic clock readings.
+	wantJSON, err := json.Marshal(want)
+	require.NoError(t, err)
+	gotJSON, err := json.Marshal(got)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(wantJSON), string(gotJSON))
+	assert.NotContains(t, buf.String(), "password")
+}
+
+func TestSeed_sqlite(t *testing.T) {
+	db, err := database.Open(config.DBConfig{Driver: config.DriverSQLite, Path: filepath.Join(t.TempDir(), "seed.db")})
+	require.NoError(t, err)
+	db.Logger = logger.Discard
+	migrator, err := database.NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	opts := Options{Seed: 7, Companies: 2, Candidates: 3, Jobs: 4, Applications: 5, BatchSize: 2}
+	require.NoError(t, Seed(context.Background(), db, opts))
+	var jobs int64
+	require.NoError(t, db.Model(&models.Job{}).Count(&jobs).Error)
+	assert.Equal(t, int64(opts.Jobs), jobs)
+
+	// New rows are numbered after the seeded ones.
+	u := models.User{Email: "new@example.com", Role: models.RoleCandidate}
+	require.NoError(t, db.Create(&u).Error)
+	assert.Equal(t, uint(opts.Companies+opts.Candidates+1), u.ID)
+	assert.Error(t, Seed(context.Background(), db, opts), "seeding twice")
+}
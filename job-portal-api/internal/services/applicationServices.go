@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/models"
+	"strconv"
+	"time"
+)
+
+// Apply submits the application of a candidate to an open job that has not expired.
+func (s *ApplicationStore) Apply(ctx context.Context, jobID uint64, na models.NewApplication, userID string) (models.Application, error) {
+	uid, err := strconv.ParseUint(userID, 10, 64)
+	if err != nil {
+		return models.Application{}, fmt.Errorf("user id %q: %w", userID, apperr.ErrUnauthorized)
+	}
+
+	job, err := s.Jobs.ViewJobDetailsById(ctx, jobID)
+	if errors.Is(err, apperr.ErrNotFound) {
+		return models.Application{}, fmt.Errorf("job %d: %w", jobID, apperr.ErrNotFound)
+	}
+	if err != nil {
+		return models.Application{}, err
+	}
+	if job.Status != models.JobStatusOpen || (job.ExpiresAt != nil && job.ExpiresAt.Before(time.Now())) {
+		return models.Application{}, fmt.Errorf("job %d is not open for applications: %w", jobID, apperr.ErrValidation)
+	}
+
+	p, err := s.companyPipeline(ctx, job.CompanyID)
+	if err != nil {
+		return models.Application{}, err
+	}
+
+	_, err = s.ApplicationRepo.ViewApplicationByJobAndUser(ctx, jobID, uint(uid))
+	if err == nil {
+		return models.Application{}, fmt.Errorf("already applied to job %d: %w", jobID, apperr.ErrConflict)
+	}
+	if !errors.Is(err, apperr.ErrNotFound) {
+		return models.Application{}, err
+	}
+
+	app, err := s.ApplicationRepo.CreateApplication(ctx, models.Application{
+		JobID:       uint(jobID),
+		UserID:      uint(uid),
+		CoverLetter: na.CoverLetter,
+		ResumeURL:   na.ResumeURL,
+		Status:      models.ApplicationSubmitted,
+		Stage:       p.initial(),
+	})
+	if err != nil {
+		return models.Application{}, err
+	}
+	return app, nil
+}
+
+func (s *ApplicationStore) MyApplications(ctx context.Context, p models.PageRequest, userID string) ([]models.Application, models.PageInfo, error) {
+	uid, err := strconv.ParseUint(userID, 10, 64)
+	if err != nil {
+		return nil, models.PageInfo{}, fmt.Errorf("user id %q: %w", userID, apperr.ErrUnauthorized)
+	}
+	return s.ApplicationRepo.ListApplicationsByUser(ctx, uint(uid), p)
+}
+
+// JobApplications lists the applications to a job for any member of the company that posted it.
+func (s *ApplicationStore) JobApplications(ctx context.Context, jobID uint64, p models.PageRequest, userID string) ([]models.Application, models.PageInfo, error) {
+	job, err := s.Jobs.ViewJobDetailsById(ctx, jobID)
+	if errors.Is(err, apperr.ErrNotFound) {
+		return nil, models.PageInfo{}, fmt.Errorf("job %d: %w", jobID, apperr.ErrNotFound)
+	}
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+	err = authorizeCompany(ctx, s.Companies, job.CompanyID, userID, models.CompanyRoleOwner, models.CompanyRoleRecruiter, models.CompanyRoleViewer)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+	return s.ApplicationRepo.ListApplicationsByJob(ctx, jobID, p)
+}
+
+// WithdrawApplication lets a candidate take back an application that has not been decided on.
+func (s *ApplicationStore) WithdrawApplication(ctx context.Context, applicationID uint, userID string) (models.Application, error) {
+	app, err := s.ApplicationRepo.ViewApplication(ctx, applicationID)
+	if errors.Is(err, apperr.ErrNotFound) {
+		return models.Application{}, fmt.Errorf("application %d: %w", applicationID, apperr.ErrNotFound)
+	}
+	if err != nil {
+		return models.Application{}, err
+	}
+	if strconv.FormatUint(uint64(app.UserID), 10) != userID {
+		// Applications of other users are not revealed.
+		return models.Application{}, fmt.Errorf("application %d: %w", applicationID, apperr.ErrNotFound)
+	}
+	if app.Status != models.ApplicationSubmitted && app.Status != models.ApplicationInReview {
+		return models.Application{}, fmt.Errorf("application %d is %s: %w", applicationID, app.Status, apperr.ErrValidation)
+	}
+	return s.ApplicationRepo.UpdateApplicationStatus(ctx, applicationID, models.ApplicationWithdrawn)
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestApplicationStore_Apply(t *testing.T) {
+	na := models.NewApplication{CoverLetter: "hire me", ResumeURL: "https://cv.example.com/me.pdf"}
+	openJob := models.Job{CompanyID: 1, Status: models.JobStatusOpen}
+	expired := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name      string
+		setup     func(jobs *repository.MockJobStore, ar *repository.MockApplicationRepo)
+		want      models.Application
+		wantErr   bool
+		wantErrIs error
+	}{
+		{
+			name: "job not found",
+			setup: func(jobs *repository.MockJobStore, ar *repository.MockApplicationRepo) {
+				jobs.EXPECT().ViewJobDetailsById(gomock.Any(), uint64(5)).Return(models.Job{}, apperr.ErrNotFound)
+			},
+			wantErr:   true,
+			wantErrIs: apperr.ErrNotFound,
+		},
+		{
+			name: "closed job",
+			setup: func(jobs *repository.MockJobStore, ar *repository.MockApplicationRepo) {
+				jobs.EXPECT().ViewJobDetailsById(gomock.Any(), uint64(5)).Return(models.Job{Status: models.JobStatusClosed}, nil)
+			},
+			wantErr:   true,
+			wantErrIs: apperr.ErrValidation,
+		},
+		{
+			name: "expired job",
+			setup: func(jobs *repository.MockJobStore, ar *repository.MockApplicationRepo) {
+				jobs.EXPECT().ViewJobDetailsById(gomock.Any(), uint64(5)).Return(models.Job{Status: models.JobStatusOpen, ExpiresAt: &expired}, nil)
+			},
+			wantErr:   true,
+			wantErrIs: apperr.ErrValidation,
+		},
+		{
+			name: "already applied",
+			setup: func(jobs *repository.MockJobStore, ar *repository.MockApplicationRepo) {
+				jobs.EXPECT().ViewJobDetailsById(gomock.Any(), uint64(5)).Return(openJob, nil)
+				ar.EXPECT().ViewPipeline(gomock.Any(), uint(1)).Return(nil, nil)
+				ar.EXPECT().ViewApplicationByJobAndUser(gomock.Any(), uint64(5), uint(2)).
+					Return(models.Application{Status: models.ApplicationWithdrawn}, nil)
+			},
+			wantErr:   true,
+			wantErrIs: apperr.ErrConflict,
+		},
+		{
+			name: "success",
+			setup: func(jobs *repository.MockJobStore, ar *repository.MockApplicationRepo) {
+				jobs.EXPECT().ViewJobDetailsById(gomock.Any(), uint64(5)).Return(openJob, nil)
+				ar.EXPECT().ViewPipeline(gomock.Any(), uint(1)).Return(nil, nil)
+				ar.EXPECT().ViewApplicationByJobAndUser(gomock.Any(), uint64(5), uint(2)).Return(models.Application{}, apperr.ErrNotFound)
+				app := models.Application{JobID: 5, UserID: 2, CoverLetter: "hire me", ResumeURL: "https://cv.example.com/me.pdf", Status: models.ApplicationSubmitted, Stage: "applied"}
+				ar.EXPECT().CreateApplication(gomock.Any(), app).Return(app, nil)
+			},
+			want: models.Application{JobID: 5, UserID: 2, CoverLetter: "hire me", ResumeURL: "https://cv.example.com/me.pdf", Status: models.ApplicationSubmitted, Stage: "applied"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			jobs := repository.NewMockJobStore(mc)
+			applicationRepo := repository.NewMockApplicationRepo(mc)
+			tt.setup(jobs, applicationRepo)
+			s, err := NewApplicationStore(repository.NewMockCompanyStore(mc), jobs, applicationRepo)
+			if err != nil {
+				t.Fatalf("error creating ApplicationStore: %v", err)
+			}
+			got, err := s.Apply(context.Background(), 5, na, "2")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("Apply() error = %v, want %v", err, tt.wantErrIs)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplicationStore_JobApplications(t *testing.T) {
+	tests := []struct {
+		name      string
+		setup     func(companies *repository.MockCompanyStore, jobs *repository.MockJobStore, ar *repository.MockApplicationRepo)
+		want      []models.Application
+		wantErrIs error
+	}{
+		{
+			name: "not a member of the company",
+			setup: func(companies *repository.MockCompanyStore, jobs *repository.MockJobStore, ar *repository.MockApplicationRepo) {
+				jobs.EXPECT().ViewJobDetailsById(gomock.Any(), uint64(5)).Return(models.Job{CompanyID: 1}, nil)
+				companies.EXPECT().ViewCompanyById(gomock.Any(), uint(1)).Return([]models.Companies{{UserId: 1}}, nil)
+				companies.EXPECT().ViewCompanyMember(gomock.Any(), uint(1), uint(2)).Return(models.CompanyMember{}, apperr.ErrNotFound)
+			},
+			wantErrIs: apperr.ErrForbidden,
+		},
+		{
+			name: "viewers can see applications",
+			setup: func(companies *repository.MockCompanyStore, jobs *repository.MockJobStore, ar *repository.MockApplicationRepo) {
+				jobs.EXPECT().ViewJobDetailsById(gomock.Any(), uint64(5)).Return(models.Job{CompanyID: 1}, nil)
+				companies.EXPECT().ViewCompanyById(gomock.Any(), uint(1)).Return([]models.Companies{{UserId: 1}}, nil)
+				companies.EXPECT().ViewCompanyMember(gomock.Any(), uint(1), uint(2)).Return(models.CompanyMember{Role: models.CompanyRoleViewer}, nil)
+				ar.EXPECT().ListApplicationsByJob(gomock.Any(), uint64(5), models.PageRequest{Sort: models.SortOldest}).Return([]models.Application{{JobID: 5, UserID: 3}}, models.PageInfo{}, nil)
+			},
+			want: []models.Application{{JobID: 5, UserID: 3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			companies := repository.NewMockCompanyStore(mc)
+			jobs := repository.NewMockJobStore(mc)
+			applicationRepo := repository.NewMockApplicationRepo(mc)
+			tt.setup(companies, jobs, applicationRepo)
+			s, err := NewApplicationStore(companies, jobs, applicationRepo)
+			if err != nil {
+				t.Fatalf("error creating ApplicationStore: %v", err)
+			}
+			got, _, err := s.JobApplications(context.Background(), 5, models.PageRequest{Sort: models.SortOldest}, "2")
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("JobApplications() error = %v, want %v", err, tt.wantErrIs)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("JobApplications() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplicationStore_WithdrawApplication(t *testing.T) {
+	tests := []struct {
+		name      string
+		stored    models.Application
+		withdraw  bool
+		wantErrIs error
+	}{
+		{
+			name:      "someone else's application",
+			stored:    models.Application{UserID: 3, Status: models.ApplicationSubmitted},
+			wantErrIs: apperr.ErrNotFound,
+		},
+		{
+			name:      "already decided",
+			stored:    models.Application{UserID: 2, Status: models.ApplicationRejected},
+			wantErrIs: apperr.ErrValidation,
+		},
+		{
+			name:     "in review",
+			stored:   models.Application{UserID: 2, Status: models.ApplicationInReview},
+			withdraw: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			applicationRepo := repository.NewMockApplicationRepo(mc)
+			applicationRepo.EXPECT().ViewApplication(gomock.Any(), uint(7)).Return(tt.stored, nil)
+			if tt.withdraw {
+				applicationRepo.EXPECT().UpdateApplicationStatus(gomock.Any(), uint(7), models.ApplicationWithdrawn).
+					Return(models.Application{UserID: 2, Status: models.ApplicationWithdrawn}, nil)
+			}
+			s, err := NewApplicationStore(repository.NewMockCompanyStore(mc), repository.NewMockJobStore(mc), applicationRepo)
+			if err != nil {
+				t.Fatalf("error creating ApplicationStore: %v", err)
+			}
+			_, err = s.WithdrawApplication(context.Background(), 7, "2")
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("WithdrawApplication() error = %v, want %v", err, tt.wantErrIs)
+			}
+		})
+	}
+}
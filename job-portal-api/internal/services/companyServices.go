@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"strconv"
+)
+
+func (s *CompanyStore) CreatCompanies(ctx context.Context, nc models.NewComapanies, UserID uint) (models.Companies, error) {
+
+	com := models.Companies{
+		CompanyName: nc.CompanyName,
+		FoundedYear: nc.FoundedYear,
+		Location:    nc.Location,
+		UserId:      UserID,
+		Address:     nc.Address,
+		// The user creating the company becomes its owner.
+		Members: []models.CompanyMember{{UserID: UserID, Role: models.CompanyRoleOwner}},
+	}
+
+	// The company is created with all of its jobs or not at all.
+	var company models.Companies
+	err := s.Tx.WithTx(ctx, func(tx repository.Repos) error {
+		var err error
+		company, err = tx.CreateCompany(ctx, com)
+		if err != nil {
+			return err
+		}
+		for _, nj := range nc.Jobs {
+			job, err := tx.CreateJob(ctx, nj.Job(company.ID))
+			if err != nil {
+				return err
+			}
+			company.Jobs = append(company.Jobs, job)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Companies{}, err
+	}
+	return company, nil
+}
+
+func (s *CompanyStore) ViewCompanies(ctx context.Context, p models.PageRequest, companyID string) ([]models.Companies, models.PageInfo, error) {
+	companies, info, err := s.Companies.ViewCompanies(ctx, p)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+	return companies, info, nil
+
+}
+
+func (s *CompanyStore) ViewCompaniesById(ctx context.Context, companyID uint, userID string) ([]models.Companies, error) {
+	company, err := s.Companies.ViewCompanyById(ctx, companyID)
+	if err != nil {
+		return []models.Companies{}, err
+	}
+
+	return company, nil
+}
+
+// TransferCompanyOwnership hands a company over to the user registered with email.
+func (s *CompanyStore) TransferCompanyOwnership(ctx context.Context, companyID uint, email string) (models.Companies, error) {
+	user, err := s.Users.ViewUserByEmail(ctx, email)
+	if err != nil {
+		return models.Companies{}, err
+	}
+	company, err := s.Companies.TransferCompanyOwnership(ctx, companyID, user.ID)
+	if err != nil {
+		return models.Companies{}, err
+	}
+	return company, nil
+}
+
+// authorizeCompany checks that the user holds one of roles in the company. The user who
+// created the company is always treated as its owner.
+func authorizeCompany(ctx context.Context, companies repository.CompanyStore, companyID uint, userID string, roles ...string) error {
+	uid, err := strconv.ParseUint(userID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("parsing user id: %w", err)
+	}
+
+	company, err := companies.ViewCompanyById(ctx, companyID)
+	if errors.Is(err, apperr.ErrNotFound) {
+		return fmt.Errorf("company %d: %w", companyID, apperr.ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	if len(company) == 0 {
+		return fmt.Errorf("company %d: %w", companyID, apperr.ErrNotFound)
+	}
+	if company[0].UserId == uint(uid) {
+		return nil
+	}
+
+	member, err := companies.ViewCompanyMember(ctx, companyID, uint(uid))
+	if errors.Is(err, apperr.ErrNotFound) {
+		return fmt.Errorf("user %d is not a member of company %d: %w", uid, companyID, apperr.ErrForbidden)
+	}
+	if err != nil {
+		return err
+	}
+	for _, r := range roles {
+		if member.Role == r {
+			return nil
+		}
+	}
+	return fmt.Errorf("user %d is a %s of company %d: %w", uid, member.Role, companyID, apperr.ErrForbidden)
+}
+
+func (s *CompanyStore) updateCompany(ctx context.Context, companyID uint, userID string, changes map[string]interface{}) (models.Companies, error) {
+	err := authorizeCompany(ctx, s.Companies, companyID, userID, models.CompanyRoleOwner)
+	if err != nil {
+		return models.Companies{}, err
+	}
+	if len(changes) == 0 {
+		company, err := s.Companies.ViewCompanyById(ctx, companyID)
+		if err != nil {
+			return models.Companies{}, err
+		}
+		return company[0], nil
+	}
+	company, err := s.Companies.UpdateCompany(ctx, companyID, changes)
+	if errors.Is(err, apperr.ErrNotFound) {
+		return models.Companies{}, fmt.Errorf("company %d: %w", companyID, apperr.ErrNotFound)
+	}
+	if err != nil {
+		return models.Companies{}, err
+	}
+	return company, nil
+}
+
+func (s *CompanyStore) UpdateCompany(ctx context.Context, companyID uint, uc models.UpdateCompany, userID string) (models.Companies, error) {
+	return s.updateCompany(ctx, companyID, userID, map[string]interface{}{
+		"company_name": uc.CompanyName,
+		"founded_year": uc.FoundedYear,
+		"location":     uc.Location,
+		"address":      uc.Address,
+	})
+}
+
+func (s *CompanyStore) PatchCompany(ctx context.Context, companyID uint, cp models.CompanyPatch, userID string) (models.Companies, error) {
+	changes := map[string]interface{}{}
+	if cp.CompanyName != nil {
+		changes["company_name"] = *cp.CompanyName
+	}
+	if cp.FoundedYear != nil {
+		changes["founded_year"] = *cp.FoundedYear
+	}
+	if cp.Location != nil {
+		changes["location"] = *cp.Location
+	}
+	if cp.Address != nil {
+		changes["address"] = *cp.Address
+	}
+	return s.updateCompany(ctx, companyID, userID, changes)
+}
+
+// DeleteCompany soft deletes a company and all of its jobs, only its owners may do so.
+func (s *CompanyStore) DeleteCompany(ctx context.Context, companyID uint, userID string) error {
+	err := authorizeCompany(ctx, s.Companies, companyID, userID, models.CompanyRoleOwner)
+	if err != nil {
+		return err
+	}
+	err = s.Companies.DeleteCompany(ctx, companyID)
+	if errors.Is(err, apperr.ErrNotFound) {
+		return fmt.Errorf("company %d: %w", companyID, apperr.ErrNotFound)
+	}
+	return err
+}
+
+// RestoreCompany undeletes a company. It is meant for admins and does not check membership.
+func (s *CompanyStore) RestoreCompany(ctx context.Context, companyID uint, restoreJobs bool) (models.Companies, error) {
+	company, err := s.Companies.RestoreCompany(ctx, companyID, restoreJobs)
+	if errors.Is(err, apperr.ErrNotFound) {
+		return models.Companies{}, fmt.Errorf("deleted company %d: %w", companyID, apperr.ErrNotFound)
+	}
+	if err != nil {
+		return models.Companies{}, err
+	}
+	return company, nil
+}
+
+func (s *CompanyStore) AddCompanyMember(ctx context.Context, companyID uint, nm models.NewCompanyMember, userID string) (models.CompanyMember, error) {
+	err := authorizeCompany(ctx, s.Companies, companyID, userID, models.CompanyRoleOwner)
+	if err != nil {
+		return models.CompanyMember{}, err
+	}
+
+	// company_members refers to users, but a row for a missing user would still hand its
+	// role to whoever registers under that id later on.
+	_, err = s.Users.ViewUserById(ctx, nm.UserID)
+	if errors.Is(err, apperr.ErrNotFound) {
+		return models.CompanyMember{}, fmt.Errorf("user %d: %w", nm.UserID, apperr.ErrNotFound)
+	}
+	if err != nil {
+		return models.CompanyMember{}, err
+	}
+
+	member, err := s.Companies.AddCompanyMember(ctx, models.CompanyMember{
+		CompanyID: companyID,
+		UserID:    nm.UserID,
+		Role:      nm.Role,
+	})
+	if err != nil {
+		return models.CompanyMember{}, err
+	}
+	return member, nil
+}
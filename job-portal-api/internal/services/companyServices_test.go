@@ -0,0 +1,494 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// inTx makes the units of work of uow run on tx.
+func inTx(uow *repository.MockUnitOfWork, tx repository.Repos) {
+	uow.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repository.Repos) error) error {
+		return fn(tx)
+	}).AnyTimes()
+}
+
+func TestCompanyStore_CreatCompanies(t *testing.T) {
+
+	type args struct {
+		ctx    context.Context
+		nc     models.NewComapanies
+		UserID uint
+	}
+	tests := []struct {
+		name             string
+		args             args
+		want             models.Companies
+		wantErr          bool
+		mockRepoResponse func() (models.Companies, error)
+		mockJobResponse  func(job models.Job) (models.Job, error)
+	}{
+		{name: "error from database",
+			args: args{
+				ctx: context.Background(),
+				nc: models.NewComapanies{
+					CompanyName: "google",
+					FoundedYear: 2019,
+					Location:    "banglore",
+					Address:     "blndr",
+				},
+				UserID: 1,
+			},
+			want:    models.Companies{},
+			wantErr: true,
+			mockRepoResponse: func() (models.Companies, error) {
+				return models.Companies{}, errors.New("error in data base")
+
+			},
+		},
+		{
+			name: "ok",
+			args: args{
+				ctx: context.Background(),
+				nc: models.NewComapanies{
+					CompanyName: "google",
+					FoundedYear: 2019,
+					Location:    "banglore",
+					Address:     "blndr",
+				},
+				UserID: 1,
+			},
+			want: models.Companies{
+				CompanyName: "google",
+				FoundedYear: 2019,
+				Location:    "banglore",
+				UserId:      1,
+				Address:     "blndr",
+			},
+			wantErr: false,
+			mockRepoResponse: func() (models.Companies, error) {
+				return models.Companies{
+					CompanyName: "google",
+					FoundedYear: 2019,
+					Location:    "banglore",
+					UserId:      1,
+					Address:     "blndr",
+				}, nil
+			},
+		},
+		{
+			name: "ok with jobs",
+			args: args{
+				ctx: context.Background(),
+				nc: models.NewComapanies{
+					CompanyName: "google",
+					Jobs:        []models.NewJob{{Title: "go developer"}, {Title: "sre"}},
+				},
+				UserID: 1,
+			},
+			want: models.Companies{
+				Model:       gorm.Model{ID: 7},
+				CompanyName: "google",
+				UserId:      1,
+				Jobs: []models.Job{
+					{Model: gorm.Model{ID: 1}, Title: "go developer", CompanyID: 7},
+					{Model: gorm.Model{ID: 2}, Title: "sre", CompanyID: 7},
+				},
+			},
+			mockRepoResponse: func() (models.Companies, error) {
+				return models.Companies{Model: gorm.Model{ID: 7}, CompanyName: "google", UserId: 1}, nil
+			},
+			mockJobResponse: func(job models.Job) (models.Job, error) {
+				job.ID = map[string]uint{"go developer": 1, "sre": 2}[job.Title]
+				return job, nil
+			},
+		},
+		{
+			name: "a job fails",
+			args: args{
+				ctx: context.Background(),
+				nc: models.NewComapanies{
+					CompanyName: "google",
+					Jobs:        []models.NewJob{{Title: "go developer"}},
+				},
+				UserID: 1,
+			},
+			want:    models.Companies{},
+			wantErr: true,
+			mockRepoResponse: func() (models.Companies, error) {
+				return models.Companies{Model: gorm.Model{ID: 7}, CompanyName: "google", UserId: 1}, nil
+			},
+			mockJobResponse: func(job models.Job) (models.Job, error) {
+				return models.Job{}, errors.New("error in data base")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			uow := repository.NewMockUnitOfWork(mc)
+			tx := repository.NewMockRepos(mc)
+			inTx(uow, tx)
+			if tt.mockRepoResponse != nil {
+				tx.EXPECT().CreateCompany(gomock.Any(), gomock.Any()).Return(tt.mockRepoResponse()).AnyTimes()
+			}
+			if tt.mockJobResponse != nil {
+				tx.EXPECT().CreateJob(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, job models.Job) (models.Job, error) {
+					return tt.mockJobResponse(job)
+				}).AnyTimes()
+			}
+			s, err := NewCompanyStore(repository.NewMockUserStore(mc), repository.NewMockCompanyStore(mc), uow)
+			if err != nil {
+				log.Print(err)
+				return
+			}
+			got, err := s.CreatCompanies(tt.args.ctx, tt.args.nc, tt.args.UserID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Service.ViewJobById() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreatCompanies() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompanyStore_ViewCompanies(t *testing.T) {
+
+	type args struct {
+		ctx       context.Context
+		companyID string
+	}
+	tests := []struct {
+		name           string
+		args           args
+		want           []models.Companies
+		wantErr        bool
+		mockNewService func() ([]models.Companies, models.PageInfo, error)
+	}{
+		{
+			name: "error from database",
+			args: args{
+				ctx:       context.Background(),
+				companyID: "1",
+			},
+			want:    nil,
+			wantErr: true,
+			mockNewService: func() ([]models.Companies, models.PageInfo, error) {
+				return nil, models.PageInfo{}, errors.New("data base error")
+			},
+		},
+		{
+			name: "OK",
+			args: args{
+				ctx:       context.Background(),
+				companyID: "1",
+			},
+			want: []models.Companies{
+				{
+					CompanyName: "slk",
+					FoundedYear: 2011,
+					Location:    "banglore",
+					UserId:      1,
+					Address:     "pune",
+				},
+				{
+					CompanyName: "tcs",
+					FoundedYear: 2013,
+					Location:    "pune",
+					UserId:      1,
+					Address:     "delhi",
+				},
+			},
+			wantErr: false,
+			mockNewService: func() ([]models.Companies, models.PageInfo, error) {
+				return []models.Companies{
+					{
+						CompanyName: "slk",
+						FoundedYear: 2011,
+						Location:    "banglore",
+						UserId:      1,
+						Address:     "pune",
+					},
+					{
+						CompanyName: "tcs",
+						FoundedYear: 2013,
+						Location:    "pune",
+						UserId:      1,
+						Address:     "delhi",
+					},
+				}, models.PageInfo{}, nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := gomock.NewController(t)
+			mockRepo := repository.NewMockCompanyStore(mock)
+			if tt.mockNewService != nil {
+				mockRepo.EXPECT().ViewCompanies(gomock.Any(), models.PageRequest{}).Return(tt.mockNewService()).AnyTimes()
+			}
+			s, err := NewCompanyStore(repository.NewMockUserStore(mock), mockRepo, repository.NewMockUnitOfWork(mock))
+			if err != nil {
+				log.Print(err)
+				return
+			}
+
+			got, _, err := s.ViewCompanies(tt.args.ctx, models.PageRequest{}, tt.args.companyID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ViewCompanies() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ViewCompanies() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompanyStore_ViewCompaniesById(t *testing.T) {
+
+	type args struct {
+		ctx       context.Context
+		companyID uint
+		userID    string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		want        []models.Companies
+		wantErr     bool
+		mockNewRepo func() ([]models.Companies, error)
+	}{
+		{
+			name: "Database Error",
+			args: args{
+				ctx:       context.Background(),
+				companyID: 1,
+				userID:    "2",
+			},
+			want:    []models.Companies{},
+			wantErr: true,
+			mockNewRepo: func() ([]models.Companies, error) {
+				return []models.Companies{}, errors.New("error from database layer")
+			},
+		},
+		{
+			name: "OK",
+			args: args{
+				ctx:       context.Background(),
+				companyID: 1,
+				userID:    "2",
+			},
+			want: []models.Companies{
+				{
+					CompanyName: "SLK",
+					FoundedYear: 2019,
+					Location:    "blndr",
+					UserId:      2,
+					Address:     "blndr",
+				},
+			},
+			wantErr: false,
+			mockNewRepo: func() ([]models.Companies, error) {
+				return []models.Companies{
+					{
+						CompanyName: "SLK",
+						FoundedYear: 2019,
+						Location:    "blndr",
+						UserId:      2,
+						Address:     "blndr",
+					},
+				}, nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := gomock.NewController(t)
+			mockRepo := repository.NewMockCompanyStore(mock)
+			if tt.mockNewRepo != nil {
+				mockRepo.EXPECT().ViewCompanyById(gomock.Any(), gomock.Any()).Return(tt.mockNewRepo()).AnyTimes()
+			}
+			s, err := NewCompanyStore(repository.NewMockUserStore(mock), mockRepo, repository.NewMockUnitOfWork(mock))
+			if err != nil {
+				log.Err(err)
+				return
+			}
+
+			got, err := s.ViewCompaniesById(tt.args.ctx, tt.args.companyID, tt.args.userID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ViewCompanies() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ViewCompaniesById() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompanyStore_DeleteCompany(t *testing.T) {
+	tests := []struct {
+		name      string
+		setup     func(m *repository.MockCompanyStore)
+		wantErr   bool
+		wantErrIs error
+	}{
+		{
+			name: "owner deletes",
+			setup: func(m *repository.MockCompanyStore) {
+				m.EXPECT().ViewCompanyById(gomock.Any(), uint(1)).Return([]models.Companies{{UserId: 2}}, nil)
+				m.EXPECT().DeleteCompany(gomock.Any(), uint(1)).Return(nil)
+			},
+		},
+		{
+			name: "recruiter cannot delete",
+			setup: func(m *repository.MockCompanyStore) {
+				m.EXPECT().ViewCompanyById(gomock.Any(), uint(1)).Return([]models.Companies{{UserId: 1}}, nil)
+				m.EXPECT().ViewCompanyMember(gomock.Any(), uint(1), uint(2)).Return(models.CompanyMember{Role: models.CompanyRoleRecruiter}, nil)
+			},
+			wantErr:   true,
+			wantErrIs: apperr.ErrForbidden,
+		},
+		{
+			name: "company not found",
+			setup: func(m *repository.MockCompanyStore) {
+				m.EXPECT().ViewCompanyById(gomock.Any(), uint(1)).Return([]models.Companies{}, nil)
+			},
+			wantErr:   true,
+			wantErrIs: apperr.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockCompanyStore(mc)
+			tt.setup(mockRepo)
+			s, err := NewCompanyStore(repository.NewMockUserStore(mc), mockRepo, repository.NewMockUnitOfWork(mc))
+			if err != nil {
+				t.Fatalf("error creating CompanyStore: %v", err)
+			}
+			err = s.DeleteCompany(context.Background(), 1, "2")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeleteCompany() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("DeleteCompany() error = %v, want %v", err, tt.wantErrIs)
+			}
+		})
+	}
+}
+
+func TestCompanyStore_PatchCompany(t *testing.T) {
+	location := "pune"
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockCompanyStore(mc)
+	mockRepo.EXPECT().ViewCompanyById(gomock.Any(), uint(1)).Return([]models.Companies{{UserId: 2}}, nil)
+	mockRepo.EXPECT().UpdateCompany(gomock.Any(), uint(1), map[string]interface{}{"location": "pune"}).
+		Return(models.Companies{Location: "pune", UserId: 2}, nil)
+	s, err := NewCompanyStore(repository.NewMockUserStore(mc), mockRepo, repository.NewMockUnitOfWork(mc))
+	if err != nil {
+		t.Fatalf("error creating CompanyStore: %v", err)
+	}
+	got, err := s.PatchCompany(context.Background(), 1, models.CompanyPatch{Location: &location}, "2")
+	if err != nil {
+		t.Fatalf("PatchCompany() error = %v", err)
+	}
+	if want := (models.Companies{Location: "pune", UserId: 2}); !reflect.DeepEqual(got, want) {
+		t.Errorf("PatchCompany() got = %v, want %v", got, want)
+	}
+}
+
+func TestCompanyStore_RestoreCompany(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockCompanyStore(mc)
+	mockRepo.EXPECT().RestoreCompany(gomock.Any(), uint(1), true).Return(models.Companies{}, apperr.ErrNotFound)
+	s, err := NewCompanyStore(repository.NewMockUserStore(mc), mockRepo, repository.NewMockUnitOfWork(mc))
+	if err != nil {
+		t.Fatalf("error creating CompanyStore: %v", err)
+	}
+	_, err = s.RestoreCompany(context.Background(), 1, true)
+	if !errors.Is(err, apperr.ErrNotFound) {
+		t.Errorf("RestoreCompany() error = %v, want %v", err, apperr.ErrNotFound)
+	}
+}
+
+func TestCompanyStore_TransferCompanyOwnership(t *testing.T) {
+	tests := []struct {
+		name      string
+		lookupErr error
+		wantErrIs error
+	}{
+		{name: "ok"},
+		{name: "unknown user", lookupErr: apperr.ErrNotFound, wantErrIs: apperr.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			users := repository.NewMockUserStore(mc)
+			companies := repository.NewMockCompanyStore(mc)
+			users.EXPECT().ViewUserByEmail(gomock.Any(), "new@owner.com").Return(models.User{Model: gorm.Model{ID: 7}}, tt.lookupErr)
+			if tt.lookupErr == nil {
+				companies.EXPECT().TransferCompanyOwnership(gomock.Any(), uint(3), uint(7)).Return(models.Companies{UserId: 7}, nil)
+			}
+			s, err := NewCompanyStore(users, companies, repository.NewMockUnitOfWork(mc))
+			if err != nil {
+				t.Fatalf("error creating CompanyStore: %v", err)
+			}
+			got, err := s.TransferCompanyOwnership(context.Background(), 3, "new@owner.com")
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("TransferCompanyOwnership() error = %v, want %v", err, tt.wantErrIs)
+			}
+			if err == nil && got.UserId != 7 {
+				t.Errorf("TransferCompanyOwnership() owner = %d, want 7", got.UserId)
+			}
+		})
+	}
+}
+
+func TestCompanyStore_AddCompanyMember(t *testing.T) {
+	tests := []struct {
+		name      string
+		lookupErr error
+		wantErrIs error
+	}{
+		{name: "ok"},
+		{name: "unknown user", lookupErr: apperr.ErrNotFound, wantErrIs: apperr.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			users := repository.NewMockUserStore(mc)
+			companies := repository.NewMockCompanyStore(mc)
+			companies.EXPECT().ViewCompanyById(gomock.Any(), uint(3)).Return([]models.Companies{{UserId: 2}}, nil)
+			users.EXPECT().ViewUserById(gomock.Any(), uint(7)).Return(models.User{Model: gorm.Model{ID: 7}}, tt.lookupErr)
+			if tt.lookupErr == nil {
+				companies.EXPECT().AddCompanyMember(gomock.Any(), models.CompanyMember{CompanyID: 3, UserID: 7, Role: models.CompanyRoleRecruiter}).
+					Return(models.CompanyMember{Model: gorm.Model{ID: 1}, CompanyID: 3, UserID: 7, Role: models.CompanyRoleRecruiter}, nil)
+			}
+			s, err := NewCompanyStore(users, companies, repository.NewMockUnitOfWork(mc))
+			if err != nil {
+				t.Fatalf("error creating CompanyStore: %v", err)
+			}
+			got, err := s.AddCompanyMember(context.Background(), 3, models.NewCompanyMember{UserID: 7, Role: models.CompanyRoleRecruiter}, "2")
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("AddCompanyMember() error = %v, want %v", err, tt.wantErrIs)
+			}
+			if err == nil && got.ID != 1 {
+				t.Errorf("AddCompanyMember() member = %d, want 1", got.ID)
+			}
+		})
+	}
+}
@@ -2,71 +2,245 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"job-portal-api/internal/apperr"
 	"job-portal-api/internal/models"
+	"strings"
 )
 
-func (s *Store) CreatCompanies(ctx context.Context, nc models.NewComapanies, UserID uint) (models.Companies, error) {
-
-	com := models.Companies{
-		CompanyName: nc.CompanyName,
-		FoundedYear: nc.FoundedYear,
-		Location:    nc.Location,
-		UserId:      UserID,
-		Address:     nc.Address,
-		Jobs:        nc.Jobs,
+func (s *JobStore) CreateJob(ctx context.Context, job models.Job, userID string) (models.Job, error) {
+	err := authorizeCompany(ctx, s.Companies, job.CompanyID, userID, models.CompanyRoleOwner, models.CompanyRoleRecruiter)
+	if err != nil {
+		return models.Job{}, err
 	}
 
-	company, err := s.UserRepo.CreateCompany(ctx, com)
+	if job.Status == "" {
+		job.Status = models.JobStatusOpen
+	}
+	if job.RemotePolicy == "" {
+		job.RemotePolicy = models.RemotePolicyOnsite
+	}
+	if job.EmploymentType == "" {
+		job.EmploymentType = models.EmploymentFullTime
+	}
+	job, err = s.Jobs.CreateJob(ctx, job)
 	if err != nil {
-		return models.Companies{}, err
+		return models.Job{}, err
 	}
-	return company, nil
+
+	return job, nil
 }
 
-func (s *Store) ViewCompanies(ctx context.Context, companyID string) ([]models.Companies, error) {
-	companies, err := s.UserRepo.ViewCompanies(ctx)
+// authorizeJob loads a job and checks that the user may manage it on behalf of its company.
+func (s *JobStore) authorizeJob(ctx context.Context, jobID uint64, userID string) (models.Job, error) {
+	job, err := s.Jobs.ViewJobDetailsById(ctx, jobID)
+	if errors.Is(err, apperr.ErrNotFound) {
+		return models.Job{}, fmt.Errorf("job %d: %w", jobID, apperr.ErrNotFound)
+	}
 	if err != nil {
-		return nil, err
+		return models.Job{}, err
+	}
+	err = authorizeCompany(ctx, s.Companies, job.CompanyID, userID, models.CompanyRoleOwner, models.CompanyRoleRecruiter)
+	if err != nil {
+		return models.Job{}, err
 	}
-	return companies, nil
+	return job, nil
+}
 
+func (s *JobStore) updateJob(ctx context.Context, jobID uint64, userID string, changes map[string]interface{}, skills []models.JobSkill) (models.Job, error) {
+	job, err := s.authorizeJob(ctx, jobID, userID)
+	if err != nil {
+		return models.Job{}, err
+	}
+	return s.applyJobChanges(ctx, jobID, job, changes, skills)
 }
 
-func (s *Store) ViewCompaniesById(ctx context.Context, companyID uint, userID string) ([]models.Companies, error) {
-	company, err := s.UserRepo.ViewCompanyById(ctx, companyID)
+// applyJobChanges stores changes to a job the caller has already been authorized for, job
+// is returned as is when there is nothing to change.
+func (s *JobStore) applyJobChanges(ctx context.Context, jobID uint64, job models.Job, changes map[string]interface{}, skills []models.JobSkill) (models.Job, error) {
+	if len(changes) == 0 && skills == nil {
+		return job, nil
+	}
+	job, err := s.Jobs.UpdateJob(ctx, jobID, changes, skills)
+	if errors.Is(err, apperr.ErrNotFound) {
+		return models.Job{}, fmt.Errorf("job %d: %w", jobID, apperr.ErrNotFound)
+	}
 	if err != nil {
-		return []models.Companies{}, err
+		return models.Job{}, err
 	}
+	return job, nil
+}
 
-	return company, nil
+func (s *JobStore) UpdateJob(ctx context.Context, jobID uint64, uj models.UpdateJob, userID string) (models.Job, error) {
+	if uj.RemotePolicy == "" {
+		uj.RemotePolicy = models.RemotePolicyOnsite
+	}
+	if uj.EmploymentType == "" {
+		uj.EmploymentType = models.EmploymentFullTime
+	}
+	skills := models.JobSkills(uj.Skills)
+	if skills == nil {
+		skills = []models.JobSkill{}
+	}
+	return s.updateJob(ctx, jobID, userID, map[string]interface{}{
+		"title":           uj.Title,
+		"description":     uj.Description,
+		"salary_min":      uj.SalaryMin,
+		"salary_max":      uj.SalaryMax,
+		"salary_currency": uj.SalaryCurrency,
+		"salary_period":   uj.SalaryPeriod,
+		"city":            uj.City,
+		"country":         uj.Country,
+		"remote_policy":   uj.RemotePolicy,
+		"employment_type": uj.EmploymentType,
+		"seniority":       uj.Seniority,
+		"expires_at":      uj.ExpiresAt,
+	}, skills)
 }
-func (s *Store) CreateJob(ctx context.Context, job models.Job, userID string) (models.Job, error) {
 
-	job, err := s.UserRepo.CreateJob(ctx, job)
+func (s *JobStore) PatchJob(ctx context.Context, jobID uint64, jp models.JobPatch, userID string) (models.Job, error) {
+	changes := map[string]interface{}{}
+	if jp.Title != nil {
+		changes["title"] = *jp.Title
+	}
+	if jp.Description != nil {
+		changes["description"] = *jp.Description
+	}
+	if jp.SalaryMin != nil {
+		changes["salary_min"] = *jp.SalaryMin
+	}
+	if jp.SalaryMax != nil {
+		changes["salary_max"] = *jp.SalaryMax
+	}
+	if jp.SalaryCurrency != nil {
+		changes["salary_currency"] = *jp.SalaryCurrency
+	}
+	if jp.SalaryPeriod != nil {
+		changes["salary_period"] = *jp.SalaryPeriod
+	}
+	if jp.City != nil {
+		changes["city"] = *jp.City
+	}
+	if jp.Country != nil {
+		changes["country"] = *jp.Country
+	}
+	if jp.RemotePolicy != nil {
+		changes["remote_policy"] = *jp.RemotePolicy
+	}
+	if jp.EmploymentType != nil {
+		changes["employment_type"] = *jp.EmploymentType
+	}
+	if jp.Seniority != nil {
+		changes["seniority"] = *jp.Seniority
+	}
+	if jp.ExpiresAt != nil {
+		changes["expires_at"] = *jp.ExpiresAt
+	}
+	var skills []models.JobSkill
+	if jp.Skills != nil {
+		skills = models.JobSkills(*jp.Skills)
+		if skills == nil {
+			skills = []models.JobSkill{}
+		}
+	}
+
+	job, err := s.authorizeJob(ctx, jobID, userID)
 	if err != nil {
 		return models.Job{}, err
 	}
+	err = checkSalary(job, jp)
+	if err != nil {
+		return models.Job{}, err
+	}
+	return s.applyJobChanges(ctx, jobID, job, changes, skills)
+}
+
+// checkSalary makes sure a patch does not leave the job with an inverted salary range or a
+// salary without a currency and period.
+func checkSalary(job models.Job, jp models.JobPatch) error {
+	if jp.SalaryMin != nil {
+		job.SalaryMin = *jp.SalaryMin
+	}
+	if jp.SalaryMax != nil {
+		job.SalaryMax = *jp.SalaryMax
+	}
+	if jp.SalaryCurrency != nil {
+		job.SalaryCurrency = *jp.SalaryCurrency
+	}
+	if jp.SalaryPeriod != nil {
+		job.SalaryPeriod = *jp.SalaryPeriod
+	}
+	if job.SalaryMax != 0 && job.SalaryMax < job.SalaryMin {
+		return fmt.Errorf("salary_max %d is below salary_min %d: %w", job.SalaryMax, job.SalaryMin, apperr.ErrValidation)
+	}
+	if (job.SalaryMin != 0 || job.SalaryMax != 0) && (job.SalaryCurrency == "" || job.SalaryPeriod == "") {
+		return fmt.Errorf("salary needs a currency and a period: %w", apperr.ErrValidation)
+	}
+	return nil
+}
 
+func (s *JobStore) CloseJob(ctx context.Context, jobID uint64, userID string) (models.Job, error) {
+	return s.updateJob(ctx, jobID, userID, map[string]interface{}{"status": models.JobStatusClosed}, nil)
+}
+
+func (s *JobStore) DeleteJob(ctx context.Context, jobID uint64, userID string) error {
+	_, err := s.authorizeJob(ctx, jobID, userID)
+	if err != nil {
+		return err
+	}
+	err = s.Jobs.DeleteJob(ctx, jobID)
+	if errors.Is(err, apperr.ErrNotFound) {
+		return fmt.Errorf("job %d: %w", jobID, apperr.ErrNotFound)
+	}
+	return err
+}
+
+// RestoreJob undeletes a job. It is meant for admins and does not check company membership.
+func (s *JobStore) RestoreJob(ctx context.Context, jobID uint64) (models.Job, error) {
+	job, err := s.Jobs.RestoreJob(ctx, jobID)
+	if errors.Is(err, apperr.ErrNotFound) {
+		return models.Job{}, fmt.Errorf("deleted job %d: %w", jobID, apperr.ErrNotFound)
+	}
+	if err != nil {
+		return models.Job{}, err
+	}
 	return job, nil
 }
-func (s *Store) ListJobs(ctx context.Context, companyID uint, userid string) ([]models.Job, error) {
-	jobs, err := s.UserRepo.ViewJobByCompanyId(ctx, companyID)
+
+func (s *JobStore) ListJobs(ctx context.Context, companyID uint, p models.PageRequest, userid string) ([]models.Job, models.PageInfo, error) {
+	jobs, info, err := s.Jobs.ViewJobByCompanyId(ctx, companyID, p)
 	if err != nil {
-		return jobs, err
+		return jobs, models.PageInfo{}, err
 	}
 
-	return jobs, nil
+	return jobs, info, nil
 }
-func (s *Store) AllJob(ctx context.Context, userId string) ([]models.Job, error) {
-	jobs, err := s.UserRepo.FindAllJobs(ctx)
+func (s *JobStore) AllJob(ctx context.Context, q models.JobQuery, p models.PageRequest, userId string) ([]models.Job, models.PageInfo, error) {
+	jobs, info, err := s.Jobs.FindAllJobs(ctx, q, p)
 	if err != nil {
-		return []models.Job{}, err
+		return []models.Job{}, models.PageInfo{}, err
 	}
 
-	return jobs, nil
+	return jobs, info, nil
+}
+
+func (s *JobStore) JobFacets(ctx context.Context, q models.JobQuery) (models.JobFacets, error) {
+	return s.Jobs.JobFacets(ctx, q)
 }
-func (s *Store) JobsByID(ctx context.Context, jobID uint64, userId string) (models.Job, error) {
-	job, err := s.UserRepo.ViewJobDetailsById(ctx, jobID)
+
+// SearchJobs runs a full-text search over the open jobs, best matches first unless p asks
+// for another order.
+func (s *JobStore) SearchJobs(ctx context.Context, q string, p models.PageRequest) ([]models.JobSearchResult, models.PageInfo, error) {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return nil, models.PageInfo{}, fmt.Errorf("empty search query: %w", apperr.ErrValidation)
+	}
+	return s.Jobs.SearchJobs(ctx, q, p)
+}
+
+func (s *JobStore) JobsByID(ctx context.Context, jobID uint64, userId string) (models.Job, error) {
+	job, err := s.Jobs.ViewJobDetailsById(ctx, jobID)
 	if err != nil {
 		return models.Job{}, err
 
@@ -5,303 +5,109 @@ import (
 	"errors"
 	"github.com/rs/zerolog/log"
 	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/apperr"
 	"job-portal-api/internal/models"
 	"job-portal-api/internal/repository"
 	"reflect"
 	"testing"
 )
 
-func TestStore_CreatCompanies(t *testing.T) {
-
+func TestJobStore_CreateJob(t *testing.T) {
 	type args struct {
 		ctx    context.Context
-		nc     models.NewComapanies
-		UserID uint
+		job    models.Job
+		userID string
+	}
+	ownedCompany := func() ([]models.Companies, error) {
+		return []models.Companies{{CompanyName: "google", UserId: 1}}, nil
 	}
 	tests := []struct {
-		name             string
-		args             args
-		want             models.Companies
-		wantErr          bool
-		mockRepoResponse func() (models.Companies, error)
+		name        string
+		args        args
+		want        models.Job
+		wantErr     bool
+		wantErrIs   error
+		mockCompany func() ([]models.Companies, error)
+		mockMember  func() (models.CompanyMember, error)
+		mockNewRepo func() (models.Job, error)
 	}{
-		{name: "error from database",
-			args: args{
-				ctx: context.Background(),
-				nc: models.NewComapanies{
-					CompanyName: "google",
-					FoundedYear: 2019,
-					Location:    "banglore",
-					Address:     "blndr",
-				},
-				UserID: 1,
-			},
-			want:    models.Companies{},
-			wantErr: true,
-			mockRepoResponse: func() (models.Companies, error) {
-				return models.Companies{}, errors.New("error in data base")
-
-			},
-		},
 		{
-			name: "ok",
+			name: "Error",
 			args: args{
 				ctx: context.Background(),
-				nc: models.NewComapanies{
-					CompanyName: "google",
-					FoundedYear: 2019,
-					Location:    "banglore",
-					Address:     "blndr",
+				job: models.Job{
+					Title:       "SDE",
+					Description: "frontend",
+					CompanyID:   1,
 				},
-				UserID: 1,
-			},
-			want: models.Companies{
-				CompanyName: "google",
-				FoundedYear: 2019,
-				Location:    "banglore",
-				UserId:      1,
-				Address:     "blndr",
+				userID: "1",
 			},
-			wantErr: false,
-			mockRepoResponse: func() (models.Companies, error) {
-				return models.Companies{
-					CompanyName: "google",
-					FoundedYear: 2019,
-					Location:    "banglore",
-					UserId:      1,
-					Address:     "blndr",
-				}, nil
+			want:        models.Job{},
+			wantErr:     true,
+			mockCompany: ownedCompany,
+			mockNewRepo: func() (models.Job, error) {
+				return models.Job{}, errors.New("database error")
 			},
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			mc := gomock.NewController(t)
-			mockRepo := repository.NewMockUserRepo(mc)
-			if tt.mockRepoResponse != nil {
-				mockRepo.EXPECT().CreateCompany(gomock.Any(), gomock.Any()).Return(tt.mockRepoResponse()).AnyTimes()
-			}
-			s, err := NewStore(mockRepo)
-			if err != nil {
-				log.Print(err)
-				return
-			}
-			got, err := s.CreatCompanies(tt.args.ctx, tt.args.nc, tt.args.UserID)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("Service.ViewJobById() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("CreatCompanies() got = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestStore_ViewCompanies(t *testing.T) {
-
-	type args struct {
-		ctx       context.Context
-		companyID string
-	}
-	tests := []struct {
-		name           string
-		args           args
-		want           []models.Companies
-		wantErr        bool
-		mockNewService func() ([]models.Companies, error)
-	}{
 		{
-			name: "error from database",
+			name: "company not found",
 			args: args{
-				ctx:       context.Background(),
-				companyID: "1",
+				ctx:    context.Background(),
+				job:    models.Job{Title: "SDE", CompanyID: 7},
+				userID: "1",
 			},
-			want:    nil,
-			wantErr: true,
-			mockNewService: func() ([]models.Companies, error) {
-				return nil, errors.New("data base error")
+			want:      models.Job{},
+			wantErr:   true,
+			wantErrIs: apperr.ErrNotFound,
+			mockCompany: func() ([]models.Companies, error) {
+				return nil, apperr.ErrNotFound
 			},
 		},
 		{
-			name: "OK",
+			name: "not a member of the company",
 			args: args{
-				ctx:       context.Background(),
-				companyID: "1",
-			},
-			want: []models.Companies{
-				{
-					CompanyName: "slk",
-					FoundedYear: 2011,
-					Location:    "banglore",
-					UserId:      1,
-					Address:     "pune",
-				},
-				{
-					CompanyName: "tcs",
-					FoundedYear: 2013,
-					Location:    "pune",
-					UserId:      1,
-					Address:     "delhi",
-				},
+				ctx:    context.Background(),
+				job:    models.Job{Title: "SDE", CompanyID: 1},
+				userID: "2",
 			},
-			wantErr: false,
-			mockNewService: func() ([]models.Companies, error) {
-				return []models.Companies{
-					{
-						CompanyName: "slk",
-						FoundedYear: 2011,
-						Location:    "banglore",
-						UserId:      1,
-						Address:     "pune",
-					},
-					{
-						CompanyName: "tcs",
-						FoundedYear: 2013,
-						Location:    "pune",
-						UserId:      1,
-						Address:     "delhi",
-					},
-				}, nil
+			want:        models.Job{},
+			wantErr:     true,
+			wantErrIs:   apperr.ErrForbidden,
+			mockCompany: ownedCompany,
+			mockMember: func() (models.CompanyMember, error) {
+				return models.CompanyMember{}, apperr.ErrNotFound
 			},
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			mock := gomock.NewController(t)
-			mockRepo := repository.NewMockUserRepo(mock)
-			if tt.mockNewService != nil {
-				mockRepo.EXPECT().ViewCompanies(gomock.Any()).Return(tt.mockNewService()).AnyTimes()
-			}
-			s, err := NewStore(mockRepo)
-			if err != nil {
-				log.Print(err)
-				return
-			}
-
-			got, err := s.ViewCompanies(tt.args.ctx, tt.args.companyID)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("ViewCompanies() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("ViewCompanies() got = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestStore_ViewCompaniesById(t *testing.T) {
-
-	type args struct {
-		ctx       context.Context
-		companyID uint
-		userID    string
-	}
-	tests := []struct {
-		name        string
-		args        args
-		want        []models.Companies
-		wantErr     bool
-		mockNewRepo func() ([]models.Companies, error)
-	}{
 		{
-			name: "Database Error",
+			name: "viewer cannot post jobs",
 			args: args{
-				ctx:       context.Background(),
-				companyID: 1,
-				userID:    "2",
+				ctx:    context.Background(),
+				job:    models.Job{Title: "SDE", CompanyID: 1},
+				userID: "2",
 			},
-			want:    []models.Companies{},
-			wantErr: true,
-			mockNewRepo: func() ([]models.Companies, error) {
-				return []models.Companies{}, errors.New("error from database layer")
+			want:        models.Job{},
+			wantErr:     true,
+			wantErrIs:   apperr.ErrForbidden,
+			mockCompany: ownedCompany,
+			mockMember: func() (models.CompanyMember, error) {
+				return models.CompanyMember{CompanyID: 1, UserID: 2, Role: models.CompanyRoleViewer}, nil
 			},
 		},
 		{
-			name: "OK",
+			name: "recruiter can post jobs",
 			args: args{
-				ctx:       context.Background(),
-				companyID: 1,
-				userID:    "2",
-			},
-			want: []models.Companies{
-				{
-					CompanyName: "SLK",
-					FoundedYear: 2019,
-					Location:    "blndr",
-					UserId:      2,
-					Address:     "blndr",
-				},
-			},
-			wantErr: false,
-			mockNewRepo: func() ([]models.Companies, error) {
-				return []models.Companies{
-					{
-						CompanyName: "SLK",
-						FoundedYear: 2019,
-						Location:    "blndr",
-						UserId:      2,
-						Address:     "blndr",
-					},
-				}, nil
+				ctx:    context.Background(),
+				job:    models.Job{Title: "SDE", CompanyID: 1},
+				userID: "2",
 			},
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			mock := gomock.NewController(t)
-			mockRepo := repository.NewMockUserRepo(mock)
-			if tt.mockNewRepo != nil {
-				mockRepo.EXPECT().ViewCompanyById(gomock.Any(), gomock.Any()).Return(tt.mockNewRepo()).AnyTimes()
-			}
-			s, err := NewStore(mockRepo)
-			if err != nil {
-				log.Err(err)
-				return
-			}
-
-			got, err := s.ViewCompaniesById(tt.args.ctx, tt.args.companyID, tt.args.userID)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("ViewCompanies() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("ViewCompaniesById() got = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestStore_CreateJob(t *testing.T) {
-	type args struct {
-		ctx    context.Context
-		job    models.Job
-		userID string
-	}
-	tests := []struct {
-		name        string
-		args        args
-		want        models.Job
-		wantErr     bool
-		mockNewRepo func() (models.Job, error)
-	}{
-		{
-			name: "Error",
-			args: args{
-				ctx: context.Background(),
-				job: models.Job{
-					Title:       "SDE",
-					Description: "frontend",
-					CompanyID:   1,
-				},
-				userID: "1",
+			want:        models.Job{Title: "SDE", CompanyID: 1},
+			wantErr:     false,
+			mockCompany: ownedCompany,
+			mockMember: func() (models.CompanyMember, error) {
+				return models.CompanyMember{CompanyID: 1, UserID: 2, Role: models.CompanyRoleRecruiter}, nil
 			},
-			want:    models.Job{},
-			wantErr: true,
 			mockNewRepo: func() (models.Job, error) {
-				return models.Job{}, errors.New("database error")
+				return models.Job{Title: "SDE", CompanyID: 1}, nil
 			},
 		},
 		{
@@ -320,7 +126,8 @@ func TestStore_CreateJob(t *testing.T) {
 				Description: "frontend",
 				CompanyID:   1,
 			},
-			wantErr: false,
+			wantErr:     false,
+			mockCompany: ownedCompany,
 			mockNewRepo: func() (models.Job, error) {
 				return models.Job{
 					Title:       "SDE",
@@ -333,11 +140,23 @@ func TestStore_CreateJob(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mock := gomock.NewController(t)
-			mockRepo := repository.NewMockUserRepo(mock)
+			companies := repository.NewMockCompanyStore(mock)
+			jobs := repository.NewMockJobStore(mock)
+			if tt.mockCompany != nil {
+				companies.EXPECT().ViewCompanyById(tt.args.ctx, tt.args.job.CompanyID).Return(tt.mockCompany())
+			}
+			if tt.mockMember != nil {
+				companies.EXPECT().ViewCompanyMember(tt.args.ctx, tt.args.job.CompanyID, gomock.Any()).Return(tt.mockMember())
+			}
 			if tt.mockNewRepo != nil {
-				mockRepo.EXPECT().CreateJob(tt.args.ctx, tt.args.job).Return(tt.mockNewRepo()).AnyTimes()
+				// New jobs are stored as open, onsite and full time unless told otherwise.
+				stored := tt.args.job
+				stored.Status = models.JobStatusOpen
+				stored.RemotePolicy = models.RemotePolicyOnsite
+				stored.EmploymentType = models.EmploymentFullTime
+				jobs.EXPECT().CreateJob(tt.args.ctx, stored).Return(tt.mockNewRepo()).AnyTimes()
 			}
-			s, err := NewStore(mockRepo)
+			s, err := NewJobStore(companies, jobs)
 			if err != nil {
 				log.Err(err)
 				return
@@ -347,6 +166,10 @@ func TestStore_CreateJob(t *testing.T) {
 				t.Errorf("CreateJob() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("CreateJob() error = %v, want %v", err, tt.wantErrIs)
+				return
+			}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("CreateJob() got = %v, want %v", got, tt.want)
 			}
@@ -354,7 +177,7 @@ func TestStore_CreateJob(t *testing.T) {
 	}
 }
 
-func TestStore_ListJobs(t *testing.T) {
+func TestJobStore_ListJobs(t *testing.T) {
 	type args struct {
 		ctx       context.Context
 		companyID uint
@@ -365,7 +188,7 @@ func TestStore_ListJobs(t *testing.T) {
 		args        args
 		want        []models.Job
 		wantErr     bool
-		mockNewRepo func() ([]models.Job, error)
+		mockNewRepo func() ([]models.Job, models.PageInfo, error)
 	}{
 		{
 			name: "Error",
@@ -376,8 +199,8 @@ func TestStore_ListJobs(t *testing.T) {
 			},
 			want:    []models.Job{},
 			wantErr: true,
-			mockNewRepo: func() ([]models.Job, error) {
-				return []models.Job{}, errors.New("database error")
+			mockNewRepo: func() ([]models.Job, models.PageInfo, error) {
+				return []models.Job{}, models.PageInfo{}, errors.New("database error")
 			},
 		},
 		{
@@ -400,7 +223,7 @@ func TestStore_ListJobs(t *testing.T) {
 				},
 			},
 			wantErr: false,
-			mockNewRepo: func() ([]models.Job, error) {
+			mockNewRepo: func() ([]models.Job, models.PageInfo, error) {
 				return []models.Job{
 					{
 						Title:       "SDE",
@@ -412,24 +235,24 @@ func TestStore_ListJobs(t *testing.T) {
 						Description: "go",
 						CompanyID:   1,
 					},
-				}, nil
+				}, models.PageInfo{}, nil
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mock := gomock.NewController(t)
-			mockRepo := repository.NewMockUserRepo(mock)
+			mockRepo := repository.NewMockJobStore(mock)
 			if tt.mockNewRepo != nil {
-				mockRepo.EXPECT().ViewJobByCompanyId(tt.args.ctx, tt.args.companyID).Return(tt.mockNewRepo()).AnyTimes()
+				mockRepo.EXPECT().ViewJobByCompanyId(tt.args.ctx, tt.args.companyID, models.PageRequest{}).Return(tt.mockNewRepo()).AnyTimes()
 			}
-			s, err := NewStore(mockRepo)
+			s, err := NewJobStore(repository.NewMockCompanyStore(mock), mockRepo)
 			if err != nil {
 				log.Err(err)
 				return
 			}
 
-			got, err := s.ListJobs(tt.args.ctx, tt.args.companyID, tt.args.userid)
+			got, _, err := s.ListJobs(tt.args.ctx, tt.args.companyID, models.PageRequest{}, tt.args.userid)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ListJobs() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -441,7 +264,7 @@ func TestStore_ListJobs(t *testing.T) {
 	}
 }
 
-func TestStore_AllJob(t *testing.T) {
+func TestJobStore_AllJob(t *testing.T) {
 	type args struct {
 		ctx    context.Context
 		userId string
@@ -451,7 +274,7 @@ func TestStore_AllJob(t *testing.T) {
 		args        args
 		want        []models.Job
 		wantErr     bool
-		mockNewRepo func() ([]models.Job, error)
+		mockNewRepo func() ([]models.Job, models.PageInfo, error)
 	}{
 		{
 			name: "Error",
@@ -461,8 +284,8 @@ func TestStore_AllJob(t *testing.T) {
 			},
 			want:    []models.Job{},
 			wantErr: true,
-			mockNewRepo: func() ([]models.Job, error) {
-				return []models.Job{}, errors.New("database error")
+			mockNewRepo: func() ([]models.Job, models.PageInfo, error) {
+				return []models.Job{}, models.PageInfo{}, errors.New("database error")
 			},
 		},
 		{
@@ -484,7 +307,7 @@ func TestStore_AllJob(t *testing.T) {
 				},
 			},
 			wantErr: false,
-			mockNewRepo: func() ([]models.Job, error) {
+			mockNewRepo: func() ([]models.Job, models.PageInfo, error) {
 				return []models.Job{
 					{
 						Title:       "hr",
@@ -496,23 +319,23 @@ func TestStore_AllJob(t *testing.T) {
 						Description: "43year ex",
 						CompanyID:   1,
 					},
-				}, nil
+				}, models.PageInfo{}, nil
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mock := gomock.NewController(t)
-			mockRepo := repository.NewMockUserRepo(mock)
+			mockRepo := repository.NewMockJobStore(mock)
 			if tt.mockNewRepo != nil {
-				mockRepo.EXPECT().FindAllJobs(gomock.Any()).Return(tt.mockNewRepo()).AnyTimes()
+				mockRepo.EXPECT().FindAllJobs(gomock.Any(), models.JobQuery{}, models.PageRequest{}).Return(tt.mockNewRepo()).AnyTimes()
 			}
-			s, err := NewStore(mockRepo)
+			s, err := NewJobStore(repository.NewMockCompanyStore(mock), mockRepo)
 			if err != nil {
 				log.Err(err)
 				return
 			}
-			got, err := s.AllJob(tt.args.ctx, tt.args.userId)
+			got, _, err := s.AllJob(tt.args.ctx, models.JobQuery{}, models.PageRequest{}, tt.args.userId)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("AllJob() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -524,7 +347,7 @@ func TestStore_AllJob(t *testing.T) {
 	}
 }
 
-func TestStore_AllJob1(t *testing.T) {
+func TestJobStore_AllJob1(t *testing.T) {
 	type args struct {
 		ctx    context.Context
 		jobID  uint64
@@ -576,11 +399,11 @@ func TestStore_AllJob1(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mock := gomock.NewController(t)
-			mockRepo := repository.NewMockUserRepo(mock)
+			mockRepo := repository.NewMockJobStore(mock)
 			if tt.mockNewRepo != nil {
 				mockRepo.EXPECT().ViewJobDetailsById(gomock.Any(), gomock.Any()).Return(tt.mockNewRepo()).AnyTimes()
 			}
-			s, err := NewStore(mockRepo)
+			s, err := NewJobStore(repository.NewMockCompanyStore(mock), mockRepo)
 			if err != nil {
 				log.Err(err)
 				return
@@ -596,3 +419,208 @@ func TestStore_AllJob1(t *testing.T) {
 		})
 	}
 }
+
+func TestJobStore_PatchJob(t *testing.T) {
+	title := "Backend engineer"
+	salaryMax := 50000
+	ownedJob := func(jobs *repository.MockJobStore, companies *repository.MockCompanyStore) {
+		jobs.EXPECT().ViewJobDetailsById(gomock.Any(), uint64(5)).Return(models.Job{Title: "SDE", CompanyID: 1}, nil)
+		companies.EXPECT().ViewCompanyById(gomock.Any(), uint(1)).Return([]models.Companies{{UserId: 1}}, nil)
+	}
+	tests := []struct {
+		name      string
+		patch     models.JobPatch
+		userID    string
+		setup     func(jobs *repository.MockJobStore, companies *repository.MockCompanyStore)
+		want      models.Job
+		wantErr   bool
+		wantErrIs error
+	}{
+		{
+			name:   "job not found",
+			patch:  models.JobPatch{Title: &title},
+			userID: "1",
+			setup: func(jobs *repository.MockJobStore, companies *repository.MockCompanyStore) {
+				jobs.EXPECT().ViewJobDetailsById(gomock.Any(), uint64(5)).Return(models.Job{}, apperr.ErrNotFound)
+			},
+			wantErr:   true,
+			wantErrIs: apperr.ErrNotFound,
+		},
+		{
+			name:   "not a member of the company",
+			patch:  models.JobPatch{Title: &title},
+			userID: "2",
+			setup: func(jobs *repository.MockJobStore, companies *repository.MockCompanyStore) {
+				ownedJob(jobs, companies)
+				companies.EXPECT().ViewCompanyMember(gomock.Any(), uint(1), uint(2)).Return(models.CompanyMember{}, apperr.ErrNotFound)
+			},
+			wantErr:   true,
+			wantErrIs: apperr.ErrForbidden,
+		},
+		{
+			name:   "only present fields change",
+			patch:  models.JobPatch{Title: &title},
+			userID: "1",
+			setup: func(jobs *repository.MockJobStore, companies *repository.MockCompanyStore) {
+				ownedJob(jobs, companies)
+				jobs.EXPECT().UpdateJob(gomock.Any(), uint64(5), map[string]interface{}{"title": title}, nil).
+					Return(models.Job{Title: title, CompanyID: 1}, nil)
+			},
+			want: models.Job{Title: title, CompanyID: 1},
+		},
+		{
+			name:   "salary max below stored min",
+			patch:  models.JobPatch{SalaryMax: &salaryMax},
+			userID: "1",
+			setup: func(jobs *repository.MockJobStore, companies *repository.MockCompanyStore) {
+				jobs.EXPECT().ViewJobDetailsById(gomock.Any(), uint64(5)).
+					Return(models.Job{CompanyID: 1, SalaryMin: 60000, SalaryCurrency: "INR", SalaryPeriod: models.SalaryPeriodYear}, nil)
+				companies.EXPECT().ViewCompanyById(gomock.Any(), uint(1)).Return([]models.Companies{{UserId: 1}}, nil)
+			},
+			wantErr:   true,
+			wantErrIs: apperr.ErrValidation,
+		},
+		{
+			name:      "salary without currency",
+			patch:     models.JobPatch{SalaryMax: &salaryMax},
+			userID:    "1",
+			setup:     ownedJob,
+			wantErr:   true,
+			wantErrIs: apperr.ErrValidation,
+		},
+		{
+			name:   "skills are replaced",
+			patch:  models.JobPatch{Skills: &[]models.NewJobSkill{{Name: "Go", Required: true}}},
+			userID: "1",
+			setup: func(jobs *repository.MockJobStore, companies *repository.MockCompanyStore) {
+				ownedJob(jobs, companies)
+				jobs.EXPECT().UpdateJob(gomock.Any(), uint64(5), map[string]interface{}{}, []models.JobSkill{{Name: "Go", Required: true}}).
+					Return(models.Job{Title: "SDE", CompanyID: 1, Skills: []models.JobSkill{{Name: "Go", Required: true}}}, nil)
+			},
+			want: models.Job{Title: "SDE", CompanyID: 1, Skills: []models.JobSkill{{Name: "Go", Required: true}}},
+		},
+		{
+			name:   "empty patch",
+			userID: "1",
+			setup:  ownedJob,
+			want:   models.Job{Title: "SDE", CompanyID: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			companies := repository.NewMockCompanyStore(mc)
+			jobs := repository.NewMockJobStore(mc)
+			tt.setup(jobs, companies)
+			s, err := NewJobStore(companies, jobs)
+			if err != nil {
+				t.Fatalf("error creating JobStore: %v", err)
+			}
+			got, err := s.PatchJob(context.Background(), 5, tt.patch, tt.userID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PatchJob() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("PatchJob() error = %v, want %v", err, tt.wantErrIs)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PatchJob() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJobStore_DeleteJob(t *testing.T) {
+	tests := []struct {
+		name      string
+		setup     func(jobs *repository.MockJobStore, companies *repository.MockCompanyStore)
+		wantErr   bool
+		wantErrIs error
+	}{
+		{
+			name: "recruiter deletes",
+			setup: func(jobs *repository.MockJobStore, companies *repository.MockCompanyStore) {
+				jobs.EXPECT().ViewJobDetailsById(gomock.Any(), uint64(5)).Return(models.Job{CompanyID: 1}, nil)
+				companies.EXPECT().ViewCompanyById(gomock.Any(), uint(1)).Return([]models.Companies{{UserId: 1}}, nil)
+				companies.EXPECT().ViewCompanyMember(gomock.Any(), uint(1), uint(2)).Return(models.CompanyMember{Role: models.CompanyRoleRecruiter}, nil)
+				jobs.EXPECT().DeleteJob(gomock.Any(), uint64(5)).Return(nil)
+			},
+		},
+		{
+			name: "viewer cannot delete",
+			setup: func(jobs *repository.MockJobStore, companies *repository.MockCompanyStore) {
+				jobs.EXPECT().ViewJobDetailsById(gomock.Any(), uint64(5)).Return(models.Job{CompanyID: 1}, nil)
+				companies.EXPECT().ViewCompanyById(gomock.Any(), uint(1)).Return([]models.Companies{{UserId: 1}}, nil)
+				companies.EXPECT().ViewCompanyMember(gomock.Any(), uint(1), uint(2)).Return(models.CompanyMember{Role: models.CompanyRoleViewer}, nil)
+			},
+			wantErr:   true,
+			wantErrIs: apperr.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			companies := repository.NewMockCompanyStore(mc)
+			jobs := repository.NewMockJobStore(mc)
+			tt.setup(jobs, companies)
+			s, err := NewJobStore(companies, jobs)
+			if err != nil {
+				t.Fatalf("error creating JobStore: %v", err)
+			}
+			err = s.DeleteJob(context.Background(), 5, "2")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeleteJob() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("DeleteJob() error = %v, want %v", err, tt.wantErrIs)
+			}
+		})
+	}
+}
+
+func TestJobStore_RestoreJob(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockJobStore(mc)
+	mockRepo.EXPECT().RestoreJob(gomock.Any(), uint64(5)).Return(models.Job{}, apperr.ErrNotFound)
+	s, err := NewJobStore(repository.NewMockCompanyStore(mc), mockRepo)
+	if err != nil {
+		t.Fatalf("error creating JobStore: %v", err)
+	}
+	_, err = s.RestoreJob(context.Background(), 5)
+	if !errors.Is(err, apperr.ErrNotFound) {
+		t.Errorf("RestoreJob() error = %v, want %v", err, apperr.ErrNotFound)
+	}
+}
+
+func TestJobStore_SearchJobs(t *testing.T) {
+	page := models.PageRequest{Limit: 5, Sort: models.SortRelevance}
+	tests := []struct {
+		name      string
+		q         string
+		wantQ     string
+		wantErrIs error
+	}{
+		{name: "blank query", q: "  ", wantErrIs: apperr.ErrValidation},
+		{name: "query is trimmed", q: " go ", wantQ: "go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockJobStore(mc)
+			if tt.wantQ != "" {
+				mockRepo.EXPECT().SearchJobs(gomock.Any(), tt.wantQ, page).Return([]models.JobSearchResult{}, models.PageInfo{}, nil)
+			}
+			s, err := NewJobStore(repository.NewMockCompanyStore(mc), mockRepo)
+			if err != nil {
+				t.Fatalf("error creating JobStore: %v", err)
+			}
+			_, _, err = s.SearchJobs(context.Background(), tt.q, page)
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("SearchJobs() error = %v, want %v", err, tt.wantErrIs)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/models"
+	"sort"
+)
+
+// DefaultPipeline is used by companies that have not defined their own. Every open stage can
+// reject, and only an offer can end in a hire.
+func DefaultPipeline(companyID uint) []models.PipelineStage {
+	stage := func(name string, position int, outcome string, next ...string) models.PipelineStage {
+		return models.PipelineStage{CompanyID: companyID, Name: name, Position: position, Outcome: outcome, Next: next}
+	}
+	return []models.PipelineStage{
+		stage("applied", 0, "", "screening", "rejected"),
+		stage("screening", 1, "", "interview", "rejected"),
+		stage("interview", 2, "", "offer", "rejected"),
+		stage("offer", 3, "", "hired", "rejected"),
+		stage("hired", 4, models.OutcomeHired),
+		stage("rejected", 5, models.OutcomeRejected),
+	}
+}
+
+// pipeline is the state machine applications of a company go through.
+type pipeline struct {
+	stages []models.PipelineStage
+	byName map[string]models.PipelineStage
+}
+
+// newPipeline checks that stages form a usable pipeline: the first stage is where
+// applications start, every transition leads to a known stage and terminal stages have none.
+func newPipeline(stages []models.PipelineStage) (pipeline, error) {
+	if len(stages) == 0 {
+		return pipeline{}, fmt.Errorf("pipeline has no stages: %w", apperr.ErrValidation)
+	}
+	sorted := make([]models.PipelineStage, len(stages))
+	copy(sorted, stages)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Position < sorted[j].Position })
+
+	p := pipeline{stages: sorted, byName: make(map[string]models.PipelineStage, len(sorted))}
+	for _, st := range sorted {
+		if _, ok := p.byName[st.Name]; ok {
+			return pipeline{}, fmt.Errorf("stage %q appears twice: %w", st.Name, apperr.ErrValidation)
+		}
+		p.byName[st.Name] = st
+	}
+	if sorted[0].Outcome != "" {
+		return pipeline{}, fmt.Errorf("first stage %q cannot be terminal: %w", sorted[0].Name, apperr.ErrValidation)
+	}
+	rejects := false
+	for _, st := range sorted {
+		if st.Outcome == models.OutcomeRejected {
+			rejects = true
+		}
+		if st.Outcome != "" && len(st.Next) > 0 {
+			return pipeline{}, fmt.Errorf("terminal stage %q cannot lead anywhere: %w", st.Name, apperr.ErrValidation)
+		}
+		if st.Outcome == "" && len(st.Next) == 0 {
+			return pipeline{}, fmt.Errorf("stage %q leads nowhere: %w", st.Name, apperr.ErrValidation)
+		}
+		for _, next := range st.Next {
+			if _, ok := p.byName[next]; !ok {
+				return pipeline{}, fmt.Errorf("stage %q leads to unknown stage %q: %w", st.Name, next, apperr.ErrValidation)
+			}
+			if next == st.Name {
+				return pipeline{}, fmt.Errorf("stage %q leads to itself: %w", st.Name, apperr.ErrValidation)
+			}
+		}
+	}
+	if !rejects {
+		return pipeline{}, fmt.Errorf("pipeline needs a rejected stage: %w", apperr.ErrValidation)
+	}
+	return p, nil
+}
+
+func (p pipeline) initial() string {
+	return p.stages[0].Name
+}
+
+// rejection is the first stage with a rejected outcome.
+func (p pipeline) rejection() string {
+	for _, st := range p.stages {
+		if st.Outcome == models.OutcomeRejected {
+			return st.Name
+		}
+	}
+	return ""
+}
+
+// move checks that an application may go from one stage to another.
+func (p pipeline) move(from, to string) error {
+	target, ok := p.byName[to]
+	if !ok {
+		return fmt.Errorf("unknown stage %q: %w", to, apperr.ErrValidation)
+	}
+	current, ok := p.byName[from]
+	if !ok {
+		return fmt.Errorf("stage %q is not in the pipeline: %w", from, apperr.ErrValidation)
+	}
+	for _, next := range current.Next {
+		if next == target.Name {
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot move from %q to %q: %w", from, to, apperr.ErrValidation)
+}
+
+// status is the application status that goes with a stage.
+func (p pipeline) status(stage string) string {
+	switch p.byName[stage].Outcome {
+	case models.OutcomeHired:
+		return models.ApplicationHired
+	case models.OutcomeRejected:
+		return models.ApplicationRejected
+	}
+	if stage == p.initial() {
+		return models.ApplicationSubmitted
+	}
+	return models.ApplicationInReview
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/models"
+	"strconv"
+)
+
+// companyPipeline loads the pipeline of a company, falling back to the default one.
+func (s *ApplicationStore) companyPipeline(ctx context.Context, companyID uint) (pipeline, error) {
+	stages, err := s.ApplicationRepo.ViewPipeline(ctx, companyID)
+	if err != nil {
+		return pipeline{}, err
+	}
+	if len(stages) == 0 {
+		stages = DefaultPipeline(companyID)
+	}
+	return newPipeline(stages)
+}
+
+func (s *ApplicationStore) ViewPipeline(ctx context.Context, companyID uint, userID string) ([]models.PipelineStage, error) {
+	err := authorizeCompany(ctx, s.Companies, companyID, userID, models.CompanyRoleOwner, models.CompanyRoleRecruiter, models.CompanyRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.companyPipeline(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return p.stages, nil
+}
+
+// SetPipeline replaces the pipeline of a company. Stages that applications under
+// consideration are in cannot be dropped.
+func (s *ApplicationStore) SetPipeline(ctx context.Context, companyID uint, np models.NewPipeline, userID string) ([]models.PipelineStage, error) {
+	err := authorizeCompany(ctx, s.Companies, companyID, userID, models.CompanyRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	stages := make([]models.PipelineStage, 0, len(np.Stages))
+	names := make([]string, 0, len(np.Stages))
+	for i, st := range np.Stages {
+		stages = append(stages, models.PipelineStage{
+			CompanyID: companyID,
+			Name:      st.Name,
+			Position:  i,
+			Outcome:   st.Outcome,
+			Next:      st.Next,
+		})
+		names = append(names, st.Name)
+	}
+	_, err = newPipeline(stages)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := s.ApplicationRepo.CountOpenApplicationsOutside(ctx, companyID, names)
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		return nil, fmt.Errorf("%d open applications are in stages that would be removed: %w", n, apperr.ErrConflict)
+	}
+	return s.ApplicationRepo.ReplacePipeline(ctx, companyID, stages)
+}
+
+func (s *ApplicationStore) MoveApplications(ctx context.Context, bm models.BulkMove, userID string) ([]models.Application, error) {
+	return s.moveApplications(ctx, bm.ApplicationIDs, bm.Stage, bm.Reason, userID)
+}
+
+func (s *ApplicationStore) RejectApplications(ctx context.Context, br models.BulkReject, userID string) ([]models.Application, error) {
+	return s.moveApplications(ctx, br.ApplicationIDs, "", br.Reason, userID)
+}
+
+// moveApplications moves applications to the jobs of a single company to stage, or to the
+// rejection stage of the company when stage is empty. Either all of them move or none do.
+func (s *ApplicationStore) moveApplications(ctx context.Context, ids []uint, stage, reason, userID string) ([]models.Application, error) {
+	uid, err := strconv.ParseUint(userID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("user id %q: %w", userID, apperr.ErrUnauthorized)
+	}
+
+	apps, err := s.ApplicationRepo.ListApplicationsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[uint]bool, len(apps))
+	for _, app := range apps {
+		if app.Job != nil {
+			found[app.ID] = true
+		}
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return nil, fmt.Errorf("application %d: %w", id, apperr.ErrNotFound)
+		}
+	}
+
+	companyID := apps[0].Job.CompanyID
+	for _, app := range apps {
+		if app.Job.CompanyID != companyID {
+			return nil, fmt.Errorf("applications belong to more than one company: %w", apperr.ErrValidation)
+		}
+	}
+	err = authorizeCompany(ctx, s.Companies, companyID, userID, models.CompanyRoleOwner, models.CompanyRoleRecruiter)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.companyPipeline(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if stage == "" {
+		stage = p.rejection()
+	}
+
+	changes := make([]models.ApplicationStageChange, 0, len(apps))
+	for _, app := range apps {
+		if app.Status == models.ApplicationWithdrawn {
+			return nil, fmt.Errorf("application %d was withdrawn: %w", app.ID, apperr.ErrValidation)
+		}
+		from := app.Stage
+		if from == "" {
+			from = p.initial()
+		}
+		err = p.move(from, stage)
+		if err != nil {
+			return nil, fmt.Errorf("application %d: %w", app.ID, err)
+		}
+		changes = append(changes, models.ApplicationStageChange{
+			ApplicationID: app.ID,
+			FromStage:     app.Stage,
+			ToStage:       stage,
+			Status:        p.status(stage),
+			ChangedBy:     uint(uid),
+			Reason:        reason,
+		})
+	}
+
+	err = s.ApplicationRepo.MoveApplications(ctx, changes)
+	if err != nil {
+		return nil, err
+	}
+	return s.ApplicationRepo.ListApplicationsByIDs(ctx, ids)
+}
+
+// ApplicationHistory lists the stage changes of an application to members of the company
+// that posted the job.
+func (s *ApplicationStore) ApplicationHistory(ctx context.Context, applicationID uint, userID string) ([]models.ApplicationStageChange, error) {
+	app, err := s.ApplicationRepo.ViewApplication(ctx, applicationID)
+	if errors.Is(err, apperr.ErrNotFound) {
+		return nil, fmt.Errorf("application %d: %w", applicationID, apperr.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	job, err := s.Jobs.ViewJobDetailsById(ctx, uint64(app.JobID))
+	if errors.Is(err, apperr.ErrNotFound) {
+		return nil, fmt.Errorf("application %d: %w", applicationID, apperr.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = authorizeCompany(ctx, s.Companies, job.CompanyID, userID, models.CompanyRoleOwner, models.CompanyRoleRecruiter, models.CompanyRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return s.ApplicationRepo.ListStageChanges(ctx, applicationID)
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"reflect"
+	"testing"
+)
+
+func TestApplicationStore_MoveApplications(t *testing.T) {
+	job := &models.Job{CompanyID: 1}
+	app := func(id uint, stage, status string) models.Application {
+		a := models.Application{JobID: 5, Stage: stage, Status: status, Job: job}
+		a.ID = id
+		return a
+	}
+	owner := func(companies *repository.MockCompanyStore) {
+		companies.EXPECT().ViewCompanyById(gomock.Any(), uint(1)).Return([]models.Companies{{UserId: 2}}, nil)
+	}
+	tests := []struct {
+		name      string
+		move      models.BulkMove
+		setup     func(companies *repository.MockCompanyStore, ar *repository.MockApplicationRepo)
+		wantErrIs error
+	}{
+		{
+			name: "missing application",
+			move: models.BulkMove{ApplicationIDs: []uint{1, 2}, Stage: "screening"},
+			setup: func(companies *repository.MockCompanyStore, ar *repository.MockApplicationRepo) {
+				ar.EXPECT().ListApplicationsByIDs(gomock.Any(), []uint{1, 2}).Return([]models.Application{app(1, "applied", models.ApplicationSubmitted)}, nil)
+			},
+			wantErrIs: apperr.ErrNotFound,
+		},
+		{
+			name: "applications of two companies",
+			move: models.BulkMove{ApplicationIDs: []uint{1, 2}, Stage: "screening"},
+			setup: func(companies *repository.MockCompanyStore, ar *repository.MockApplicationRepo) {
+				other := app(2, "applied", models.ApplicationSubmitted)
+				other.Job = &models.Job{CompanyID: 9}
+				ar.EXPECT().ListApplicationsByIDs(gomock.Any(), []uint{1, 2}).Return([]models.Application{app(1, "applied", models.ApplicationSubmitted), other}, nil)
+			},
+			wantErrIs: apperr.ErrValidation,
+		},
+		{
+			name: "one transition is not allowed so none move",
+			move: models.BulkMove{ApplicationIDs: []uint{1, 2}, Stage: "interview"},
+			setup: func(companies *repository.MockCompanyStore, ar *repository.MockApplicationRepo) {
+				ar.EXPECT().ListApplicationsByIDs(gomock.Any(), []uint{1, 2}).
+					Return([]models.Application{app(1, "screening", models.ApplicationInReview), app(2, "applied", models.ApplicationSubmitted)}, nil)
+				owner(companies)
+				ar.EXPECT().ViewPipeline(gomock.Any(), uint(1)).Return(nil, nil)
+			},
+			wantErrIs: apperr.ErrValidation,
+		},
+		{
+			name: "moved concurrently",
+			move: models.BulkMove{ApplicationIDs: []uint{1}, Stage: "screening"},
+			setup: func(companies *repository.MockCompanyStore, ar *repository.MockApplicationRepo) {
+				ar.EXPECT().ListApplicationsByIDs(gomock.Any(), []uint{1}).Return([]models.Application{app(1, "applied", models.ApplicationSubmitted)}, nil)
+				owner(companies)
+				ar.EXPECT().ViewPipeline(gomock.Any(), uint(1)).Return(nil, nil)
+				ar.EXPECT().MoveApplications(gomock.Any(), gomock.Any()).Return(apperr.ErrConflict)
+			},
+			wantErrIs: apperr.ErrConflict,
+		},
+		{
+			name: "moves and records who and why",
+			move: models.BulkMove{ApplicationIDs: []uint{1, 2}, Stage: "screening", Reason: "strong profiles"},
+			setup: func(companies *repository.MockCompanyStore, ar *repository.MockApplicationRepo) {
+				ar.EXPECT().ListApplicationsByIDs(gomock.Any(), []uint{1, 2}).
+					Return([]models.Application{app(1, "applied", models.ApplicationSubmitted), app(2, "", models.ApplicationSubmitted)}, nil)
+				owner(companies)
+				ar.EXPECT().ViewPipeline(gomock.Any(), uint(1)).Return(nil, nil)
+				ar.EXPECT().MoveApplications(gomock.Any(), []models.ApplicationStageChange{
+					{ApplicationID: 1, FromStage: "applied", ToStage: "screening", Status: models.ApplicationInReview, ChangedBy: 2, Reason: "strong profiles"},
+					{ApplicationID: 2, FromStage: "", ToStage: "screening", Status: models.ApplicationInReview, ChangedBy: 2, Reason: "strong profiles"},
+				}).Return(nil)
+				ar.EXPECT().ListApplicationsByIDs(gomock.Any(), []uint{1, 2}).Return(nil, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			companies := repository.NewMockCompanyStore(mc)
+			applicationRepo := repository.NewMockApplicationRepo(mc)
+			tt.setup(companies, applicationRepo)
+			s, err := NewApplicationStore(companies, repository.NewMockJobStore(mc), applicationRepo)
+			if err != nil {
+				t.Fatalf("error creating ApplicationStore: %v", err)
+			}
+			_, err = s.MoveApplications(context.Background(), tt.move, "2")
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("MoveApplications() error = %v, want %v", err, tt.wantErrIs)
+			}
+		})
+	}
+}
+
+func TestApplicationStore_RejectApplications(t *testing.T) {
+	mc := gomock.NewController(t)
+	companies := repository.NewMockCompanyStore(mc)
+	applicationRepo := repository.NewMockApplicationRepo(mc)
+	custom := []models.PipelineStage{
+		{Name: "new", Next: []string{"call", "not a fit"}},
+		{Name: "call", Position: 1, Next: []string{"not a fit", "signed"}},
+		{Name: "signed", Position: 2, Outcome: models.OutcomeHired},
+		{Name: "not a fit", Position: 3, Outcome: models.OutcomeRejected},
+	}
+	a := models.Application{Stage: "call", Status: models.ApplicationInReview, Job: &models.Job{CompanyID: 1}}
+	a.ID = 4
+	applicationRepo.EXPECT().ListApplicationsByIDs(gomock.Any(), []uint{4}).Return([]models.Application{a}, nil)
+	companies.EXPECT().ViewCompanyById(gomock.Any(), uint(1)).Return([]models.Companies{{UserId: 1}}, nil)
+	companies.EXPECT().ViewCompanyMember(gomock.Any(), uint(1), uint(2)).Return(models.CompanyMember{Role: models.CompanyRoleRecruiter}, nil)
+	applicationRepo.EXPECT().ViewPipeline(gomock.Any(), uint(1)).Return(custom, nil)
+	applicationRepo.EXPECT().MoveApplications(gomock.Any(), []models.ApplicationStageChange{
+		{ApplicationID: 4, FromStage: "call", ToStage: "not a fit", Status: models.ApplicationRejected, ChangedBy: 2},
+	}).Return(nil)
+	rejected := a
+	rejected.Stage, rejected.Status = "not a fit", models.ApplicationRejected
+	applicationRepo.EXPECT().ListApplicationsByIDs(gomock.Any(), []uint{4}).Return([]models.Application{rejected}, nil)
+
+	s, err := NewApplicationStore(companies, repository.NewMockJobStore(mc), applicationRepo)
+	if err != nil {
+		t.Fatalf("error creating ApplicationStore: %v", err)
+	}
+	got, err := s.RejectApplications(context.Background(), models.BulkReject{ApplicationIDs: []uint{4}}, "2")
+	if err != nil {
+		t.Fatalf("RejectApplications() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []models.Application{rejected}) {
+		t.Errorf("RejectApplications() got = %v, want %v", got, []models.Application{rejected})
+	}
+}
+
+func TestApplicationStore_SetPipeline(t *testing.T) {
+	np := models.NewPipeline{Stages: []models.NewPipelineStage{
+		{Name: "new", Next: []string{"no"}},
+		{Name: "no", Outcome: models.OutcomeRejected},
+	}}
+	tests := []struct {
+		name      string
+		setup     func(ar *repository.MockApplicationRepo)
+		wantErrIs error
+	}{
+		{
+			name: "open applications in removed stages",
+			setup: func(ar *repository.MockApplicationRepo) {
+				ar.EXPECT().CountOpenApplicationsOutside(gomock.Any(), uint(1), []string{"new", "no"}).Return(int64(3), nil)
+			},
+			wantErrIs: apperr.ErrConflict,
+		},
+		{
+			name: "replaced",
+			setup: func(ar *repository.MockApplicationRepo) {
+				ar.EXPECT().CountOpenApplicationsOutside(gomock.Any(), uint(1), []string{"new", "no"}).Return(int64(0), nil)
+				ar.EXPECT().ReplacePipeline(gomock.Any(), uint(1), []models.PipelineStage{
+					{CompanyID: 1, Name: "new", Next: []string{"no"}},
+					{CompanyID: 1, Name: "no", Position: 1, Outcome: models.OutcomeRejected},
+				}).Return(nil, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			companies := repository.NewMockCompanyStore(mc)
+			companies.EXPECT().ViewCompanyById(gomock.Any(), uint(1)).Return([]models.Companies{{UserId: 2}}, nil)
+			applicationRepo := repository.NewMockApplicationRepo(mc)
+			tt.setup(applicationRepo)
+			s, err := NewApplicationStore(companies, repository.NewMockJobStore(mc), applicationRepo)
+			if err != nil {
+				t.Fatalf("error creating ApplicationStore: %v", err)
+			}
+			_, err = s.SetPipeline(context.Background(), 1, np, "2")
+			if !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("SetPipeline() error = %v, want %v", err, tt.wantErrIs)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"errors"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/models"
+	"testing"
+)
+
+func TestNewPipeline(t *testing.T) {
+	tests := []struct {
+		name    string
+		stages  []models.PipelineStage
+		wantErr bool
+	}{
+		{
+			name:   "default pipeline",
+			stages: DefaultPipeline(1),
+		},
+		{
+			name:    "no stages",
+			wantErr: true,
+		},
+		{
+			name: "terminal first stage",
+			stages: []models.PipelineStage{
+				{Name: "rejected", Outcome: models.OutcomeRejected},
+				{Name: "applied", Position: 1, Next: []string{"rejected"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown next stage",
+			stages: []models.PipelineStage{
+				{Name: "applied", Next: []string{"call"}},
+				{Name: "rejected", Position: 1, Outcome: models.OutcomeRejected},
+			},
+			wantErr: true,
+		},
+		{
+			name: "dead end",
+			stages: []models.PipelineStage{
+				{Name: "applied", Next: []string{"call"}},
+				{Name: "call", Position: 1},
+				{Name: "rejected", Position: 2, Outcome: models.OutcomeRejected},
+			},
+			wantErr: true,
+		},
+		{
+			name: "no way to reject",
+			stages: []models.PipelineStage{
+				{Name: "applied", Next: []string{"hired"}},
+				{Name: "hired", Position: 1, Outcome: models.OutcomeHired},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newPipeline(tt.stages)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newPipeline() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, apperr.ErrValidation) {
+				t.Errorf("newPipeline() error = %v, want %v", err, apperr.ErrValidation)
+			}
+		})
+	}
+}
+
+func TestPipeline_Move(t *testing.T) {
+	p, err := newPipeline(DefaultPipeline(1))
+	if err != nil {
+		t.Fatalf("newPipeline() error = %v", err)
+	}
+	tests := []struct {
+		from, to   string
+		wantErr    bool
+		wantStatus string
+	}{
+		{from: "applied", to: "screening", wantStatus: models.ApplicationInReview},
+		{from: "applied", to: "rejected", wantStatus: models.ApplicationRejected},
+		{from: "offer", to: "hired", wantStatus: models.ApplicationHired},
+		{from: "applied", to: "offer", wantErr: true},
+		{from: "screening", to: "applied", wantErr: true},
+		{from: "rejected", to: "screening", wantErr: true},
+		{from: "hired", to: "rejected", wantErr: true},
+		{from: "applied", to: "lunch", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			err := p.move(tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("move() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && p.status(tt.to) != tt.wantStatus {
+				t.Errorf("status() = %v, want %v", p.status(tt.to), tt.wantStatus)
+			}
+		})
+	}
+	if p.initial() != "applied" || p.rejection() != "rejected" {
+		t.Errorf("initial() = %v, rejection() = %v", p.initial(), p.rejection())
+	}
+}
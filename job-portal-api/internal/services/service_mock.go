@@ -0,0 +1,669 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services.go
+//
+// Generated by this command:
+//
+//	mockgen -source services.go -destination service_mock.go -package services
+//
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	auth "job-portal-api/internal/auth"
+	models "job-portal-api/internal/models"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserService is a mock of UserService interface.
+type MockUserService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserServiceMockRecorder
+}
+
+// MockUserServiceMockRecorder is the mock recorder for MockUserService.
+type MockUserServiceMockRecorder struct {
+	mock *MockUserService
+}
+
+// NewMockUserService creates a new mock instance.
+func NewMockUserService(ctrl *gomock.Controller) *MockUserService {
+	mock := &MockUserService{ctrl: ctrl}
+	mock.recorder = &MockUserServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockUserService) Authenticate(ctx context.Context, email, password string) (auth.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, email, password)
+	ret0, _ := ret[0].(auth.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockUserServiceMockRecorder) Authenticate(ctx, email, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockUserService)(nil).Authenticate), ctx, email, password)
+}
+
+// CreateUser mocks base method.
+func (m *MockUserService) CreateUser(ctx context.Context, nu models.NewUser) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, nu)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockUserServiceMockRecorder) CreateUser(ctx, nu any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserService)(nil).CreateUser), ctx, nu)
+}
+
+// IsTokenRevoked mocks base method.
+func (m *MockUserService) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTokenRevoked", ctx, jti)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTokenRevoked indicates an expected call of IsTokenRevoked.
+func (mr *MockUserServiceMockRecorder) IsTokenRevoked(ctx, jti any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenRevoked", reflect.TypeOf((*MockUserService)(nil).IsTokenRevoked), ctx, jti)
+}
+
+// IssueRefreshToken mocks base method.
+func (m *MockUserService) IssueRefreshToken(ctx context.Context, userId string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueRefreshToken", ctx, userId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueRefreshToken indicates an expected call of IssueRefreshToken.
+func (mr *MockUserServiceMockRecorder) IssueRefreshToken(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueRefreshToken", reflect.TypeOf((*MockUserService)(nil).IssueRefreshToken), ctx, userId)
+}
+
+// Logout mocks base method.
+func (m *MockUserService) Logout(ctx context.Context, claims auth.Claims, refreshToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, claims, refreshToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockUserServiceMockRecorder) Logout(ctx, claims, refreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockUserService)(nil).Logout), ctx, claims, refreshToken)
+}
+
+// RefreshToken mocks base method.
+func (m *MockUserService) RefreshToken(ctx context.Context, refreshToken string) (auth.Claims, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshToken", ctx, refreshToken)
+	ret0, _ := ret[0].(auth.Claims)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockUserServiceMockRecorder) RefreshToken(ctx, refreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockUserService)(nil).RefreshToken), ctx, refreshToken)
+}
+
+// ResetPassword mocks base method.
+func (m *MockUserService) ResetPassword(ctx context.Context, email, password string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, email, password)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockUserServiceMockRecorder) ResetPassword(ctx, email, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUserService)(nil).ResetPassword), ctx, email, password)
+}
+
+// UpdateUserRole mocks base method.
+func (m *MockUserService) UpdateUserRole(ctx context.Context, userID uint, role string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", ctx, userID, role)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockUserServiceMockRecorder) UpdateUserRole(ctx, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockUserService)(nil).UpdateUserRole), ctx, userID, role)
+}
+
+// MockCompanyService is a mock of CompanyService interface.
+type MockCompanyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCompanyServiceMockRecorder
+}
+
+// MockCompanyServiceMockRecorder is the mock recorder for MockCompanyService.
+type MockCompanyServiceMockRecorder struct {
+	mock *MockCompanyService
+}
+
+// NewMockCompanyService creates a new mock instance.
+func NewMockCompanyService(ctrl *gomock.Controller) *MockCompanyService {
+	mock := &MockCompanyService{ctrl: ctrl}
+	mock.recorder = &MockCompanyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCompanyService) EXPECT() *MockCompanyServiceMockRecorder {
+	return m.recorder
+}
+
+// AddCompanyMember mocks base method.
+func (m *MockCompanyService) AddCompanyMember(ctx context.Context, companyID uint, nm models.NewCompanyMember, userId string) (models.CompanyMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCompanyMember", ctx, companyID, nm, userId)
+	ret0, _ := ret[0].(models.CompanyMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCompanyMember indicates an expected call of AddCompanyMember.
+func (mr *MockCompanyServiceMockRecorder) AddCompanyMember(ctx, companyID, nm, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCompanyMember", reflect.TypeOf((*MockCompanyService)(nil).AddCompanyMember), ctx, companyID, nm, userId)
+}
+
+// CreatCompanies mocks base method.
+func (m *MockCompanyService) CreatCompanies(ctx context.Context, nc models.NewComapanies, UserId uint) (models.Companies, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatCompanies", ctx, nc, UserId)
+	ret0, _ := ret[0].(models.Companies)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatCompanies indicates an expected call of CreatCompanies.
+func (mr *MockCompanyServiceMockRecorder) CreatCompanies(ctx, nc, UserId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatCompanies", reflect.TypeOf((*MockCompanyService)(nil).CreatCompanies), ctx, nc, UserId)
+}
+
+// DeleteCompany mocks base method.
+func (m *MockCompanyService) DeleteCompany(ctx context.Context, companyID uint, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCompany", ctx, companyID, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCompany indicates an expected call of DeleteCompany.
+func (mr *MockCompanyServiceMockRecorder) DeleteCompany(ctx, companyID, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCompany", reflect.TypeOf((*MockCompanyService)(nil).DeleteCompany), ctx, companyID, userId)
+}
+
+// PatchCompany mocks base method.
+func (m *MockCompanyService) PatchCompany(ctx context.Context, companyID uint, cp models.CompanyPatch, userId string) (models.Companies, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchCompany", ctx, companyID, cp, userId)
+	ret0, _ := ret[0].(models.Companies)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchCompany indicates an expected call of PatchCompany.
+func (mr *MockCompanyServiceMockRecorder) PatchCompany(ctx, companyID, cp, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchCompany", reflect.TypeOf((*MockCompanyService)(nil).PatchCompany), ctx, companyID, cp, userId)
+}
+
+// RestoreCompany mocks base method.
+func (m *MockCompanyService) RestoreCompany(ctx context.Context, companyID uint, restoreJobs bool) (models.Companies, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreCompany", ctx, companyID, restoreJobs)
+	ret0, _ := ret[0].(models.Companies)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreCompany indicates an expected call of RestoreCompany.
+func (mr *MockCompanyServiceMockRecorder) RestoreCompany(ctx, companyID, restoreJobs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreCompany", reflect.TypeOf((*MockCompanyService)(nil).RestoreCompany), ctx, companyID, restoreJobs)
+}
+
+// TransferCompanyOwnership mocks base method.
+func (m *MockCompanyService) TransferCompanyOwnership(ctx context.Context, companyID uint, email string) (models.Companies, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferCompanyOwnership", ctx, companyID, email)
+	ret0, _ := ret[0].(models.Companies)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferCompanyOwnership indicates an expected call of TransferCompanyOwnership.
+func (mr *MockCompanyServiceMockRecorder) TransferCompanyOwnership(ctx, companyID, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferCompanyOwnership", reflect.TypeOf((*MockCompanyService)(nil).TransferCompanyOwnership), ctx, companyID, email)
+}
+
+// UpdateCompany mocks base method.
+func (m *MockCompanyService) UpdateCompany(ctx context.Context, companyID uint, uc models.UpdateCompany, userId string) (models.Companies, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCompany", ctx, companyID, uc, userId)
+	ret0, _ := ret[0].(models.Companies)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCompany indicates an expected call of UpdateCompany.
+func (mr *MockCompanyServiceMockRecorder) UpdateCompany(ctx, companyID, uc, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCompany", reflect.TypeOf((*MockCompanyService)(nil).UpdateCompany), ctx, companyID, uc, userId)
+}
+
+// ViewCompanies mocks base method.
+func (m *MockCompanyService) ViewCompanies(ctx context.Context, p models.PageRequest, companyId string) ([]models.Companies, models.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewCompanies", ctx, p, companyId)
+	ret0, _ := ret[0].([]models.Companies)
+	ret1, _ := ret[1].(models.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ViewCompanies indicates an expected call of ViewCompanies.
+func (mr *MockCompanyServiceMockRecorder) ViewCompanies(ctx, p, companyId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCompanies", reflect.TypeOf((*MockCompanyService)(nil).ViewCompanies), ctx, p, companyId)
+}
+
+// ViewCompaniesById mocks base method.
+func (m *MockCompanyService) ViewCompaniesById(ctx context.Context, companybyid uint, userId string) ([]models.Companies, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewCompaniesById", ctx, companybyid, userId)
+	ret0, _ := ret[0].([]models.Companies)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewCompaniesById indicates an expected call of ViewCompaniesById.
+func (mr *MockCompanyServiceMockRecorder) ViewCompaniesById(ctx, companybyid, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCompaniesById", reflect.TypeOf((*MockCompanyService)(nil).ViewCompaniesById), ctx, companybyid, userId)
+}
+
+// MockJobService is a mock of JobService interface.
+type MockJobService struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobServiceMockRecorder
+}
+
+// MockJobServiceMockRecorder is the mock recorder for MockJobService.
+type MockJobServiceMockRecorder struct {
+	mock *MockJobService
+}
+
+// NewMockJobService creates a new mock instance.
+func NewMockJobService(ctrl *gomock.Controller) *MockJobService {
+	mock := &MockJobService{ctrl: ctrl}
+	mock.recorder = &MockJobServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobService) EXPECT() *MockJobServiceMockRecorder {
+	return m.recorder
+}
+
+// AllJob mocks base method.
+func (m *MockJobService) AllJob(ctx context.Context, q models.JobQuery, p models.PageRequest, userId string) ([]models.Job, models.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllJob", ctx, q, p, userId)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(models.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AllJob indicates an expected call of AllJob.
+func (mr *MockJobServiceMockRecorder) AllJob(ctx, q, p, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllJob", reflect.TypeOf((*MockJobService)(nil).AllJob), ctx, q, p, userId)
+}
+
+// CloseJob mocks base method.
+func (m *MockJobService) CloseJob(ctx context.Context, jobID uint64, userId string) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseJob", ctx, jobID, userId)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseJob indicates an expected call of CloseJob.
+func (mr *MockJobServiceMockRecorder) CloseJob(ctx, jobID, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseJob", reflect.TypeOf((*MockJobService)(nil).CloseJob), ctx, jobID, userId)
+}
+
+// CreateJob mocks base method.
+func (m *MockJobService) CreateJob(ctx context.Context, newJob models.Job, userId string) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJob", ctx, newJob, userId)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateJob indicates an expected call of CreateJob.
+func (mr *MockJobServiceMockRecorder) CreateJob(ctx, newJob, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJob", reflect.TypeOf((*MockJobService)(nil).CreateJob), ctx, newJob, userId)
+}
+
+// DeleteJob mocks base method.
+func (m *MockJobService) DeleteJob(ctx context.Context, jobID uint64, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteJob", ctx, jobID, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteJob indicates an expected call of DeleteJob.
+func (mr *MockJobServiceMockRecorder) DeleteJob(ctx, jobID, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteJob", reflect.TypeOf((*MockJobService)(nil).DeleteJob), ctx, jobID, userId)
+}
+
+// JobFacets mocks base method.
+func (m *MockJobService) JobFacets(ctx context.Context, q models.JobQuery) (models.JobFacets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JobFacets", ctx, q)
+	ret0, _ := ret[0].(models.JobFacets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JobFacets indicates an expected call of JobFacets.
+func (mr *MockJobServiceMockRecorder) JobFacets(ctx, q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JobFacets", reflect.TypeOf((*MockJobService)(nil).JobFacets), ctx, q)
+}
+
+// JobsByID mocks base method.
+func (m *MockJobService) JobsByID(ctx context.Context, jobID uint64, userId string) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JobsByID", ctx, jobID, userId)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JobsByID indicates an expected call of JobsByID.
+func (mr *MockJobServiceMockRecorder) JobsByID(ctx, jobID, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JobsByID", reflect.TypeOf((*MockJobService)(nil).JobsByID), ctx, jobID, userId)
+}
+
+// ListJobs mocks base method.
+func (m *MockJobService) ListJobs(ctx context.Context, companyId uint, p models.PageRequest, userId string) ([]models.Job, models.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListJobs", ctx, companyId, p, userId)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(models.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListJobs indicates an expected call of ListJobs.
+func (mr *MockJobServiceMockRecorder) ListJobs(ctx, companyId, p, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobs", reflect.TypeOf((*MockJobService)(nil).ListJobs), ctx, companyId, p, userId)
+}
+
+// PatchJob mocks base method.
+func (m *MockJobService) PatchJob(ctx context.Context, jobID uint64, jp models.JobPatch, userId string) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchJob", ctx, jobID, jp, userId)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchJob indicates an expected call of PatchJob.
+func (mr *MockJobServiceMockRecorder) PatchJob(ctx, jobID, jp, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchJob", reflect.TypeOf((*MockJobService)(nil).PatchJob), ctx, jobID, jp, userId)
+}
+
+// RestoreJob mocks base method.
+func (m *MockJobService) RestoreJob(ctx context.Context, jobID uint64) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreJob", ctx, jobID)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreJob indicates an expected call of RestoreJob.
+func (mr *MockJobServiceMockRecorder) RestoreJob(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreJob", reflect.TypeOf((*MockJobService)(nil).RestoreJob), ctx, jobID)
+}
+
+// SearchJobs mocks base method.
+func (m *MockJobService) SearchJobs(ctx context.Context, q string, p models.PageRequest) ([]models.JobSearchResult, models.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchJobs", ctx, q, p)
+	ret0, _ := ret[0].([]models.JobSearchResult)
+	ret1, _ := ret[1].(models.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchJobs indicates an expected call of SearchJobs.
+func (mr *MockJobServiceMockRecorder) SearchJobs(ctx, q, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchJobs", reflect.TypeOf((*MockJobService)(nil).SearchJobs), ctx, q, p)
+}
+
+// UpdateJob mocks base method.
+func (m *MockJobService) UpdateJob(ctx context.Context, jobID uint64, uj models.UpdateJob, userId string) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJob", ctx, jobID, uj, userId)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateJob indicates an expected call of UpdateJob.
+func (mr *MockJobServiceMockRecorder) UpdateJob(ctx, jobID, uj, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJob", reflect.TypeOf((*MockJobService)(nil).UpdateJob), ctx, jobID, uj, userId)
+}
+
+// MockApplicationService is a mock of ApplicationService interface.
+type MockApplicationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockApplicationServiceMockRecorder
+}
+
+// MockApplicationServiceMockRecorder is the mock recorder for MockApplicationService.
+type MockApplicationServiceMockRecorder struct {
+	mock *MockApplicationService
+}
+
+// NewMockApplicationService creates a new mock instance.
+func NewMockApplicationService(ctrl *gomock.Controller) *MockApplicationService {
+	mock := &MockApplicationService{ctrl: ctrl}
+	mock.recorder = &MockApplicationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApplicationService) EXPECT() *MockApplicationServiceMockRecorder {
+	return m.recorder
+}
+
+// ApplicationHistory mocks base method.
+func (m *MockApplicationService) ApplicationHistory(ctx context.Context, applicationID uint, userId string) ([]models.ApplicationStageChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationHistory", ctx, applicationID, userId)
+	ret0, _ := ret[0].([]models.ApplicationStageChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplicationHistory indicates an expected call of ApplicationHistory.
+func (mr *MockApplicationServiceMockRecorder) ApplicationHistory(ctx, applicationID, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationHistory", reflect.TypeOf((*MockApplicationService)(nil).ApplicationHistory), ctx, applicationID, userId)
+}
+
+// Apply mocks base method.
+func (m *MockApplicationService) Apply(ctx context.Context, jobID uint64, na models.NewApplication, userId string) (models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Apply", ctx, jobID, na, userId)
+	ret0, _ := ret[0].(models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Apply indicates an expected call of Apply.
+func (mr *MockApplicationServiceMockRecorder) Apply(ctx, jobID, na, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockApplicationService)(nil).Apply), ctx, jobID, na, userId)
+}
+
+// JobApplications mocks base method.
+func (m *MockApplicationService) JobApplications(ctx context.Context, jobID uint64, p models.PageRequest, userId string) ([]models.Application, models.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JobApplications", ctx, jobID, p, userId)
+	ret0, _ := ret[0].([]models.Application)
+	ret1, _ := ret[1].(models.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// JobApplications indicates an expected call of JobApplications.
+func (mr *MockApplicationServiceMockRecorder) JobApplications(ctx, jobID, p, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JobApplications", reflect.TypeOf((*MockApplicationService)(nil).JobApplications), ctx, jobID, p, userId)
+}
+
+// MoveApplications mocks base method.
+func (m *MockApplicationService) MoveApplications(ctx context.Context, bm models.BulkMove, userId string) ([]models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveApplications", ctx, bm, userId)
+	ret0, _ := ret[0].([]models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveApplications indicates an expected call of MoveApplications.
+func (mr *MockApplicationServiceMockRecorder) MoveApplications(ctx, bm, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveApplications", reflect.TypeOf((*MockApplicationService)(nil).MoveApplications), ctx, bm, userId)
+}
+
+// MyApplications mocks base method.
+func (m *MockApplicationService) MyApplications(ctx context.Context, p models.PageRequest, userId string) ([]models.Application, models.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MyApplications", ctx, p, userId)
+	ret0, _ := ret[0].([]models.Application)
+	ret1, _ := ret[1].(models.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// MyApplications indicates an expected call of MyApplications.
+func (mr *MockApplicationServiceMockRecorder) MyApplications(ctx, p, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MyApplications", reflect.TypeOf((*MockApplicationService)(nil).MyApplications), ctx, p, userId)
+}
+
+// RejectApplications mocks base method.
+func (m *MockApplicationService) RejectApplications(ctx context.Context, br models.BulkReject, userId string) ([]models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectApplications", ctx, br, userId)
+	ret0, _ := ret[0].([]models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RejectApplications indicates an expected call of RejectApplications.
+func (mr *MockApplicationServiceMockRecorder) RejectApplications(ctx, br, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectApplications", reflect.TypeOf((*MockApplicationService)(nil).RejectApplications), ctx, br, userId)
+}
+
+// SetPipeline mocks base method.
+func (m *MockApplicationService) SetPipeline(ctx context.Context, companyID uint, np models.NewPipeline, userId string) ([]models.PipelineStage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPipeline", ctx, companyID, np, userId)
+	ret0, _ := ret[0].([]models.PipelineStage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetPipeline indicates an expected call of SetPipeline.
+func (mr *MockApplicationServiceMockRecorder) SetPipeline(ctx, companyID, np, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPipeline", reflect.TypeOf((*MockApplicationService)(nil).SetPipeline), ctx, companyID, np, userId)
+}
+
+// ViewPipeline mocks base method.
+func (m *MockApplicationService) ViewPipeline(ctx context.Context, companyID uint, userId string) ([]models.PipelineStage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewPipeline", ctx, companyID, userId)
+	ret0, _ := ret[0].([]models.PipelineStage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewPipeline indicates an expected call of ViewPipeline.
+func (mr *MockApplicationServiceMockRecorder) ViewPipeline(ctx, companyID, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewPipeline", reflect.TypeOf((*MockApplicationService)(nil).ViewPipeline), ctx, companyID, userId)
+}
+
+// WithdrawApplication mocks base method.
+func (m *MockApplicationService) WithdrawApplication(ctx context.Context, applicationID uint, userId string) (models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawApplication", ctx, applicationID, userId)
+	ret0, _ := ret[0].(models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WithdrawApplication indicates an expected call of WithdrawApplication.
+func (mr *MockApplicationServiceMockRecorder) WithdrawApplication(ctx, applicationID, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawApplication", reflect.TypeOf((*MockApplicationService)(nil).WithdrawApplication), ctx, applicationID, userId)
+}
@@ -3,36 +3,126 @@ package services
 import (
 	"context"
 	"errors"
+	"job-portal-api/internal/auth"
 	"job-portal-api/internal/models"
 	"job-portal-api/internal/repository"
-
-	"github.com/golang-jwt/jwt/v5"
 )
 
 //go:generate mockgen -source services.go -destination service_mock.go -package services
 
-type Service interface {
+// UserService signs users up and in, and manages their roles and sessions.
+type UserService interface {
+	CreateUser(ctx context.Context, nu models.NewUser) (models.User, error)
+	ResetPassword(ctx context.Context, email, password string) (models.User, error)
+	Authenticate(ctx context.Context, email, password string) (auth.Claims,
+		error)
+	UpdateUserRole(ctx context.Context, userID uint, role string) (models.User, error)
+	IssueRefreshToken(ctx context.Context, userId string) (string, error)
+	RefreshToken(ctx context.Context, refreshToken string) (auth.Claims, string, error)
+	Logout(ctx context.Context, claims auth.Claims, refreshToken string) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+// CompanyService lets employers run their companies and who works on them.
+type CompanyService interface {
 	CreatCompanies(ctx context.Context, nc models.NewComapanies, UserId uint) (models.Companies, error)
-	ViewCompanies(ctx context.Context, companyId string) ([]models.Companies, error)
+	ViewCompanies(ctx context.Context, p models.PageRequest, companyId string) ([]models.Companies, models.PageInfo, error)
 	ViewCompaniesById(ctx context.Context, companybyid uint, userId string) ([]models.Companies, error)
-	CreateUser(ctx context.Context, nu models.NewUser) (models.User, error)
+	UpdateCompany(ctx context.Context, companyID uint, uc models.UpdateCompany, userId string) (models.Companies, error)
+	PatchCompany(ctx context.Context, companyID uint, cp models.CompanyPatch, userId string) (models.Companies, error)
+	DeleteCompany(ctx context.Context, companyID uint, userId string) error
+	RestoreCompany(ctx context.Context, companyID uint, restoreJobs bool) (models.Companies, error)
+	AddCompanyMember(ctx context.Context, companyID uint, nm models.NewCompanyMember, userId string) (models.CompanyMember, error)
+	TransferCompanyOwnership(ctx context.Context, companyID uint, email string) (models.Companies, error)
+}
+
+// JobService lets companies post jobs and everyone find them.
+type JobService interface {
 	CreateJob(ctx context.Context, newJob models.Job, userId string) (models.Job, error)
-	AllJob(ctx context.Context, userId string) ([]models.Job, error)
-	ListJobs(ctx context.Context, companyId uint, userId string) ([]models.Job, error)
-	Authenticate(ctx context.Context, email, password string) (jwt.RegisteredClaims,
-		error)
+	UpdateJob(ctx context.Context, jobID uint64, uj models.UpdateJob, userId string) (models.Job, error)
+	PatchJob(ctx context.Context, jobID uint64, jp models.JobPatch, userId string) (models.Job, error)
+	CloseJob(ctx context.Context, jobID uint64, userId string) (models.Job, error)
+	DeleteJob(ctx context.Context, jobID uint64, userId string) error
+	RestoreJob(ctx context.Context, jobID uint64) (models.Job, error)
+	AllJob(ctx context.Context, q models.JobQuery, p models.PageRequest, userId string) ([]models.Job, models.PageInfo, error)
+	JobFacets(ctx context.Context, q models.JobQuery) (models.JobFacets, error)
+	SearchJobs(ctx context.Context, q string, p models.PageRequest) ([]models.JobSearchResult, models.PageInfo, error)
+	ListJobs(ctx context.Context, companyId uint, p models.PageRequest, userId string) ([]models.Job, models.PageInfo, error)
 	JobsByID(ctx context.Context, jobID uint64, userId string) (models.Job, error)
 }
 
-type Store struct {
-	UserRepo repository.UserRepo
+// ApplicationService lets candidates apply to jobs and employers move them through their hiring pipeline.
+type ApplicationService interface {
+	Apply(ctx context.Context, jobID uint64, na models.NewApplication, userId string) (models.Application, error)
+	MyApplications(ctx context.Context, p models.PageRequest, userId string) ([]models.Application, models.PageInfo, error)
+	JobApplications(ctx context.Context, jobID uint64, p models.PageRequest, userId string) ([]models.Application, models.PageInfo, error)
+	WithdrawApplication(ctx context.Context, applicationID uint, userId string) (models.Application, error)
+	ApplicationHistory(ctx context.Context, applicationID uint, userId string) ([]models.ApplicationStageChange, error)
+	MoveApplications(ctx context.Context, bm models.BulkMove, userId string) ([]models.Application, error)
+	RejectApplications(ctx context.Context, br models.BulkReject, userId string) ([]models.Application, error)
+	ViewPipeline(ctx context.Context, companyID uint, userId string) ([]models.PipelineStage, error)
+	SetPipeline(ctx context.Context, companyID uint, np models.NewPipeline, userId string) ([]models.PipelineStage, error)
+}
+
+type UserStore struct {
+	Users repository.UserStore
+}
+
+func NewUserStore(users repository.UserStore) (UserService, error) {
+	if users == nil {
+		return nil, errors.New("interface cannot be null")
+	}
+	return &UserStore{
+		Users: users,
+	}, nil
+}
+
+// CompanyStore creates companies together with their first jobs, in units of work of Tx.
+type CompanyStore struct {
+	Users     repository.UserStore
+	Companies repository.CompanyStore
+	Tx        repository.UnitOfWork
+}
+
+func NewCompanyStore(users repository.UserStore, companies repository.CompanyStore, tx repository.UnitOfWork) (CompanyService, error) {
+	if users == nil || companies == nil || tx == nil {
+		return nil, errors.New("interface cannot be null")
+	}
+	return &CompanyStore{
+		Users:     users,
+		Companies: companies,
+		Tx:        tx,
+	}, nil
+}
+
+type JobStore struct {
+	Companies repository.CompanyStore
+	Jobs      repository.JobStore
+}
+
+func NewJobStore(companies repository.CompanyStore, jobs repository.JobStore) (JobService, error) {
+	if companies == nil || jobs == nil {
+		return nil, errors.New("interface cannot be null")
+	}
+	return &JobStore{
+		Companies: companies,
+		Jobs:      jobs,
+	}, nil
+}
+
+type ApplicationStore struct {
+	Companies       repository.CompanyStore
+	Jobs            repository.JobStore
+	ApplicationRepo repository.ApplicationRepo
 }
 
-func NewStore(userRepo repository.UserRepo) (Service, error) {
-	if userRepo == nil {
+func NewApplicationStore(companies repository.CompanyStore, jobs repository.JobStore, applicationRepo repository.ApplicationRepo) (ApplicationService, error) {
+	if companies == nil || jobs == nil || applicationRepo == nil {
 		return nil, errors.New("interface cannot be null")
 	}
-	return &Store{
-		UserRepo: userRepo,
+	return &ApplicationStore{
+		Companies:       companies,
+		Jobs:            jobs,
+		ApplicationRepo: applicationRepo,
 	}, nil
 }
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/models"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const refreshTokenTTL = 30 * 24 * time.Hour
+
+// IssueRefreshToken creates a refresh token for the user starting a new token family.
+func (s *UserStore) IssueRefreshToken(ctx context.Context, userID string) (string, error) {
+	uid, err := strconv.ParseUint(userID, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("parsing user id: %w", err)
+	}
+	return s.issueRefreshToken(ctx, uint(uid), uuid.NewString())
+}
+
+func (s *UserStore) issueRefreshToken(ctx context.Context, userID uint, familyID string) (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("generating refresh token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	_, err = s.Users.CreateRefreshToken(ctx, models.RefreshToken{
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// RefreshToken exchanges a refresh token for the claims of a new access token and a new
+// refresh token in the same family. Presenting a token that was already used revokes the
+// whole family, since either the client or an attacker holds a stolen copy.
+func (s *UserStore) RefreshToken(ctx context.Context, refreshToken string) (auth.Claims, string, error) {
+	rt, err := s.Users.ViewRefreshToken(ctx, hashToken(refreshToken))
+	if errors.Is(err, apperr.ErrNotFound) {
+		return auth.Claims{}, "", fmt.Errorf("unknown refresh token: %w", apperr.ErrUnauthorized)
+	}
+	if err != nil {
+		return auth.Claims{}, "", err
+	}
+
+	revoked, err := s.Users.RevokeRefreshToken(ctx, rt.ID)
+	if err != nil {
+		return auth.Claims{}, "", err
+	}
+	if !revoked {
+		log.Warn().Str("family", rt.FamilyID).Uint("user", rt.UserID).Msg("refresh token reused, revoking family")
+		err = s.Users.RevokeTokenFamily(ctx, rt.FamilyID)
+		if err != nil {
+			return auth.Claims{}, "", err
+		}
+		return auth.Claims{}, "", fmt.Errorf("refresh token reused: %w", apperr.ErrUnauthorized)
+	}
+	if time.Now().After(rt.ExpiresAt) {
+		return auth.Claims{}, "", fmt.Errorf("refresh token expired: %w", apperr.ErrUnauthorized)
+	}
+
+	claims, err := s.Users.ViewUserClaims(ctx, rt.UserID)
+	if err != nil {
+		return auth.Claims{}, "", err
+	}
+	token, err := s.issueRefreshToken(ctx, rt.UserID, rt.FamilyID)
+	if err != nil {
+		return auth.Claims{}, "", err
+	}
+	return claims, token, nil
+}
+
+// Logout revokes the access token described by claims and, when given, the family of the refresh token.
+func (s *UserStore) Logout(ctx context.Context, claims auth.Claims, refreshToken string) error {
+	var expiresAt time.Time
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+	err := s.Users.RevokeAccessToken(ctx, claims.ID, expiresAt)
+	if err != nil {
+		return err
+	}
+	if refreshToken == "" {
+		return nil
+	}
+
+	rt, err := s.Users.ViewRefreshToken(ctx, hashToken(refreshToken))
+	if errors.Is(err, apperr.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if strconv.FormatUint(uint64(rt.UserID), 10) != claims.Subject {
+		return fmt.Errorf("refresh token belongs to another user: %w", apperr.ErrForbidden)
+	}
+	return s.Users.RevokeTokenFamily(ctx, rt.FamilyID)
+}
+
+func (s *UserStore) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	return s.Users.IsAccessTokenRevoked(ctx, jti)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
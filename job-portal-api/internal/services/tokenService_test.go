@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"testing"
+	"time"
+)
+
+func TestUserStore_RefreshToken(t *testing.T) {
+	stored := models.RefreshToken{
+		Model:     gorm.Model{ID: 3},
+		UserID:    1,
+		TokenHash: hashToken("old-token"),
+		FamilyID:  "family",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	tests := []struct {
+		name      string
+		setup     func(m *repository.MockUserStore)
+		wantErrIs error
+		wantErr   bool
+	}{
+		{
+			name: "unknown token",
+			setup: func(m *repository.MockUserStore) {
+				m.EXPECT().ViewRefreshToken(gomock.Any(), hashToken("old-token")).Return(models.RefreshToken{}, apperr.ErrNotFound)
+			},
+			wantErr:   true,
+			wantErrIs: apperr.ErrUnauthorized,
+		},
+		{
+			name: "reused token revokes the family",
+			setup: func(m *repository.MockUserStore) {
+				m.EXPECT().ViewRefreshToken(gomock.Any(), hashToken("old-token")).Return(stored, nil)
+				m.EXPECT().RevokeRefreshToken(gomock.Any(), uint(3)).Return(false, nil)
+				m.EXPECT().RevokeTokenFamily(gomock.Any(), "family").Return(nil)
+			},
+			wantErr:   true,
+			wantErrIs: apperr.ErrUnauthorized,
+		},
+		{
+			name: "expired token",
+			setup: func(m *repository.MockUserStore) {
+				expired := stored
+				expired.ExpiresAt = time.Now().Add(-time.Minute)
+				m.EXPECT().ViewRefreshToken(gomock.Any(), hashToken("old-token")).Return(expired, nil)
+				m.EXPECT().RevokeRefreshToken(gomock.Any(), uint(3)).Return(true, nil)
+			},
+			wantErr:   true,
+			wantErrIs: apperr.ErrUnauthorized,
+		},
+		{
+			name: "database error",
+			setup: func(m *repository.MockUserStore) {
+				m.EXPECT().ViewRefreshToken(gomock.Any(), hashToken("old-token")).Return(models.RefreshToken{}, errors.New("database error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "rotates the token within the family",
+			setup: func(m *repository.MockUserStore) {
+				m.EXPECT().ViewRefreshToken(gomock.Any(), hashToken("old-token")).Return(stored, nil)
+				m.EXPECT().RevokeRefreshToken(gomock.Any(), uint(3)).Return(true, nil)
+				m.EXPECT().ViewUserClaims(gomock.Any(), uint(1)).Return(auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}}, nil)
+				m.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, rt models.RefreshToken) (models.RefreshToken, error) {
+						if rt.FamilyID != "family" || rt.UserID != 1 || rt.TokenHash == hashToken("old-token") {
+							t.Errorf("unexpected rotated token %+v", rt)
+						}
+						return rt, nil
+					})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserStore(mc)
+			tt.setup(mockRepo)
+			s, err := NewUserStore(mockRepo)
+			if err != nil {
+				t.Fatalf("error creating UserStore: %v", err)
+			}
+
+			claims, token, err := s.RefreshToken(context.Background(), "old-token")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RefreshToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("RefreshToken() error = %v, want %v", err, tt.wantErrIs)
+				return
+			}
+			if !tt.wantErr && (claims.Subject != "1" || token == "" || token == "old-token") {
+				t.Errorf("RefreshToken() got = %v, %q", claims, token)
+			}
+		})
+	}
+}
+
+func TestUserStore_Logout(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	claims := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{
+		ID:        "jti",
+		Subject:   "1",
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}}
+	tests := []struct {
+		name         string
+		refreshToken string
+		setup        func(m *repository.MockUserStore)
+		wantErrIs    error
+		wantErr      bool
+	}{
+		{
+			name: "access token only",
+			setup: func(m *repository.MockUserStore) {
+				m.EXPECT().RevokeAccessToken(gomock.Any(), "jti", expiresAt).Return(nil)
+			},
+		},
+		{
+			name:         "revokes the refresh token family",
+			refreshToken: "refresh",
+			setup: func(m *repository.MockUserStore) {
+				m.EXPECT().RevokeAccessToken(gomock.Any(), "jti", expiresAt).Return(nil)
+				m.EXPECT().ViewRefreshToken(gomock.Any(), hashToken("refresh")).Return(models.RefreshToken{UserID: 1, FamilyID: "family"}, nil)
+				m.EXPECT().RevokeTokenFamily(gomock.Any(), "family").Return(nil)
+			},
+		},
+		{
+			name:         "refresh token of another user",
+			refreshToken: "refresh",
+			setup: func(m *repository.MockUserStore) {
+				m.EXPECT().RevokeAccessToken(gomock.Any(), "jti", expiresAt).Return(nil)
+				m.EXPECT().ViewRefreshToken(gomock.Any(), hashToken("refresh")).Return(models.RefreshToken{UserID: 2, FamilyID: "family"}, nil)
+			},
+			wantErr:   true,
+			wantErrIs: apperr.ErrForbidden,
+		},
+		{
+			name: "database error",
+			setup: func(m *repository.MockUserStore) {
+				m.EXPECT().RevokeAccessToken(gomock.Any(), "jti", expiresAt).Return(errors.New("database error"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserStore(mc)
+			tt.setup(mockRepo)
+			s, err := NewUserStore(mockRepo)
+			if err != nil {
+				t.Fatalf("error creating UserStore: %v", err)
+			}
+
+			err = s.Logout(context.Background(), claims, tt.refreshToken)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Logout() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("Logout() error = %v, want %v", err, tt.wantErrIs)
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"context"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/tracing"
+)
+
+type tracedUserService struct {
+	next UserService
+}
+
+// TraceUserService runs every method of s in a span.
+func TraceUserService(s UserService) UserService {
+	return tracedUserService{next: s}
+}
+
+func (t tracedUserService) CreateUser(ctx context.Context, nu models.NewUser) (models.User, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "UserService.CreateUser")
+	user, err := t.next.CreateUser(ctx, nu)
+	tracing.End(span, err)
+	return user, err
+}
+
+func (t tracedUserService) ResetPassword(ctx context.Context, email, password string) (models.User, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "UserService.ResetPassword")
+	user, err := t.next.ResetPassword(ctx, email, password)
+	tracing.End(span, err)
+	return user, err
+}
+
+func (t tracedUserService) Authenticate(ctx context.Context, email, password string) (auth.Claims, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "UserService.Authenticate")
+	claims, err := t.next.Authenticate(ctx, email, password)
+	tracing.End(span, err)
+	return claims, err
+}
+
+func (t tracedUserService) UpdateUserRole(ctx context.Context, userID uint, role string) (models.User, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "UserService.UpdateUserRole")
+	user, err := t.next.UpdateUserRole(ctx, userID, role)
+	tracing.End(span, err)
+	return user, err
+}
+
+func (t tracedUserService) IssueRefreshToken(ctx context.Context, userId string) (string, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "UserService.IssueRefreshToken")
+	token, err := t.next.IssueRefreshToken(ctx, userId)
+	tracing.End(span, err)
+	return token, err
+}
+
+func (t tracedUserService) RefreshToken(ctx context.Context, refreshToken string) (auth.Claims, string, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "UserService.RefreshToken")
+	claims, token, err := t.next.RefreshToken(ctx, refreshToken)
+	tracing.End(span, err)
+	return claims, token, err
+}
+
+func (t tracedUserService) Logout(ctx context.Context, claims auth.Claims, refreshToken string) error {
+	ctx, span := tracing.Tracer().Start(ctx, "UserService.Logout")
+	err := t.next.Logout(ctx, claims, refreshToken)
+	tracing.End(span, err)
+	return err
+}
+
+func (t tracedUserService) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "UserService.IsTokenRevoked")
+	revoked, err := t.next.IsTokenRevoked(ctx, jti)
+	tracing.End(span, err)
+	return revoked, err
+}
+
+type tracedCompanyService struct {
+	next CompanyService
+}
+
+// TraceCompanyService runs every method of s in a span.
+func TraceCompanyService(s CompanyService) CompanyService {
+	return tracedCompanyService{next: s}
+}
+
+func (t tracedCompanyService) CreatCompanies(ctx context.Context, nc models.NewComapanies, UserId uint) (models.Companies, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "CompanyService.CreatCompanies")
+	company, err := t.next.CreatCompanies(ctx, nc, UserId)
+	tracing.End(span, err)
+	return company, err
+}
+
+func (t tracedCompanyService) ViewCompanies(ctx context.Context, p models.PageRequest, companyId string) ([]models.Companies, models.PageInfo, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "CompanyService.ViewCompanies")
+	companies, info, err := t.next.ViewCompanies(ctx, p, companyId)
+	tracing.End(span, err)
+	return companies, info, err
+}
+
+func (t tracedCompanyService) ViewCompaniesById(ctx context.Context, companybyid uint, userId string) ([]models.Companies, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "CompanyService.ViewCompaniesById")
+	companies, err := t.next.ViewCompaniesById(ctx, companybyid, userId)
+	tracing.End(span, err)
+	return companies, err
+}
+
+func (t tracedCompanyService) UpdateCompany(ctx context.Context, companyID uint, uc models.UpdateCompany, userId string) (models.Companies, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "CompanyService.UpdateCompany")
+	company, err := t.next.UpdateCompany(ctx, companyID, uc, userId)
+	tracing.End(span, err)
+	return company, err
+}
+
+func (t tracedCompanyService) PatchCompany(ctx context.Context, companyID uint, cp models.CompanyPatch, userId string) (models.Companies, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "CompanyService.PatchCompany")
+	company, err := t.next.PatchCompany(ctx, companyID, cp, userId)
+	tracing.End(span, err)
+	return company, err
+}
+
+func (t tracedCompanyService) DeleteCompany(ctx context.Context, companyID uint, userId string) error {
+	ctx, span := tracing.Tracer().Start(ctx, "CompanyService.DeleteCompany")
+	err := t.next.DeleteCompany(ctx, companyID, userId)
+	tracing.End(span, err)
+	return err
+}
+
+func (t tracedCompanyService) RestoreCompany(ctx context.Context, companyID uint, restoreJobs bool) (models.Companies, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "CompanyService.RestoreCompany")
+	company, err := t.next.RestoreCompany(ctx, companyID, restoreJobs)
+	tracing.End(span, err)
+	return company, err
+}
+
+func (t tracedCompanyService) AddCompanyMember(ctx context.Context, companyID uint, nm models.NewCompanyMember, userId string) (models.CompanyMember, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "CompanyService.AddCompanyMember")
+	member, err := t.next.AddCompanyMember(ctx, companyID, nm, userId)
+	tracing.End(span, err)
+	return member, err
+}
+
+func (t tracedCompanyService) TransferCompanyOwnership(ctx context.Context, companyID uint, email string) (models.Companies, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "CompanyService.TransferCompanyOwnership")
+	company, err := t.next.TransferCompanyOwnership(ctx, companyID, email)
+	tracing.End(span, err)
+	return company, err
+}
+
+type tracedJobService struct {
+	next JobService
+}
+
+// TraceJobService runs every method of s in a span.
+func TraceJobService(s JobService) JobService {
+	return tracedJobService{next: s}
+}
+
+func (t tracedJobService) CreateJob(ctx context.Context, newJob models.Job, userId string) (models.Job, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "JobService.CreateJob")
+	job, err := t.next.CreateJob(ctx, newJob, userId)
+	tracing.End(span, err)
+	return job, err
+}
+
+func (t tracedJobService) UpdateJob(ctx context.Context, jobID uint64, uj models.UpdateJob, userId string) (models.Job, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "JobService.UpdateJob")
+	job, err := t.next.UpdateJob(ctx, jobID, uj, userId)
+	tracing.End(span, err)
+	return job, err
+}
+
+func (t tracedJobService) PatchJob(ctx context.Context, jobID uint64, jp models.JobPatch, userId string) (models.Job, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "JobService.PatchJob")
+	job, err := t.next.PatchJob(ctx, jobID, jp, userId)
+	tracing.End(span, err)
+	return job, err
+}
+
+func (t tracedJobService) CloseJob(ctx context.Context, jobID uint64, userId string) (models.Job, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "JobService.CloseJob")
+	job, err := t.next.CloseJob(ctx, jobID, userId)
+	tracing.End(span, err)
+	return job, err
+}
+
+func (t tracedJobService) DeleteJob(ctx context.Context, jobID uint64, userId string) error {
+	ctx, span := tracing.Tracer().Start(ctx, "JobService.DeleteJob")
+	err := t.next.DeleteJob(ctx, jobID, userId)
+	tracing.End(span, err)
+	return err
+}
+
+func (t tracedJobService) RestoreJob(ctx context.Context, jobID uint64) (models.Job, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "JobService.RestoreJob")
+	job, err := t.next.RestoreJob(ctx, jobID)
+	tracing.End(span, err)
+	return job, err
+}
+
+func (t tracedJobService) AllJob(ctx context.Context, q models.JobQuery, p models.PageRequest, userId string) ([]models.Job, models.PageInfo, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "JobService.AllJob")
+	jobs, info, err := t.next.AllJob(ctx, q, p, userId)
+	tracing.End(span, err)
+	return jobs, info, err
+}
+
+func (t tracedJobService) JobFacets(ctx context.Context, q models.JobQuery) (models.JobFacets, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "JobService.JobFacets")
+	facets, err := t.next.JobFacets(ctx, q)
+	tracing.End(span, err)
+	return facets, err
+}
+
+func (t tracedJobService) SearchJobs(ctx context.Context, q string, p models.PageRequest) ([]models.JobSearchResult, models.PageInfo, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "JobService.SearchJobs")
+	results, info, err := t.next.SearchJobs(ctx, q, p)
+	tracing.End(span, err)
+	return results, info, err
+}
+
+func (t tracedJobService) ListJobs(ctx context.Context, companyId uint, p models.PageRequest, userId string) ([]models.Job, models.PageInfo, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "JobService.ListJobs")
+	jobs, info, err := t.next.ListJobs(ctx, companyId, p, userId)
+	tracing.End(span, err)
+	return jobs, info, err
+}
+
+func (t tracedJobService) JobsByID(ctx context.Context, jobID uint64, userId string) (models.Job, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "JobService.JobsByID")
+	job, err := t.next.JobsByID(ctx, jobID, userId)
+	tracing.End(span, err)
+	return job, err
+}
+
+type tracedApplicationService struct {
+	next ApplicationService
+}
+
+// TraceApplicationService runs every method of s in a span.
+func TraceApplicationService(s ApplicationService) ApplicationService {
+	return tracedApplicationService{next: s}
+}
+
+func (t tracedApplicationService) Apply(ctx context.Context, jobID uint64, na models.NewApplication, userId string) (models.Application, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ApplicationService.Apply")
+	app, err := t.next.Apply(ctx, jobID, na, userId)
+	tracing.End(span, err)
+	return app, err
+}
+
+func (t tracedApplicationService) MyApplications(ctx context.Context, p models.PageRequest, userId string) ([]models.Application, models.PageInfo, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ApplicationService.MyApplications")
+	apps, info, err := t.next.MyApplications(ctx, p, userId)
+	tracing.End(span, err)
+	return apps, info, err
+}
+
+func (t tracedApplicationService) JobApplications(ctx context.Context, jobID uint64, p models.PageRequest, userId string) ([]models.Application, models.PageInfo, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ApplicationService.JobApplications")
+	apps, info, err := t.next.JobApplications(ctx, jobID, p, userId)
+	tracing.End(span, err)
+	return apps, info, err
+}
+
+func (t tracedApplicationService) WithdrawApplication(ctx context.Context, applicationID uint, userId string) (models.Application, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ApplicationService.WithdrawApplication")
+	app, err := t.next.WithdrawApplication(ctx, applicationID, userId)
+	tracing.End(span, err)
+	return app, err
+}
+
+func (t tracedApplicationService) ApplicationHistory(ctx context.Context, applicationID uint, userId string) ([]models.ApplicationStageChange, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ApplicationService.ApplicationHistory")
+	changes, err := t.next.ApplicationHistory(ctx, applicationID, userId)
+	tracing.End(span, err)
+	return changes, err
+}
+
+func (t tracedApplicationService) MoveApplications(ctx context.Context, bm models.BulkMove, userId string) ([]models.Application, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ApplicationService.MoveApplications")
+	apps, err := t.next.MoveApplications(ctx, bm, userId)
+	tracing.End(span, err)
+	return apps, err
+}
+
+func (t tracedApplicationService) RejectApplications(ctx context.Context, br models.BulkReject, userId string) ([]models.Application, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ApplicationService.RejectApplications")
+	apps, err := t.next.RejectApplications(ctx, br, userId)
+	tracing.End(span, err)
+	return apps, err
+}
+
+func (t tracedApplicationService) ViewPipeline(ctx context.Context, companyID uint, userId string) ([]models.PipelineStage, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ApplicationService.ViewPipeline")
+	stages, err := t.next.ViewPipeline(ctx, companyID, userId)
+	tracing.End(span, err)
+	return stages, err
+}
+
+func (t tracedApplicationService) SetPipeline(ctx context.Context, companyID uint, np models.NewPipeline, userId string) ([]models.PipelineStage, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ApplicationService.SetPipeline")
+	stages, err := t.next.SetPipeline(ctx, companyID, np, userId)
+	tracing.End(span, err)
+	return stages, err
+}
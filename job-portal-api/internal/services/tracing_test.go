@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTraceServices(t *testing.T) {
+	tp := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(tp) })
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+
+	mc := gomock.NewController(t)
+	users := NewMockUserService(mc)
+	jobs := NewMockJobService(mc)
+	var inner trace.SpanContext
+	jobs.EXPECT().JobsByID(gomock.Any(), uint64(5), "1").DoAndReturn(
+		func(ctx context.Context, jobID uint64, userId string) (models.Job, error) {
+			inner = trace.SpanContextFromContext(ctx)
+			return models.Job{Title: "Backend Engineer"}, nil
+		})
+	users.EXPECT().Authenticate(gomock.Any(), "a@example.com", "wrong").
+		Return(auth.Claims{}, apperr.ErrUnauthorized)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "GET /api/jobs/:jobID")
+	job, err := TraceJobService(jobs).JobsByID(ctx, 5, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "Backend Engineer", job.Title)
+	_, err = TraceUserService(users).Authenticate(ctx, "a@example.com", "wrong")
+	assert.ErrorIs(t, err, apperr.ErrUnauthorized)
+	parent.End()
+
+	spans := sr.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "JobService.JobsByID", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, spans[0].SpanContext(), inner, "the service runs in its span")
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, "UserService.Authenticate", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
@@ -2,37 +2,75 @@ package services
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"job-portal-api/internal/auth"
 	"job-portal-api/internal/models"
 
-	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
-func (s *Store) CreateUser(ctx context.Context, nu models.NewUser) (models.User, error) {
-	// We hash the user's password for storage in the database.
-	hashedPass, err := bcrypt.GenerateFromPassword([]byte(nu.Password), bcrypt.DefaultCost)
+func (s *UserStore) CreateUser(ctx context.Context, nu models.NewUser) (models.User, error) {
+	hashedPass, err := hashPassword(nu.Password)
 	if err != nil {
-		return models.User{}, fmt.Errorf("generating password hash: %w", err)
+		return models.User{}, err
+	}
+	role := nu.Role
+	if role == "" {
+		role = models.RoleCandidate
 	}
 	u := models.User{
 		Name:         nu.Name,
 		Email:        nu.Email,
-		PasswordHash: string(hashedPass),
+		PasswordHash: hashedPass,
+		Role:         role,
 	}
-	user, err := s.UserRepo.CreateUser(ctx, u)
+	user, err := s.Users.CreateUser(ctx, u)
 	if err != nil {
 		return models.User{}, err
 	}
 	return user, nil
 }
 
-func (s *Store) Authenticate(ctx context.Context, email, password string) (jwt.RegisteredClaims,
+func (s *UserStore) Authenticate(ctx context.Context, email, password string) (auth.Claims,
 	error) {
-	claims, err := s.UserRepo.CheckEmail(ctx, email, password)
+	claims, err := s.Users.CheckEmail(ctx, email, password)
 	if err != nil {
-		return jwt.RegisteredClaims{}, errors.New("not able to generate claims")
+		return auth.Claims{}, err
 	}
 	return claims, nil
 }
+
+func (s *UserStore) UpdateUserRole(ctx context.Context, userID uint, role string) (models.User, error) {
+	user, err := s.Users.UpdateUserRole(ctx, userID, role)
+	if err != nil {
+		return models.User{}, err
+	}
+	return user, nil
+}
+
+// ResetPassword gives the user registered with email a new password and ends its sessions.
+func (s *UserStore) ResetPassword(ctx context.Context, email, password string) (models.User, error) {
+	user, err := s.Users.ViewUserByEmail(ctx, email)
+	if err != nil {
+		return models.User{}, err
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return models.User{}, err
+	}
+	err = s.Users.UpdatePassword(ctx, user.ID, hash)
+	if err != nil {
+		return models.User{}, err
+	}
+	user.PasswordHash = hash
+	return user, nil
+}
+
+// hashPassword hashes a password for storage in the database.
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("generating password hash: %w", err)
+	}
+	return string(hash), nil
+}
@@ -6,13 +6,17 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/rs/zerolog/log"
 	"go.uber.org/mock/gomock"
+	"job-portal-api/internal/auth"
 	"job-portal-api/internal/models"
 	"job-portal-api/internal/repository"
 	"reflect"
 	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
-func TestStore_CreateUser(t *testing.T) {
+func TestUserStore_CreateUser(t *testing.T) {
 
 	type args struct {
 		ctx context.Context
@@ -71,28 +75,28 @@ func TestStore_CreateUser(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mc := gomock.NewController(t)
-			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo := repository.NewMockUserStore(mc)
 			if tt.mockRepoResponse != nil {
 				mockRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(tt.mockRepoResponse()).AnyTimes()
 			}
-			s, err := NewStore(mockRepo)
+			s, err := NewUserStore(mockRepo)
 			if err != nil {
 				log.Print(err)
 				return
 			}
 			got, err := s.CreateUser(tt.args.ctx, tt.args.nu)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("Store.CreateUser() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("UserStore.CreateUser() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("Store.CreateUser() got = %v, want %v", got, tt.want)
+				t.Errorf("UserStore.CreateUser() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestStore_Authenticate(t *testing.T) {
+func TestUserStore_Authenticate(t *testing.T) {
 	type args struct {
 		ctx      context.Context
 		email    string
@@ -101,9 +105,9 @@ func TestStore_Authenticate(t *testing.T) {
 	tests := []struct {
 		name             string
 		args             args
-		want             jwt.RegisteredClaims
+		want             auth.Claims
 		wantErr          bool
-		mockRepoResponse func() (jwt.RegisteredClaims, error)
+		mockRepoResponse func() (auth.Claims, error)
 	}{
 		{
 			name: "Error",
@@ -112,10 +116,10 @@ func TestStore_Authenticate(t *testing.T) {
 				email:    "satyam@gmail.com",
 				password: "satyam",
 			},
-			want:    jwt.RegisteredClaims{},
+			want:    auth.Claims{},
 			wantErr: true,
-			mockRepoResponse: func() (jwt.RegisteredClaims, error) {
-				return jwt.RegisteredClaims{}, errors.New("error in token")
+			mockRepoResponse: func() (auth.Claims, error) {
+				return auth.Claims{}, errors.New("error in token")
 			},
 		},
 		{
@@ -125,17 +129,23 @@ func TestStore_Authenticate(t *testing.T) {
 				email:    "satyam@gmail.com",
 				password: "satyam",
 			},
-			want: jwt.RegisteredClaims{
-				Issuer:  "satyam",
-				Subject: "1",
-				ID:      "2",
-			},
-			wantErr: false,
-			mockRepoResponse: func() (jwt.RegisteredClaims, error) {
-				return jwt.RegisteredClaims{
+			want: auth.Claims{
+				RegisteredClaims: jwt.RegisteredClaims{
 					Issuer:  "satyam",
 					Subject: "1",
 					ID:      "2",
+				},
+				Role: models.RoleEmployer,
+			},
+			wantErr: false,
+			mockRepoResponse: func() (auth.Claims, error) {
+				return auth.Claims{
+					RegisteredClaims: jwt.RegisteredClaims{
+						Issuer:  "satyam",
+						Subject: "1",
+						ID:      "2",
+					},
+					Role: models.RoleEmployer,
 				}, nil
 			},
 		},
@@ -144,14 +154,14 @@ func TestStore_Authenticate(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mc := gomock.NewController(t)
-			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo := repository.NewMockUserStore(mc)
 			if tt.mockRepoResponse != nil {
 				mockRepo.EXPECT().CheckEmail(gomock.Any(), gomock.Any(), gomock.Any()).Return(tt.mockRepoResponse()).AnyTimes()
 			}
 
-			s, err := NewStore(mockRepo)
+			s, err := NewUserStore(mockRepo)
 			if err != nil {
-				t.Fatalf("error creating Store: %v", err)
+				t.Fatalf("error creating UserStore: %v", err)
 			}
 
 			got, err := s.Authenticate(tt.args.ctx, tt.args.email, tt.args.password)
@@ -165,3 +175,30 @@ func TestStore_Authenticate(t *testing.T) {
 		})
 	}
 }
+
+func TestUserStore_ResetPassword(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserStore(mc)
+	mockRepo.EXPECT().ViewUserByEmail(gomock.Any(), "satyam@gmail.com").Return(models.User{Model: gorm.Model{ID: 4}, PasswordHash: "old"}, nil)
+	var stored string
+	mockRepo.EXPECT().UpdatePassword(gomock.Any(), uint(4), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ uint, hash string) error {
+			stored = hash
+			return nil
+		})
+	s, err := NewUserStore(mockRepo)
+	if err != nil {
+		t.Fatalf("error creating UserStore: %v", err)
+	}
+
+	user, err := s.ResetPassword(context.Background(), "satyam@gmail.com", "NewPassw0rd")
+	if err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	if user.PasswordHash != stored {
+		t.Errorf("ResetPassword() hash = %q, stored %q", user.PasswordHash, stored)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(stored), []byte("NewPassw0rd")) != nil {
+		t.Error("stored hash does not match the new password")
+	}
+}
@@ -0,0 +1,143 @@
+// Package tracing follows requests through the api with OpenTelemetry spans, continuing
+// the W3C trace context they come with and exporting the spans over OTLP/HTTP.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"job-portal-api/internal/config"
+	"net/url"
+	"path"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// instrumentation names the tracer of the api in the spans it starts.
+const instrumentation = "job-portal-api"
+
+// Tracer starts the spans of the api, on the provider installed by Setup. Until Setup is
+// called the spans only carry on the trace context of their parent.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentation)
+}
+
+// Setup installs the tracer provider and the W3C trace context propagator. Spans are sent
+// to cfg.Endpoint when it is set. Either way every request gets a trace id, so the logs
+// of a request can be found by it. shutdown sends the spans still buffered.
+func Setup(ctx context.Context, cfg config.TracingConfig) (shutdown func(context.Context) error, err error) {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName))),
+	}
+	if cfg.Endpoint != "" {
+		exporter, err := newExporter(ctx, cfg.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return tp.Shutdown, nil
+}
+
+// newExporter sends spans to the OTLP/HTTP collector at endpoint, in plain http unless
+// the url says https.
+func newExporter(ctx context.Context, endpoint string) (*otlptrace.Exporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("parsing tracing endpoint %w", err)
+	}
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(u.Host),
+		otlptracehttp.WithURLPath(path.Join("/", u.Path, "v1/traces")),
+	}
+	if u.Scheme == "http" {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating trace exporter %w", err)
+	}
+	return exporter, nil
+}
+
+// End ends span, marking it failed when err is not nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// spanKey holds the span of a statement in its gorm instance.
+const spanKey = "tracing:span"
+
+// InstrumentDB runs every statement run through db in a span, a child of the span in the
+// context of the statement.
+func InstrumentDB(db *gorm.DB) error {
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().Before("*").Register("tracing:before_create", startStatement("create")),
+		cb.Create().After("*").Register("tracing:after_create", endStatement),
+		cb.Query().Before("*").Register("tracing:before_query", startStatement("query")),
+		cb.Query().After("*").Register("tracing:after_query", endStatement),
+		cb.Update().Before("*").Register("tracing:before_update", startStatement("update")),
+		cb.Update().After("*").Register("tracing:after_update", endStatement),
+		cb.Delete().Before("*").Register("tracing:before_delete", startStatement("delete")),
+		cb.Delete().After("*").Register("tracing:after_delete", endStatement),
+		cb.Row().Before("*").Register("tracing:before_row", startStatement("row")),
+		cb.Row().After("*").Register("tracing:after_row", endStatement),
+		cb.Raw().Before("*").Register("tracing:before_raw", startStatement("raw")),
+		cb.Raw().After("*").Register("tracing:after_raw", endStatement),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startStatement(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		name := "db." + operation
+		if db.Statement.Table != "" {
+			name += " " + db.Statement.Table
+		}
+		_, span := Tracer().Start(db.Statement.Context, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemKey.String(db.Dialector.Name())))
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+func endStatement(db *gorm.DB) {
+	v, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
+	if !ok {
+		return
+	}
+	span.SetAttributes(semconv.DBStatement(db.Statement.SQL.String()))
+	if db.Statement.Table != "" {
+		span.SetAttributes(semconv.DBSQLTable(db.Statement.Table))
+	}
+	err := db.Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil
+	}
+	End(span, err)
+}
@@ -0,0 +1,145 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"job-portal-api/internal/config"
+	"job-portal-api/internal/database"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm/logger"
+)
+
+// keepGlobals puts back the tracer provider and propagator the test replaces.
+func keepGlobals(t *testing.T) {
+	tp, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(tp)
+		otel.SetTextMapPropagator(propagator)
+	})
+}
+
+// collector is an OTLP/HTTP collector keeping the spans it is sent.
+type collector struct {
+	requests chan *coltracepb.ExportTraceServiceRequest
+}
+
+func (c collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/traces" {
+		http.NotFound(w, r)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req coltracepb.ExportTraceServiceRequest
+	err = proto.Unmarshal(body, &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.requests <- &req
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestSetup_exportsToCollector(t *testing.T) {
+	keepGlobals(t)
+	c := collector{requests: make(chan *coltracepb.ExportTraceServiceRequest, 1)}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	shutdown, err := Setup(context.Background(), config.TracingConfig{Endpoint: srv.URL, ServiceName: "jobs-test"})
+	require.NoError(t, err)
+	_, span := Tracer().Start(context.Background(), "GET /api/jobs")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	req := <-c.requests
+	require.Len(t, req.ResourceSpans, 1)
+	rs := req.ResourceSpans[0]
+	var service string
+	for _, kv := range rs.Resource.Attributes {
+		if kv.Key == "service.name" {
+			service = kv.Value.GetStringValue()
+		}
+	}
+	assert.Equal(t, "jobs-test", service)
+	require.Len(t, rs.ScopeSpans, 1)
+	require.Len(t, rs.ScopeSpans[0].Spans, 1)
+	got := rs.ScopeSpans[0].Spans[0]
+	assert.Equal(t, "GET /api/jobs", got.Name)
+	assert.Equal(t, span.SpanContext().TraceID().String(), traceID(got.TraceId))
+}
+
+// traceID reads the trace id of an exported span.
+func traceID(b []byte) string {
+	var id trace.TraceID
+	copy(id[:], b)
+	return id.String()
+}
+
+func TestSetup_withoutEndpoint(t *testing.T) {
+	keepGlobals(t)
+	shutdown, err := Setup(context.Background(), config.TracingConfig{ServiceName: "jobs-test"})
+	require.NoError(t, err)
+	defer shutdown(context.Background())
+
+	_, first := Tracer().Start(context.Background(), "first")
+	_, second := Tracer().Start(context.Background(), "second")
+	assert.True(t, first.SpanContext().IsValid(), "spans get trace ids without an exporter")
+	assert.NotEqual(t, first.SpanContext().TraceID(), second.SpanContext().TraceID())
+}
+
+func TestInstrumentDB(t *testing.T) {
+	keepGlobals(t)
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+
+	db, err := database.Open(config.DBConfig{Driver: config.DriverSQLite, Path: filepath.Join(t.TempDir(), "test.db")})
+	require.NoError(t, err)
+	db.Logger = logger.Discard
+	require.NoError(t, InstrumentDB(db))
+	type note struct {
+		ID   int
+		Text string
+	}
+	require.NoError(t, db.Exec("CREATE TABLE notes (id integer PRIMARY KEY, text text)").Error)
+
+	ctx, parent := Tracer().Start(context.Background(), "parent")
+	var notes []note
+	require.NoError(t, db.WithContext(ctx).Where("text = ?", "secret").Find(&notes).Error)
+	assert.Error(t, db.WithContext(ctx).Exec("SELECT * FROM nowhere").Error)
+	parent.End()
+
+	spans := sr.Ended()
+	require.Len(t, spans, 4)
+	query, failed := spans[1], spans[2]
+	assert.Equal(t, "db.query notes", query.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), query.Parent().SpanID())
+	attrs := map[string]string{}
+	for _, kv := range query.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	assert.Equal(t, "sqlite", attrs["db.system"])
+	assert.Equal(t, "notes", attrs["db.sql.table"])
+	assert.Equal(t, "SELECT * FROM `notes` WHERE text = ?", attrs["db.statement"])
+	assert.Equal(t, codes.Unset, query.Status().Code)
+
+	assert.Equal(t, "db.raw", failed.Name())
+	assert.Equal(t, codes.Error, failed.Status().Code)
+}
@@ -0,0 +1,189 @@
+// Package validation checks the requests of the api against the rules in their validate
+// tags and describes what is wrong field by field.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"job-portal-api/internal/apperr"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Bounds of a password, bcrypt ignores everything past 72 bytes.
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+var (
+	once     sync.Once
+	validate *validator.Validate
+)
+
+// Validator returns the validator shared by the api. It caches what it learns about every
+// struct it sees, so it must be built once and not per request.
+func Validator() *validator.Validate {
+	once.Do(func() {
+		validate = newValidator()
+	})
+	return validate
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	// Errors name fields the way clients send them.
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		for _, tag := range []string{"json", "query"} {
+			name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return f.Name
+	})
+	must(v.RegisterValidation("password", password))
+	must(v.RegisterValidation("pastyear", pastYear))
+	must(v.RegisterValidation("weburl", webURL))
+	v.RegisterAlias("country", "iso3166_1_alpha2")
+	return v
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// password asks for a mix of upper case letters, lower case letters and digits.
+func password(fl validator.FieldLevel) bool {
+	s := fl.Field().String()
+	if len(s) < MinPasswordLength || len(s) > MaxPasswordLength {
+		return false
+	}
+	var upper, lower, digit bool
+	for _, r := range s {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	return upper && lower && digit
+}
+
+// pastYear accepts the current year and the years before it.
+func pastYear(fl validator.FieldLevel) bool {
+	year := fl.Field().Int()
+	return year > 0 && year <= int64(time.Now().Year())
+}
+
+// webURL accepts absolute http and https urls.
+func webURL(fl validator.FieldLevel) bool {
+	u, err := url.Parse(fl.Field().String())
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && u.User == nil
+}
+
+// Struct checks s and returns an apperr validation error listing the fields that are not
+// valid, detail describing the whole request.
+func Struct(s interface{}, detail string) error {
+	err := Validator().Struct(s)
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+	fields := make([]apperr.FieldError, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		fields = append(fields, apperr.FieldError{Field: fieldPath(fe), Message: message(fe)})
+	}
+	return apperr.Invalid(detail, fields...)
+}
+
+// fieldPath drops the name of the struct the validation started from.
+func fieldPath(fe validator.FieldError) string {
+	_, path, ok := strings.Cut(fe.Namespace(), ".")
+	if !ok {
+		return fe.Field()
+	}
+	return path
+}
+
+func message(fe validator.FieldError) string {
+	param := fe.Param()
+	switch fe.Tag() {
+	case "required", "required_with":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "password":
+		return fmt.Sprintf("must be %d to %d characters long and contain an upper case letter, a lower case letter and a digit",
+			MinPasswordLength, MaxPasswordLength)
+	case "pastyear":
+		return "must be a year that is not in the future"
+	case "weburl", "http_url":
+		return "must be an http or https url"
+	case "country", "iso3166_1_alpha2":
+		return "must be an ISO 3166-1 alpha-2 country code"
+	case "iso4217":
+		return "must be an ISO 4217 currency code"
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(param), ", ")
+	case "unique":
+		return "must not contain duplicates"
+	case "number":
+		return "must be a number"
+	case "gtefield":
+		return "must not be less than " + snakeCase(param)
+	case "gt":
+		if fe.Type() == reflect.TypeOf(time.Time{}) {
+			return "must be in the future"
+		}
+		return "must be greater than " + param
+	case "min", "max":
+		if fe.Tag() == "min" && param == "1" && fe.Kind() != reflect.Int && fe.Kind() != reflect.Uint {
+			return "must not be empty"
+		}
+		bound := "at least"
+		if fe.Tag() == "max" {
+			bound = "at most"
+		}
+		switch fe.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("must be %s %s characters long", bound, param)
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return fmt.Sprintf("must have %s %s items", bound, param)
+		}
+		return fmt.Sprintf("must be %s %s", bound, param)
+	}
+	return fmt.Sprintf("does not satisfy the %s rule", fe.Tag())
+}
+
+// snakeCase names the field a cross field rule refers to the way fields are named in json.
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
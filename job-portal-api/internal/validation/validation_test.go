@@ -0,0 +1,105 @@
+package validation
+
+import (
+	"job-portal-api/internal/apperr"
+	"job-portal-api/internal/models"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidator_isShared(t *testing.T) {
+	assert.Same(t, Validator(), Validator())
+}
+
+func TestStruct(t *testing.T) {
+	nextYear := time.Now().Year() + 1
+	tests := []struct {
+		name       string
+		value      interface{}
+		wantFields []apperr.FieldError
+	}{
+		{
+			name:  "valid user",
+			value: models.NewUser{Name: "Asha", Email: "asha@example.com", Password: "Secret123"},
+		},
+		{
+			name:  "weak password",
+			value: models.NewUser{Name: "Asha", Email: "asha@example", Password: "secret123"},
+			wantFields: []apperr.FieldError{
+				{Field: "email", Message: "must be a valid email address"},
+				{Field: "password", Message: "must be 8 to 72 characters long and contain an upper case letter, a lower case letter and a digit"},
+			},
+		},
+		{
+			name:  "founded in the future",
+			value: models.UpdateCompany{CompanyName: "Tek", FoundedYear: nextYear, Location: "bnglr", Address: "blndr"},
+			wantFields: []apperr.FieldError{
+				{Field: "founded_year", Message: "must be a year that is not in the future"},
+			},
+		},
+		{
+			name:  "founded this year",
+			value: models.UpdateCompany{CompanyName: "Tek", FoundedYear: time.Now().Year(), Location: "bnglr", Address: "blndr"},
+		},
+		{
+			name:  "resume link is not a web url",
+			value: models.NewApplication{ResumeURL: "ftp://example.com/cv.pdf"},
+			wantFields: []apperr.FieldError{
+				{Field: "resume_url", Message: "must be an http or https url"},
+			},
+		},
+		{
+			name:  "unknown country and nested fields",
+			value: models.NewJob{Title: "Go developer", Description: "APIs", Country: "XX", Skills: []models.NewJobSkill{{Name: "go"}, {Name: ""}}},
+			wantFields: []apperr.FieldError{
+				{Field: "country", Message: "must be an ISO 3166-1 alpha-2 country code"},
+				{Field: "skills[1].name", Message: "is required"},
+			},
+		},
+		{
+			name:  "query parameters",
+			value: models.JobQuery{SalaryMin: 10, SalaryMax: 5, SalaryCurrency: "EUR"},
+			wantFields: []apperr.FieldError{
+				{Field: "salary_max", Message: "must not be less than salary_min"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Struct(tt.value, "invalid")
+			if tt.wantFields == nil {
+				require.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, apperr.ErrValidation)
+			assert.Equal(t, "invalid", apperr.Detail(err))
+			assert.Equal(t, tt.wantFields, apperr.Fields(err))
+		})
+	}
+}
+
+func Test_password(t *testing.T) {
+	long := "Aa1"
+	for len(long) <= MaxPasswordLength {
+		long += strconv.Itoa(len(long) % 10)
+	}
+	for _, tt := range []struct {
+		password string
+		want     bool
+	}{
+		{"Secret123", true},
+		{"Sécret123", true},
+		{"Sec12", false},
+		{"secret123", false},
+		{"SECRET123", false},
+		{"SecretOne", false},
+		{long, false},
+	} {
+		err := Validator().Var(tt.password, "password")
+		assert.Equal(t, tt.want, err == nil, tt.password)
+	}
+}